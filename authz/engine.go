@@ -0,0 +1,272 @@
+package authz
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/goodfoodcesi/auth-api/domain/auth"
+)
+
+// ImpersonationRuleID is the built-in rule that keeps impersonated subjects
+// to read actions, whatever the policy allows.
+const ImpersonationRuleID = "impersonation-read-only"
+
+// Subject is who is asking, as described by their access token.
+type Subject struct {
+	UserID         string   `json:"user_id"`
+	Role           string   `json:"role"`
+	RestaurantID   string   `json:"restaurant_id,omitempty"`
+	RestaurantRole string   `json:"restaurant_role,omitempty"`
+	Approved       *bool    `json:"approved,omitempty"`
+	Permissions    []string `json:"perms,omitempty"`
+	// Actor is the admin or service acting on the user's behalf, if any.
+	Actor string    `json:"actor,omitempty"`
+	Kind  auth.Kind `json:"kind,omitempty"`
+}
+
+// Impersonated tells whether an admin acts as the user.
+func (s Subject) Impersonated() bool {
+	return s.Kind == auth.KindUser && s.Actor != ""
+}
+
+type Resource struct {
+	Type       string                 `json:"type"`
+	ID         string                 `json:"id,omitempty"`
+	Attributes map[string]interface{} `json:"attributes,omitempty"`
+}
+
+type Environment struct {
+	IP   string    `json:"ip,omitempty"`
+	Time time.Time `json:"time"`
+}
+
+type Request struct {
+	Subject     Subject     `json:"subject"`
+	Action      string      `json:"action"`
+	Resource    Resource    `json:"resource"`
+	Environment Environment `json:"environment"`
+}
+
+type Decision struct {
+	Allowed bool   `json:"allowed"`
+	Effect  Effect `json:"effect"`
+	// RuleID is the rule that decided, empty when the default applied.
+	RuleID string `json:"rule_id,omitempty"`
+	Reason string `json:"reason"`
+}
+
+// DecisionLog records every decision taken by an Engine.
+type DecisionLog interface {
+	Record(request Request, decision Decision)
+}
+
+type Engine struct {
+	policy *Policy
+	log    DecisionLog
+}
+
+func NewEngine(policy *Policy, log DecisionLog) *Engine {
+	return &Engine{
+		policy: policy,
+		log:    log,
+	}
+}
+
+// Decide evaluates every rule against request: a matching deny always wins,
+// otherwise the first matching allow, otherwise the policy default. An
+// impersonated subject is denied anything but reads, whatever the rules say.
+func (e *Engine) Decide(request Request) Decision {
+	if request.Environment.Time.IsZero() {
+		request.Environment.Time = time.Now()
+	}
+
+	decision := Decision{
+		Allowed: e.policy.Default == Allow,
+		Effect:  e.policy.Default,
+		Reason:  "no rule matched",
+	}
+	for _, rule := range e.policy.Rules {
+		if !rule.matches(request) {
+			continue
+		}
+		if rule.Effect == Deny {
+			decision = Decision{Allowed: false, Effect: Deny, RuleID: rule.ID, Reason: rule.Description}
+			break
+		}
+		if decision.RuleID == "" {
+			decision = Decision{Allowed: true, Effect: Allow, RuleID: rule.ID, Reason: rule.Description}
+		}
+	}
+	if request.Subject.Impersonated() && !readAction(request.Action) {
+		decision = Decision{Allowed: false, Effect: Deny, RuleID: ImpersonationRuleID, Reason: "Impersonation tokens are read-only."}
+	}
+
+	if e.log != nil {
+		e.log.Record(request, decision)
+	}
+	return decision
+}
+
+// readAction tells whether action only looks at data, e.g. "users:read" or
+// "members:list".
+func readAction(action string) bool {
+	return strings.HasSuffix(action, ":read") || strings.HasSuffix(action, ":list")
+}
+
+func (r Rule) matches(request Request) bool {
+	if !matchAny(r.Actions, request.Action) || !matchAny(r.Resources, request.Resource.Type) {
+		return false
+	}
+	for _, condition := range r.When {
+		if !condition.holds(request) {
+			return false
+		}
+	}
+	return true
+}
+
+// matchAny supports "*" and prefix patterns such as "deliveries:*".
+func matchAny(patterns []string, value string) bool {
+	for _, pattern := range patterns {
+		if pattern == "*" || pattern == value {
+			return true
+		}
+		if prefix, ok := strings.CutSuffix(pattern, "*"); ok && strings.HasPrefix(value, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func (c Condition) holds(request Request) bool {
+	actual, present := request.attribute(c.Attr)
+
+	expected := c.Value
+	if c.Ref != "" {
+		var ok bool
+		if expected, ok = request.attribute(c.Ref); !ok {
+			// An absent reference never matches, so two missing
+			// attributes are not considered equal.
+			return c.Op == OpNe
+		}
+	}
+
+	switch c.Op {
+	case OpExists:
+		want, ok := c.Value.(bool)
+		return present == (want || !ok)
+	case OpEq:
+		return present && fmt.Sprint(actual) == fmt.Sprint(expected)
+	case OpNe:
+		return !present || fmt.Sprint(actual) != fmt.Sprint(expected)
+	case OpIn:
+		if !present {
+			return false
+		}
+		for _, candidate := range list(expected) {
+			if fmt.Sprint(actual) == fmt.Sprint(candidate) {
+				return true
+			}
+		}
+		return false
+	case OpContains:
+		if !present {
+			return false
+		}
+		for _, item := range list(actual) {
+			if fmt.Sprint(item) == fmt.Sprint(expected) {
+				return true
+			}
+		}
+		return false
+	case OpCIDR:
+		ip := net.ParseIP(fmt.Sprint(actual))
+		if !present || ip == nil {
+			return false
+		}
+		for _, block := range list(expected) {
+			if _, network, err := net.ParseCIDR(fmt.Sprint(block)); err == nil && network.Contains(ip) {
+				return true
+			}
+		}
+		return false
+	case OpGte, OpLte:
+		a, errA := strconv.ParseFloat(fmt.Sprint(actual), 64)
+		b, errB := strconv.ParseFloat(fmt.Sprint(expected), 64)
+		if !present || errA != nil || errB != nil {
+			return false
+		}
+		if c.Op == OpGte {
+			return a >= b
+		}
+		return a <= b
+	}
+	return false
+}
+
+// attribute resolves a dotted path such as "subject.role" or "env.hour".
+func (r Request) attribute(path string) (interface{}, bool) {
+	scope, name, _ := strings.Cut(path, ".")
+	switch scope {
+	case "subject":
+		switch name {
+		case "user_id":
+			return r.Subject.UserID, r.Subject.UserID != ""
+		case "role":
+			return r.Subject.Role, r.Subject.Role != ""
+		case "restaurant_id":
+			return r.Subject.RestaurantID, r.Subject.RestaurantID != ""
+		case "restaurant_role":
+			return r.Subject.RestaurantRole, r.Subject.RestaurantRole != ""
+		case "approved":
+			if r.Subject.Approved == nil {
+				return nil, false
+			}
+			return *r.Subject.Approved, true
+		case "perms":
+			return r.Subject.Permissions, true
+		case "actor":
+			return r.Subject.Actor, r.Subject.Actor != ""
+		case "kind":
+			return string(r.Subject.Kind), r.Subject.Kind != ""
+		}
+	case "resource":
+		switch name {
+		case "type":
+			return r.Resource.Type, r.Resource.Type != ""
+		case "id":
+			return r.Resource.ID, r.Resource.ID != ""
+		}
+		value, ok := r.Resource.Attributes[name]
+		return value, ok && value != nil && value != ""
+	case "env":
+		switch name {
+		case "ip":
+			return r.Environment.IP, r.Environment.IP != ""
+		case "hour":
+			return r.Environment.Time.Hour(), true
+		case "weekday":
+			return strings.ToLower(r.Environment.Time.Weekday().String()), true
+		}
+	}
+	return nil, false
+}
+
+func list(value interface{}) []interface{} {
+	switch v := value.(type) {
+	case []interface{}:
+		return v
+	case []string:
+		items := make([]interface{}, len(v))
+		for i := range v {
+			items[i] = v[i]
+		}
+		return items
+	case nil:
+		return nil
+	}
+	return []interface{}{value}
+}
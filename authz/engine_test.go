@@ -0,0 +1,168 @@
+package authz_test
+
+import (
+	"testing"
+
+	"github.com/goodfoodcesi/auth-api/authz"
+	"github.com/goodfoodcesi/auth-api/domain/auth"
+)
+
+func defaultEngine(t *testing.T) *authz.Engine {
+	t.Helper()
+
+	policy, err := authz.LoadPolicy("")
+	if err != nil {
+		t.Fatalf("LoadPolicy: %v", err)
+	}
+	return authz.NewEngine(policy, nil)
+}
+
+func TestDefaultPolicy(t *testing.T) {
+	approved, pending := true, false
+	restaurant := func(id string) authz.Resource {
+		return authz.Resource{Type: "restaurant", Attributes: map[string]interface{}{"restaurant_id": id}}
+	}
+	tests := []struct {
+		name     string
+		request  authz.Request
+		wantRule string
+		allowed  bool
+	}{
+		{
+			name:     "admin does anything",
+			request:  authz.Request{Subject: authz.Subject{UserID: "u1", Role: "admin"}, Action: "users:delete", Resource: authz.Resource{Type: "user", ID: "u2"}},
+			wantRule: "admin-all",
+			allowed:  true,
+		},
+		{
+			name:     "user reads their own record",
+			request:  authz.Request{Subject: authz.Subject{UserID: "u1", Role: "client"}, Action: "users:read", Resource: authz.Resource{Type: "user", ID: "u1"}},
+			wantRule: "user-own-record",
+			allowed:  true,
+		},
+		{
+			name:    "user reads someone else's record",
+			request: authz.Request{Subject: authz.Subject{UserID: "u1", Role: "client"}, Action: "users:read", Resource: authz.Resource{Type: "user", ID: "u2"}},
+		},
+		{
+			name:    "user deletes their own record",
+			request: authz.Request{Subject: authz.Subject{UserID: "u1", Role: "client"}, Action: "users:delete", Resource: authz.Resource{Type: "user", ID: "u1"}},
+		},
+		{
+			name:     "manager manages the members of their restaurant",
+			request:  authz.Request{Subject: authz.Subject{UserID: "u1", Role: "manager", RestaurantID: "r1"}, Action: "members:manage", Resource: restaurant("r1")},
+			wantRule: "manager-own-restaurant",
+			allowed:  true,
+		},
+		{
+			name:    "manager manages the members of another restaurant",
+			request: authz.Request{Subject: authz.Subject{UserID: "u1", Role: "manager", RestaurantID: "r1"}, Action: "members:manage", Resource: restaurant("r2")},
+		},
+		{
+			name:    "unscoped manager against a restaurant without id",
+			request: authz.Request{Subject: authz.Subject{UserID: "u1", Role: "manager"}, Action: "restaurant:update", Resource: authz.Resource{Type: "restaurant"}},
+		},
+		{
+			name:    "client acts for a restaurant",
+			request: authz.Request{Subject: authz.Subject{UserID: "u1", Role: "client", RestaurantID: "r1"}, Action: "restaurant:update", Resource: restaurant("r1")},
+		},
+		{
+			name:     "approved driver accepts a delivery",
+			request:  authz.Request{Subject: authz.Subject{UserID: "u1", Role: "driver", Approved: &approved, Permissions: []string{"deliveries:accept"}}, Action: "deliveries:accept", Resource: authz.Resource{Type: "delivery"}},
+			wantRule: "driver-deliveries",
+			allowed:  true,
+		},
+		{
+			name:     "pending driver accepts a delivery",
+			request:  authz.Request{Subject: authz.Subject{UserID: "u1", Role: "driver", Approved: &pending, Permissions: []string{"deliveries:accept"}}, Action: "deliveries:accept", Resource: authz.Resource{Type: "delivery"}},
+			wantRule: "driver-must-be-approved",
+		},
+		{
+			name:     "impersonated manager lists the members of their restaurant",
+			request:  authz.Request{Subject: authz.Subject{UserID: "u1", Role: "manager", RestaurantID: "r1", Actor: "admin", Kind: auth.KindUser}, Action: "members:list", Resource: restaurant("r1")},
+			wantRule: "manager-own-restaurant",
+			allowed:  true,
+		},
+		{
+			name:     "impersonated manager manages the members of their restaurant",
+			request:  authz.Request{Subject: authz.Subject{UserID: "u1", Role: "manager", RestaurantID: "r1", Actor: "admin", Kind: auth.KindUser}, Action: "members:manage", Resource: restaurant("r1")},
+			wantRule: authz.ImpersonationRuleID,
+		},
+		{
+			name:     "impersonated admin deletes a user",
+			request:  authz.Request{Subject: authz.Subject{UserID: "u1", Role: "admin", Actor: "admin", Kind: auth.KindUser}, Action: "users:delete", Resource: authz.Resource{Type: "user", ID: "u2"}},
+			wantRule: authz.ImpersonationRuleID,
+		},
+		{
+			name:     "service acting for a manager",
+			request:  authz.Request{Subject: authz.Subject{UserID: "u1", Role: "manager", RestaurantID: "r1", Actor: "order-api", Kind: auth.KindService}, Action: "orders:refund", Resource: authz.Resource{Type: "order", Attributes: map[string]interface{}{"restaurant_id": "r1"}}},
+			wantRule: "manager-own-restaurant",
+			allowed:  true,
+		},
+		{
+			name:     "driver whose token does not say",
+			request:  authz.Request{Subject: authz.Subject{UserID: "u1", Role: "driver", Permissions: []string{"deliveries:accept"}}, Action: "deliveries:accept", Resource: authz.Resource{Type: "delivery"}},
+			wantRule: "driver-must-be-approved",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			decision := defaultEngine(t).Decide(tt.request)
+
+			if decision.Allowed != tt.allowed || decision.RuleID != tt.wantRule {
+				t.Errorf("decision = %+v, want allowed %t by %q", decision, tt.allowed, tt.wantRule)
+			}
+		})
+	}
+}
+
+func TestDenyWinsOverAllow(t *testing.T) {
+	policy, err := authz.ParsePolicy([]byte(`
+default: deny
+rules:
+  - id: everyone
+    effect: allow
+    actions: ["*"]
+    resources: ["*"]
+  - id: blocked-network
+    effect: deny
+    actions: ["*"]
+    resources: ["*"]
+    when:
+      - { attr: env.ip, op: cidr, value: ["192.0.2.0/24"] }
+`))
+	if err != nil {
+		t.Fatalf("ParsePolicy: %v", err)
+	}
+	engine := authz.NewEngine(policy, nil)
+
+	tests := []struct {
+		ip       string
+		allowed  bool
+		wantRule string
+	}{
+		{ip: "198.51.100.7", allowed: true, wantRule: "everyone"},
+		{ip: "192.0.2.10", wantRule: "blocked-network"},
+	}
+	for _, tt := range tests {
+		decision := engine.Decide(authz.Request{Action: "users:read", Resource: authz.Resource{Type: "user"}, Environment: authz.Environment{IP: tt.ip}})
+		if decision.Allowed != tt.allowed || decision.RuleID != tt.wantRule {
+			t.Errorf("from %s: decision = %+v, want allowed %t by %q", tt.ip, decision, tt.allowed, tt.wantRule)
+		}
+	}
+}
+
+func TestParsePolicyRejectsInvalidPolicies(t *testing.T) {
+	tests := map[string]string{
+		"unknown effect":   "default: deny\nrules:\n  - { id: r, effect: maybe, actions: [\"*\"], resources: [\"*\"] }\n",
+		"unknown operator": "default: deny\nrules:\n  - { id: r, effect: allow, actions: [\"*\"], resources: [\"*\"], when: [{ attr: subject.role, op: like, value: a }] }\n",
+		"malformed yaml":   "default: [deny\n",
+	}
+	for name, policy := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := authz.ParsePolicy([]byte(policy)); err == nil {
+				t.Error("ParsePolicy accepted the policy")
+			}
+		})
+	}
+}
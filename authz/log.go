@@ -0,0 +1,26 @@
+package authz
+
+import "go.uber.org/zap"
+
+// ZapDecisionLog writes decisions as structured log lines, so they can be
+// searched next to the rest of the service logs.
+type ZapDecisionLog struct {
+	logger *zap.Logger
+}
+
+func NewZapDecisionLog(logger *zap.Logger) *ZapDecisionLog {
+	return &ZapDecisionLog{logger: logger.Named("authz")}
+}
+
+func (l *ZapDecisionLog) Record(request Request, decision Decision) {
+	l.logger.Info("authorization decision",
+		zap.Bool("allowed", decision.Allowed),
+		zap.String("rule_id", decision.RuleID),
+		zap.String("action", request.Action),
+		zap.String("resource_type", request.Resource.Type),
+		zap.String("resource_id", request.Resource.ID),
+		zap.String("subject_id", request.Subject.UserID),
+		zap.String("subject_role", request.Subject.Role),
+		zap.String("ip", request.Environment.IP),
+	)
+}
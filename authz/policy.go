@@ -0,0 +1,113 @@
+// Package authz is a small attribute-based policy engine. Policies are
+// declared in YAML and evaluated against the subject (token claims), the
+// action, the resource and the environment of a request.
+package authz
+
+import (
+	_ "embed"
+	"fmt"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+type Effect string
+
+const (
+	Allow Effect = "allow"
+	Deny  Effect = "deny"
+)
+
+// Operators supported in rule conditions.
+const (
+	OpEq       = "eq"
+	OpNe       = "ne"
+	OpIn       = "in"
+	OpContains = "contains"
+	OpCIDR     = "cidr"
+	OpGte      = "gte"
+	OpLte      = "lte"
+	OpExists   = "exists"
+)
+
+//go:embed policies.yaml
+var defaultPolicy []byte
+
+// Policy is the content of a policy file. Rules are not ordered: any matching
+// deny wins over allows, and nothing matching means the default applies.
+type Policy struct {
+	Default Effect `yaml:"default"`
+	Rules   []Rule `yaml:"rules"`
+}
+
+type Rule struct {
+	ID          string      `yaml:"id"`
+	Description string      `yaml:"description"`
+	Effect      Effect      `yaml:"effect"`
+	Actions     []string    `yaml:"actions"`
+	Resources   []string    `yaml:"resources"`
+	When        []Condition `yaml:"when"`
+}
+
+// Condition compares the attribute at Attr, e.g. "subject.role", either with
+// the literal Value or with the attribute at Ref.
+type Condition struct {
+	Attr  string      `yaml:"attr"`
+	Op    string      `yaml:"op"`
+	Value interface{} `yaml:"value"`
+	Ref   string      `yaml:"ref"`
+}
+
+// LoadPolicy reads the policy at path, or the built-in one when path is empty.
+func LoadPolicy(path string) (*Policy, error) {
+	data := defaultPolicy
+	if path != "" {
+		var err error
+		if data, err = os.ReadFile(path); err != nil {
+			return nil, err
+		}
+	}
+	return ParsePolicy(data)
+}
+
+func ParsePolicy(data []byte) (*Policy, error) {
+	var policy Policy
+	if err := yaml.Unmarshal(data, &policy); err != nil {
+		return nil, err
+	}
+	if policy.Default == "" {
+		policy.Default = Deny
+	}
+	if err := policy.validate(); err != nil {
+		return nil, err
+	}
+	return &policy, nil
+}
+
+func (p *Policy) validate() error {
+	if p.Default != Allow && p.Default != Deny {
+		return fmt.Errorf("authz: invalid default effect %q", p.Default)
+	}
+	for _, rule := range p.Rules {
+		if rule.ID == "" {
+			return fmt.Errorf("authz: rule without id")
+		}
+		if rule.Effect != Allow && rule.Effect != Deny {
+			return fmt.Errorf("authz: rule %s has invalid effect %q", rule.ID, rule.Effect)
+		}
+		if len(rule.Actions) == 0 || len(rule.Resources) == 0 {
+			return fmt.Errorf("authz: rule %s must list actions and resources", rule.ID)
+		}
+		for _, condition := range rule.When {
+			switch condition.Op {
+			case OpEq, OpNe, OpIn, OpContains, OpCIDR, OpGte, OpLte, OpExists:
+			default:
+				return fmt.Errorf("authz: rule %s has unknown operator %q", rule.ID, condition.Op)
+			}
+			if condition.Attr == "" {
+				return fmt.Errorf("authz: rule %s has a condition without attr", rule.ID)
+			}
+		}
+	}
+	return nil
+}
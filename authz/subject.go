@@ -0,0 +1,17 @@
+package authz
+
+import "github.com/goodfoodcesi/auth-api/domain/auth"
+
+// SubjectFromPrincipal describes the authenticated caller of a request.
+func SubjectFromPrincipal(p *auth.Principal) Subject {
+	return Subject{
+		UserID:         p.Subject,
+		Role:           string(p.Role),
+		RestaurantID:   p.Tenant,
+		RestaurantRole: string(p.TenantRole),
+		Approved:       p.Approved,
+		Permissions:    p.Permissions,
+		Actor:          p.Actor,
+		Kind:           p.Kind,
+	}
+}
@@ -1,18 +1,28 @@
 package main
 
 import (
+	"context"
 	"log"
 	"os"
 	"os/signal"
+	"strconv"
 	"syscall"
+	"time"
 
+	"github.com/goodfoodcesi/auth-api/domain/events"
+	"github.com/goodfoodcesi/auth-api/domain/messaging"
+	"github.com/goodfoodcesi/auth-api/domain/service"
 	"github.com/goodfoodcesi/auth-api/infrastructure/database"
+	"github.com/goodfoodcesi/auth-api/infrastructure/database/repository"
 	"github.com/goodfoodcesi/auth-api/infrastructure/logger"
 	"github.com/goodfoodcesi/auth-api/infrastructure/messaging/consumer"
 	"github.com/goodfoodcesi/auth-api/infrastructure/messaging/rabbitmq"
 	"go.uber.org/zap"
 )
 
+// drainTimeout bounds how long in-flight messages are waited for on shutdown.
+const drainTimeout = 30 * time.Second
+
 func main() {
 
 	logger, err := logger.NewLogger()
@@ -40,7 +50,7 @@ func main() {
 	}
 	defer db.Close()
 
-	rabbit, err := rabbitmq.NewRabbitMQ(os.Getenv("RABBITMQ_URL"), logger)
+	rabbit, err := rabbitmq.NewRabbitMQ(os.Getenv("RABBITMQ_URL"), events.Schemas, logger)
 	if err != nil {
 		log.Fatal(err)
 	}
@@ -49,19 +59,68 @@ func main() {
 	stop := make(chan os.Signal, 1)
 	signal.Notify(stop, syscall.SIGINT, syscall.SIGTERM)
 
+	if err := rabbit.DeclareTopology(rabbitmq.ConsumerTopology); err != nil {
+		logger.Fatal("failed to declare consumer topology", zap.Error(err))
+	}
+
+	userRepo := repository.NewUserRepository(db)
+	driverApplicationRepo := repository.NewDriverApplicationRepository(db)
+	membershipRepo := repository.NewMembershipRepository(db)
+	outboxRepo := repository.NewOutboxRepository(db)
+	transactor := repository.NewTransactor(db)
+	accountSyncService := service.NewAccountSyncService(userRepo, driverApplicationRepo, membershipRepo, outboxRepo, transactor, logger)
+
 	userConsumer := consumer.NewUserConsumer(logger)
+	accountConsumer := consumer.NewAccountConsumer(accountSyncService, logger)
 
-	go func() {
-		err = rabbit.Consume(rabbitmq.ClientCreatedQueueClientAPI, userConsumer.HandleUserCreated)
-		if err != nil {
-			logger.Error("failed to consume user created event", zap.Error(err))
-			stop <- syscall.SIGTERM
-			return
+	inbox := service.NewInbox(repository.NewProcessedMessageRepository(db), transactor, logger)
+	cleanupCtx, stopCleanup := context.WithCancel(context.Background())
+	defer stopCleanup()
+	go inbox.RunCleanup(cleanupCtx)
+
+	options := messaging.ConsumeOptions{
+		Workers:  envInt("CONSUMER_WORKERS", 4),
+		Prefetch: envInt("CONSUMER_PREFETCH", 0),
+		Retry:    messaging.DefaultRetryPolicy,
+		Inbox:    inbox,
+	}
+	consumers := []struct {
+		name    string
+		queue   string
+		handler messaging.Handler
+	}{
+		{"client-api.user-created", rabbitmq.ClientCreatedQueueClientAPI, userConsumer.HandleUserCreated},
+		{"auth-api.restaurant-deleted", rabbitmq.RestaurantDeletedQueueAuthAPI, accountConsumer.HandleRestaurantDeleted},
+		{"auth-api.customer-fraud-flagged", rabbitmq.CustomerFraudFlaggedQueueAuthAPI, accountConsumer.HandleFraudFlagged},
+		{"auth-api.driver-contract-ended", rabbitmq.DriverContractEndedQueueAuthAPI, accountConsumer.HandleDriverContractEnded},
+	}
+	for _, c := range consumers {
+		options.Name = c.name
+		if err := rabbit.Consume(c.queue, options, c.handler); err != nil {
+			logger.Fatal("failed to consume queue", zap.String("queue", c.queue), zap.Error(err))
 		}
-	}()
+	}
 
-	logger.Info("Consumer started successfully")
+	logger.Info("Consumer started successfully", zap.Int("workers", options.Workers))
 
 	<-stop
 	logger.Info("Shutting down consumer...")
+
+	// Leave the messages being handled time to finish before closing.
+	ctx, cancel := context.WithTimeout(context.Background(), drainTimeout)
+	defer cancel()
+	if err := rabbit.Shutdown(ctx); err != nil {
+		logger.Warn("consumers did not drain in time", zap.Error(err))
+	}
+
+	logger.Info("Consumer stopped")
+}
+
+// envInt reads a positive integer from the environment.
+func envInt(name string, fallback int) int {
+	value, err := strconv.Atoi(os.Getenv(name))
+	if err != nil || value <= 0 {
+		return fallback
+	}
+	return value
 }
@@ -0,0 +1,124 @@
+// Command dlq inspects the dead-letter queue of a consumed queue and moves
+// its messages back to it or drops them.
+//
+//	dlq list <queue> [-limit 20]
+//	dlq show <queue> <message-id>
+//	dlq requeue <queue> [message-id]...
+//	dlq purge <queue>
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/goodfoodcesi/auth-api/domain/events"
+	"github.com/goodfoodcesi/auth-api/infrastructure/messaging/rabbitmq"
+	"go.uber.org/zap"
+)
+
+const showLimit = 10000
+
+func main() {
+	if len(os.Args) < 3 {
+		usage()
+	}
+	queue := os.Args[2]
+
+	rabbit, err := rabbitmq.NewRabbitMQ(os.Getenv("RABBITMQ_URL"), events.Schemas, zap.NewNop())
+	if err != nil {
+		log.Fatal("Failed to connect to RabbitMQ: ", err)
+	}
+	defer rabbit.Close()
+
+	switch os.Args[1] {
+	case "list":
+		flags := flag.NewFlagSet("list", flag.ExitOnError)
+		limit := flags.Int("limit", 20, "maximum number of messages")
+		//nolint:errcheck
+		flags.Parse(os.Args[3:])
+
+		deadLetters, err := rabbit.DeadLetters(queue, *limit)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(w, "MESSAGE ID\tTYPE\tATTEMPTS\tDEAD LETTERED\tREASON")
+		for _, d := range deadLetters {
+			fmt.Fprintf(w, "%s\t%s\t%d\t%s\t%s\n",
+				d.MessageID, d.Type, d.Attempts, d.DeadLetteredAt.Format(time.RFC3339), d.Reason)
+		}
+		w.Flush()
+
+	case "show":
+		if len(os.Args) < 4 {
+			usage()
+		}
+		deadLetters, err := rabbit.DeadLetters(queue, showLimit)
+		if err != nil {
+			log.Fatal(err)
+		}
+		for _, d := range deadLetters {
+			if d.MessageID != os.Args[3] {
+				continue
+			}
+			body := json.RawMessage(d.Body)
+			if !json.Valid(body) {
+				body, _ = json.Marshal(string(d.Body))
+			}
+			out, err := json.MarshalIndent(struct {
+				MessageID      string                 `json:"message_id"`
+				Type           string                 `json:"type"`
+				Queue          string                 `json:"queue"`
+				Attempts       int                    `json:"attempts"`
+				Reason         string                 `json:"reason"`
+				DeadLetteredAt time.Time              `json:"dead_lettered_at"`
+				Headers        map[string]interface{} `json:"headers"`
+				Body           json.RawMessage        `json:"body"`
+			}{
+				MessageID:      d.MessageID,
+				Type:           d.Type,
+				Queue:          d.Queue,
+				Attempts:       d.Attempts,
+				Reason:         d.Reason,
+				DeadLetteredAt: d.DeadLetteredAt,
+				Headers:        d.Headers,
+				Body:           body,
+			}, "", "  ")
+			if err != nil {
+				log.Fatal(err)
+			}
+			fmt.Println(string(out))
+			return
+		}
+		log.Fatal("No dead letter with message ID ", os.Args[3])
+
+	case "requeue":
+		requeued, err := rabbit.RequeueDeadLetters(context.Background(), queue, os.Args[3:])
+		fmt.Println("requeued", requeued)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+	case "purge":
+		purged, err := rabbit.PurgeDeadLetters(queue)
+		if err != nil {
+			log.Fatal(err)
+		}
+		fmt.Println("purged", purged)
+
+	default:
+		usage()
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: dlq list <queue> [-limit n] | show <queue> <message-id> | requeue <queue> [message-id]... | purge <queue>")
+	os.Exit(2)
+}
@@ -0,0 +1,157 @@
+// Command outbox inspects the outbox and queues messages to be published
+// again.
+//
+//	outbox list [-status pending|failed|sent] [-limit 50]
+//	outbox show <id>
+//	outbox replay <id>...
+//	outbox lag
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/goodfoodcesi/auth-api/infrastructure/database"
+	"github.com/goodfoodcesi/auth-api/infrastructure/database/repository"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/zap"
+)
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+	}
+
+	dbConfig := database.Config{
+		Host:     os.Getenv("POSTGRES_HOST"),
+		Port:     os.Getenv("POSTGRES_PORT"),
+		User:     os.Getenv("POSTGRES_USER"),
+		Password: os.Getenv("POSTGRES_PASSWORD"),
+		DBName:   os.Getenv("POSTGRES_DB"),
+	}
+
+	dbConn, err := database.NewPostgresPool(dbConfig, zap.NewNop())
+	if err != nil {
+		log.Fatal("Failed to connect to database: ", err)
+	}
+	defer dbConn.Close()
+
+	repo := repository.NewOutboxRepository(dbConn)
+	ctx := context.Background()
+
+	switch os.Args[1] {
+	case "list":
+		flags := flag.NewFlagSet("list", flag.ExitOnError)
+		status := flags.String("status", "pending", "pending, failed, sent or empty for all")
+		limit := flags.Int("limit", 50, "maximum number of messages")
+		//nolint:errcheck
+		flags.Parse(os.Args[2:])
+
+		messages, err := repo.List(ctx, *status, int32(*limit))
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(w, "ID\tEVENT\tAGGREGATE\tATTEMPTS\tCREATED\tSENT\tLAST ERROR")
+		for _, m := range messages {
+			sent := "-"
+			if m.SentAt.Valid {
+				sent = m.SentAt.Time.Format(time.RFC3339)
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s/%s\t%d\t%s\t%s\t%s\n",
+				m.ID.String(), m.EventType, m.AggregateType, m.AggregateID, m.Attempts,
+				m.CreatedAt.Time.Format(time.RFC3339), sent, m.LastError.String)
+		}
+		w.Flush()
+
+	case "show":
+		message, err := repo.Get(ctx, parseID(2))
+		if err != nil {
+			log.Fatal(err)
+		}
+		out, err := json.MarshalIndent(struct {
+			ID          string          `json:"id"`
+			Aggregate   string          `json:"aggregate"`
+			EventType   string          `json:"event_type"`
+			Exchange    string          `json:"exchange"`
+			RoutingKey  string          `json:"routing_key"`
+			Attempts    int32           `json:"attempts"`
+			LastError   string          `json:"last_error,omitempty"`
+			AvailableAt time.Time       `json:"available_at"`
+			CreatedAt   time.Time       `json:"created_at"`
+			SentAt      *time.Time      `json:"sent_at,omitempty"`
+			Payload     json.RawMessage `json:"payload"`
+		}{
+			ID:          message.ID.String(),
+			Aggregate:   message.AggregateType + "/" + message.AggregateID,
+			EventType:   message.EventType,
+			Exchange:    message.Exchange,
+			RoutingKey:  message.RoutingKey,
+			Attempts:    message.Attempts,
+			LastError:   message.LastError.String,
+			AvailableAt: message.AvailableAt.Time,
+			CreatedAt:   message.CreatedAt.Time,
+			SentAt:      timePtr(message.SentAt),
+			Payload:     message.Payload,
+		}, "", "  ")
+		if err != nil {
+			log.Fatal(err)
+		}
+		fmt.Println(string(out))
+
+	case "replay":
+		if len(os.Args) < 3 {
+			usage()
+		}
+		for i := 2; i < len(os.Args); i++ {
+			if err := repo.Replay(ctx, parseID(i)); err != nil {
+				log.Fatalf("%s: %v", os.Args[i], err)
+			}
+			fmt.Println("queued", os.Args[i])
+		}
+
+	case "lag":
+		pending, oldest, err := repo.Lag(ctx)
+		if err != nil {
+			log.Fatal(err)
+		}
+		fmt.Println("pending:", pending)
+		if !oldest.IsZero() {
+			fmt.Println("oldest:", oldest.Format(time.RFC3339), "("+time.Since(oldest).Round(time.Second).String()+" ago)")
+		}
+
+	default:
+		usage()
+	}
+}
+
+func parseID(arg int) pgtype.UUID {
+	if len(os.Args) <= arg {
+		usage()
+	}
+	id, err := uuid.Parse(os.Args[arg])
+	if err != nil {
+		log.Fatal("Invalid message ID: ", err)
+	}
+	return pgtype.UUID{Bytes: id, Valid: true}
+}
+
+func timePtr(t pgtype.Timestamptz) *time.Time {
+	if !t.Valid {
+		return nil
+	}
+	return &t.Time
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: outbox list [-status pending|failed|sent] [-limit n] | show <id> | replay <id>... | lag")
+	os.Exit(2)
+}
@@ -0,0 +1,49 @@
+package crypto
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"strings"
+)
+
+// APIKeyPrefix starts every API key so leaked keys are easy to spot in logs
+// and by secret scanners.
+const APIKeyPrefix = "gf"
+
+// GenerateAPIKey returns a key of the form gf_<prefix>_<secret>. The prefix
+// identifies the key and may be shown, only the hash of the secret is stored.
+func GenerateAPIKey() (key, prefix, secret string, err error) {
+	id := make([]byte, 6)
+	if _, err := rand.Read(id); err != nil {
+		return "", "", "", err
+	}
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return "", "", "", err
+	}
+
+	prefix = hex.EncodeToString(id)
+	secret = base64.RawURLEncoding.EncodeToString(raw)
+	return APIKeyPrefix + "_" + prefix + "_" + secret, prefix, secret, nil
+}
+
+// ParseAPIKey splits a key produced by GenerateAPIKey.
+func ParseAPIKey(key string) (prefix, secret string, ok bool) {
+	parts := strings.SplitN(key, "_", 3)
+	if len(parts) != 3 || parts[0] != APIKeyPrefix || parts[1] == "" || parts[2] == "" {
+		return "", "", false
+	}
+	return parts[1], parts[2], true
+}
+
+func HashAPIKeySecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+func CompareAPIKeySecret(hash, secret string) bool {
+	return subtle.ConstantTimeCompare([]byte(hash), []byte(HashAPIKeySecret(secret))) == 1
+}
@@ -0,0 +1,103 @@
+// Package auth describes who an authenticated request acts for, whatever the
+// way it proved its identity.
+package auth
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	db "github.com/goodfoodcesi/auth-api/infrastructure/database/sqlc"
+	"github.com/goodfoodcesi/auth-api/infrastructure/jwt"
+)
+
+// Kind tells how a principal authenticated.
+type Kind string
+
+const (
+	// KindUser is a user signed in with an access token issued by this API.
+	KindUser Kind = "user"
+	// KindService is another service holding a token exchanged on behalf of
+	// a user.
+	KindService Kind = "service"
+	// KindAPIKey is a partner or automation calling with a restaurant API key.
+	KindAPIKey Kind = "apikey"
+)
+
+// Principal is the authenticated caller of a request.
+type Principal struct {
+	// Subject is the id of the user the request acts for. API keys act for
+	// the manager who created them.
+	Subject     string
+	Kind        Kind
+	Role        db.UserRole
+	Permissions []string
+	// SessionID identifies the token pair the request came with, or the API
+	// key for KindAPIKey.
+	SessionID string
+	// Actor is the admin or service acting on the subject's behalf, empty
+	// unless the token is an impersonation or exchanged token.
+	Actor string
+	// Tenant is the restaurant the request is scoped to, if any.
+	Tenant     string
+	TenantRole db.MembershipRole
+	// Approved is only set for drivers.
+	Approved *bool
+	// IssuedAt is when the token was signed, zero for API keys.
+	IssuedAt time.Time
+}
+
+// FromClaims builds the principal of a validated token.
+func FromClaims(claims *jwt.Claims) *Principal {
+	p := &Principal{
+		Subject:     claims.UserID,
+		Kind:        KindUser,
+		Role:        claims.Role,
+		Permissions: claims.Permissions,
+		SessionID:   claims.SessionID,
+		Tenant:      claims.RestaurantID,
+		TenantRole:  claims.RestaurantRole,
+		Approved:    claims.Approved,
+	}
+	if claims.IssuedAt != nil {
+		p.IssuedAt = claims.IssuedAt.Time
+	}
+	if claims.Actor != nil {
+		p.Actor = claims.Actor.Subject
+	}
+	if len(claims.Audience) > 0 && !slices.Contains(claims.Audience, jwt.DefaultAudience) {
+		p.Kind = KindService
+	}
+	return p
+}
+
+func (p *Principal) HasPermission(permission string) bool {
+	return slices.Contains(p.Permissions, permission)
+}
+
+// Impersonated tells whether an admin is acting as the subject.
+func (p *Principal) Impersonated() bool {
+	return p.Kind == KindUser && p.Actor != ""
+}
+
+type contextKey struct{}
+
+func NewContext(ctx context.Context, p *Principal) context.Context {
+	return context.WithValue(ctx, contextKey{}, p)
+}
+
+// FromContext returns the principal AuthMiddleware stored in ctx.
+func FromContext(ctx context.Context) (*Principal, bool) {
+	p, ok := ctx.Value(contextKey{}).(*Principal)
+	return p, ok && p != nil
+}
+
+// MustFromContext is FromContext for code that only runs behind
+// AuthMiddleware. It panics when the middleware was not mounted.
+func MustFromContext(ctx context.Context) *Principal {
+	p, ok := FromContext(ctx)
+	if !ok {
+		panic("auth: no principal in context, is AuthMiddleware mounted?")
+	}
+	return p
+}
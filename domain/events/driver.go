@@ -0,0 +1,22 @@
+package events
+
+import "time"
+
+// DriverApproved is sent once a driver's documents have been checked, after
+// which they may take deliveries.
+type DriverApproved struct {
+	UserID        string    `json:"user_id" validate:"required"`
+	ApplicationID string    `json:"application_id" validate:"required"`
+	ReviewedBy    string    `json:"reviewed_by" validate:"required"`
+	OccurredAt    time.Time `json:"occurred_at" validate:"required"`
+}
+
+// DriverRejected is sent when a driver application is turned down. The notes
+// explain to the driver what to fix before resubmitting.
+type DriverRejected struct {
+	UserID        string    `json:"user_id" validate:"required"`
+	ApplicationID string    `json:"application_id" validate:"required"`
+	ReviewedBy    string    `json:"reviewed_by" validate:"required"`
+	Notes         string    `json:"notes"`
+	OccurredAt    time.Time `json:"occurred_at" validate:"required"`
+}
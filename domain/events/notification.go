@@ -0,0 +1,11 @@
+package events
+
+import "time"
+
+// SmsOtpRequested asks notification-api to text a login code to a phone number.
+type SmsOtpRequested struct {
+	PhoneNumber string    `json:"phone_number" validate:"required"`
+	Code        string    `json:"code" validate:"required"`
+	Locale      string    `json:"locale"`
+	ExpiresAt   time.Time `json:"expires_at" validate:"required"`
+}
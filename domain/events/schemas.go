@@ -0,0 +1,27 @@
+package events
+
+import (
+	"github.com/goodfoodcesi/auth-api/infrastructure/messaging/rabbitmq"
+	"github.com/goodfoodcesi/auth-api/infrastructure/messaging/schema"
+)
+
+// Schemas lists every event auth-api publishes or consumes. A change that breaks
+// consumers registers a new version instead of editing the existing one.
+var Schemas = schema.NewRegistry()
+
+func init() {
+	Schemas.Register(rabbitmq.UserCreatedKey, 1, UserCreated{})
+	Schemas.Register(rabbitmq.UserUpdatedKey, 1, UserUpdated{})
+	Schemas.Register(rabbitmq.UserDeletedKey, 1, UserDeleted{})
+	Schemas.Register(rabbitmq.UserRoleChangedKey, 1, UserRoleChanged{})
+	Schemas.Register(rabbitmq.UserSuspendedKey, 1, UserSuspended{})
+	Schemas.Register(rabbitmq.UserReactivatedKey, 1, UserReactivated{})
+	Schemas.Register(rabbitmq.UserPasswordChangedKey, 1, UserPasswordChanged{})
+	Schemas.Register(rabbitmq.UserLoggedInKey, 1, UserLoggedIn{})
+	Schemas.Register(rabbitmq.SmsOtpKey, 1, SmsOtpRequested{})
+	Schemas.Register(rabbitmq.DriverApprovedKey, 1, DriverApproved{})
+	Schemas.Register(rabbitmq.DriverRejectedKey, 1, DriverRejected{})
+	Schemas.Register(rabbitmq.RestaurantDeletedKey, 1, RestaurantDeleted{})
+	Schemas.Register(rabbitmq.CustomerFraudFlaggedKey, 1, CustomerFraudFlagged{})
+	Schemas.Register(rabbitmq.DriverContractEndedKey, 1, DriverContractEnded{})
+}
@@ -0,0 +1,26 @@
+package events
+
+import "time"
+
+// RestaurantDeleted is sent by restaurant-api once a restaurant is closed for
+// good. Its staff lose access to it.
+type RestaurantDeleted struct {
+	RestaurantID string    `json:"restaurant_id" validate:"required"`
+	OccurredAt   time.Time `json:"occurred_at" validate:"required"`
+}
+
+// CustomerFraudFlagged is sent by payment-api when a customer's payments look
+// fraudulent. The account is suspended until an admin looks into it.
+type CustomerFraudFlagged struct {
+	UserID     string    `json:"user_id" validate:"required"`
+	Reason     string    `json:"reason"`
+	OccurredAt time.Time `json:"occurred_at" validate:"required"`
+}
+
+// DriverContractEnded is sent by delivery-api when a driver stops working
+// with GoodFood. They may no longer take deliveries.
+type DriverContractEnded struct {
+	UserID     string    `json:"user_id" validate:"required"`
+	Reason     string    `json:"reason"`
+	OccurredAt time.Time `json:"occurred_at" validate:"required"`
+}
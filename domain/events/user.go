@@ -0,0 +1,96 @@
+package events
+
+import (
+	"time"
+
+	"github.com/goodfoodcesi/api-utils-go/pkg/event"
+	"github.com/goodfoodcesi/auth-api/infrastructure/database/sqlc"
+)
+
+// UserProfile carries the profile fields owned by auth-api that are not part
+// of the shared api-utils-go event definitions.
+type UserProfile struct {
+	PhoneNumber   string `json:"phone_number,omitempty"`
+	PhoneVerified bool   `json:"phone_verified"`
+	Locale        string `json:"locale"`
+	Timezone      string `json:"timezone"`
+	AvatarURL     string `json:"avatar_url,omitempty"`
+}
+
+// UserCreated is wire compatible with event.UserCreatedEvent: the profile
+// fields are only added next to the shared ones.
+type UserCreated struct {
+	event.UserCreatedEvent
+	UserProfile
+}
+
+func NewUserProfile(user *db.User) UserProfile {
+	return UserProfile{
+		PhoneNumber:   user.PhoneNumber.String,
+		PhoneVerified: user.PhoneVerifiedAt.Valid,
+		Locale:        user.Locale,
+		Timezone:      user.Timezone,
+		AvatarURL:     user.AvatarUrl.String,
+	}
+}
+
+// UserSuspended is sent when an active account is suspended or banned, so
+// open orders can be cancelled.
+type UserSuspended struct {
+	UserID     string    `json:"user_id" validate:"required"`
+	Status     string    `json:"status" validate:"required"`
+	Reason     string    `json:"reason,omitempty"`
+	ChangedBy  string    `json:"changed_by,omitempty"`
+	OccurredAt time.Time `json:"occurred_at" validate:"required"`
+}
+
+// UserReactivated is sent when an account goes back to the active status.
+type UserReactivated struct {
+	UserID     string    `json:"user_id" validate:"required"`
+	ChangedBy  string    `json:"changed_by,omitempty"`
+	OccurredAt time.Time `json:"occurred_at" validate:"required"`
+}
+
+// UserUpdated carries the profile as it is after the user edited it.
+type UserUpdated struct {
+	UserID     string    `json:"user_id" validate:"required"`
+	FirstName  string    `json:"first_name"`
+	LastName   string    `json:"last_name"`
+	Email      string    `json:"email"`
+	Version    int32     `json:"version" validate:"required"`
+	OccurredAt time.Time `json:"occurred_at" validate:"required"`
+	UserProfile
+}
+
+// UserDeleted is sent when an account is deleted, whatever its previous
+// status. Services holding personal data should erase it.
+type UserDeleted struct {
+	UserID     string    `json:"user_id" validate:"required"`
+	Reason     string    `json:"reason,omitempty"`
+	ChangedBy  string    `json:"changed_by,omitempty"`
+	OccurredAt time.Time `json:"occurred_at" validate:"required"`
+}
+
+type UserRoleChanged struct {
+	UserID     string    `json:"user_id" validate:"required"`
+	From       string    `json:"from" validate:"required"`
+	To         string    `json:"to" validate:"required"`
+	ChangedBy  string    `json:"changed_by"`
+	OccurredAt time.Time `json:"occurred_at" validate:"required"`
+}
+
+// UserPasswordChanged lets notification-api warn the user, in case they did
+// not change it themselves.
+type UserPasswordChanged struct {
+	UserID     string    `json:"user_id" validate:"required"`
+	Email      string    `json:"email"`
+	OccurredAt time.Time `json:"occurred_at" validate:"required"`
+}
+
+// UserLoggedIn is sent after each successful sign-in. Method is password or
+// phone.
+type UserLoggedIn struct {
+	UserID     string    `json:"user_id" validate:"required"`
+	Method     string    `json:"method" validate:"required"`
+	OccurredAt time.Time `json:"occurred_at" validate:"required"`
+}
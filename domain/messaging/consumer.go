@@ -0,0 +1,86 @@
+package messaging
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrPermanent marks a failure that no retry can fix, the message goes
+// straight to the dead-letter queue.
+var ErrPermanent = errors.New("permanent failure")
+
+// Permanent wraps err so the message is dead-lettered without being retried.
+func Permanent(err error) error {
+	return fmt.Errorf("%w: %w", ErrPermanent, err)
+}
+
+// Handler processes a consumed event. ctx carries its correlation, so the
+// events published while handling it are traced back to it, and is cancelled
+// when Shutdown stops waiting for it.
+type Handler func(ctx context.Context, envelope *Envelope) error
+
+// Inbox records the messages a consumer processed, see ConsumeOptions.
+type Inbox interface {
+	// Process runs handle unless consumer already processed messageID.
+	Process(ctx context.Context, consumer, messageID string, handle func(ctx context.Context) error) error
+}
+
+// ConsumeOptions tune the consumer of one queue.
+type ConsumeOptions struct {
+	// Name identifies the consumer in the inbox, the queue name by default.
+	Name string
+	// Workers is how many messages are handled at once, 1 by default.
+	Workers int
+	// Prefetch is how many unacked messages the broker sends ahead, twice
+	// Workers by default.
+	Prefetch int
+	// Retry defaults to DefaultRetryPolicy.
+	Retry RetryPolicy
+	// Inbox, when set, makes the handler idempotent: a message it already
+	// processed is acked without calling it again.
+	Inbox Inbox
+}
+
+// WithDefaults fills in the options left unset for the consumer of queue.
+func (o ConsumeOptions) WithDefaults(queue string) ConsumeOptions {
+	if o.Name == "" {
+		o.Name = queue
+	}
+	if o.Workers <= 0 {
+		o.Workers = 1
+	}
+	if o.Prefetch <= 0 {
+		o.Prefetch = 2 * o.Workers
+	}
+	if o.Retry.MaxAttempts == 0 {
+		o.Retry = DefaultRetryPolicy
+	}
+	return o
+}
+
+// RetryPolicy says how a queue's consumer retries a message its handler
+// failed on.
+type RetryPolicy struct {
+	// MaxAttempts counts the first delivery, the message is dead-lettered
+	// after that many failures.
+	MaxAttempts int
+	// Delays are waited before each retry, the last one is reused.
+	Delays []time.Duration
+}
+
+// DefaultRetryPolicy suits handlers calling other services that may be
+// briefly unavailable.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 5,
+	Delays:      []time.Duration{5 * time.Second, 30 * time.Second, 5 * time.Minute},
+}
+
+// Delay is waited before retrying a message that failed attempts times.
+func (p RetryPolicy) Delay(attempts int) time.Duration {
+	if len(p.Delays) == 0 {
+		return 0
+	}
+	return p.Delays[min(attempts, len(p.Delays))-1]
+}
@@ -0,0 +1,114 @@
+package messaging
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Producer is the CloudEvents source of everything auth-api publishes.
+const Producer = "auth-api"
+
+// Schemas knows the payload of every event type and version.
+type Schemas interface {
+	Latest(eventType string) (int, error)
+	Validate(eventType string, version int, data []byte) error
+}
+
+// Envelope wraps every published event with what consumers need to
+// deduplicate it, pick its schema and trace it back to the request that
+// caused it.
+type Envelope struct {
+	ID            string          `json:"id"`
+	Type          string          `json:"type"`
+	Version       int             `json:"version"`
+	OccurredAt    time.Time       `json:"occurred_at"`
+	Producer      string          `json:"producer"`
+	CorrelationID string          `json:"correlation_id,omitempty"`
+	CausationID   string          `json:"causation_id,omitempty"`
+	Data          json.RawMessage `json:"data"`
+}
+
+// NewEnvelope wraps payload as the latest version of eventType registered in
+// schemas, checking it against that schema.
+func NewEnvelope(ctx context.Context, schemas Schemas, eventType string, payload interface{}) (*Envelope, error) {
+	version, err := schemas.Latest(eventType)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal message: %w", err)
+	}
+	if err := schemas.Validate(eventType, version, data); err != nil {
+		return nil, err
+	}
+
+	return &Envelope{
+		ID:            uuid.NewString(),
+		Type:          eventType,
+		Version:       version,
+		OccurredAt:    time.Now().UTC(),
+		Producer:      Producer,
+		CorrelationID: correlationID(ctx),
+		CausationID:   causationID(ctx),
+		Data:          data,
+	}, nil
+}
+
+// Decode unmarshals the payload into v.
+func (e *Envelope) Decode(v interface{}) error {
+	return json.Unmarshal(e.Data, v)
+}
+
+type contextKey int
+
+const (
+	requestKey contextKey = iota
+	correlationKey
+	causationKey
+)
+
+// WithRequestID returns the context of the request with the given id: events
+// published from it are correlated with and caused by that request.
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestKey, id)
+}
+
+// WithEnvelope returns the context to handle envelope in: events published
+// from it share its correlation id and are caused by it.
+func WithEnvelope(ctx context.Context, envelope *Envelope) context.Context {
+	correlation := envelope.CorrelationID
+	if correlation == "" {
+		correlation = envelope.ID
+	}
+	ctx = context.WithValue(ctx, correlationKey, correlation)
+	return context.WithValue(ctx, causationKey, envelope.ID)
+}
+
+// correlationID is the id shared by everything done for one request, the
+// request id unless ctx handles an event.
+func correlationID(ctx context.Context) string {
+	if id, ok := ctx.Value(correlationKey).(string); ok {
+		return id
+	}
+	return requestID(ctx)
+}
+
+// causationID is the id of what directly caused the event: the event being
+// handled, or else the request.
+func causationID(ctx context.Context) string {
+	if id, ok := ctx.Value(causationKey).(string); ok {
+		return id
+	}
+	return requestID(ctx)
+}
+
+func requestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestKey).(string)
+	return id
+}
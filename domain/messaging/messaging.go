@@ -0,0 +1,58 @@
+// Package messaging is what the services need from a message broker, so they
+// do not depend on RabbitMQ itself.
+package messaging
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrNotConnected is returned while the connection to the broker is down
+	// and being re-established.
+	ErrNotConnected = errors.New("not connected to the broker")
+	// ErrNacked means the broker refused responsibility for the message, it
+	// may be published again.
+	ErrNacked = errors.New("message was nacked by the broker")
+	// ErrUnroutable means no queue is bound for the message. Publishing it
+	// again will fail the same way until the topology is fixed.
+	ErrUnroutable = errors.New("message could not be routed to any queue")
+	// ErrConfirmTimeout means the confirm did not arrive in time, the
+	// message may or may not have been stored.
+	ErrConfirmTimeout = errors.New("timed out waiting for publisher confirm")
+	// ErrShuttingDown is returned by Consume once Shutdown was called.
+	ErrShuttingDown = errors.New("consumers are shutting down")
+)
+
+// PublishError says which message failed. Use errors.Is with the errors
+// above to decide what to do.
+type PublishError struct {
+	Exchange   string
+	RoutingKey string
+	MessageID  string
+	Err        error
+}
+
+func (e *PublishError) Error() string {
+	return fmt.Sprintf("failed to publish message %s to %q with key %q: %v", e.MessageID, e.Exchange, e.RoutingKey, e.Err)
+}
+
+func (e *PublishError) Unwrap() error {
+	return e.Err
+}
+
+// Publisher sends events to an exchange. Both methods return once the broker
+// took responsibility for the event.
+type Publisher interface {
+	// Publish wraps message in an envelope typed by routingKey.
+	Publish(ctx context.Context, exchange, routingKey string, message interface{}) error
+	PublishEnvelope(ctx context.Context, exchange, routingKey string, envelope *Envelope) error
+}
+
+// Subscriber hands the events of a queue to a handler.
+type Subscriber interface {
+	Consume(queue string, options ConsumeOptions, handler Handler) error
+	// Shutdown stops consuming and waits for the handlers until ctx is done.
+	Shutdown(ctx context.Context) error
+}
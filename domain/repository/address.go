@@ -0,0 +1,21 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/goodfoodcesi/auth-api/infrastructure/database/sqlc"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+var ErrAddressNotFound = errors.New("address not found")
+
+// AddressRepository scopes every lookup to the owning user, so an address id
+// alone never grants access to another user's address.
+type AddressRepository interface {
+	Create(ctx context.Context, address *db.UserAddress) (*db.UserAddress, error)
+	GetByID(ctx context.Context, userID, id pgtype.UUID) (*db.UserAddress, error)
+	ListByUser(ctx context.Context, userID pgtype.UUID) ([]db.UserAddress, error)
+	Update(ctx context.Context, address *db.UserAddress) (*db.UserAddress, error)
+	Delete(ctx context.Context, userID, id pgtype.UUID) error
+}
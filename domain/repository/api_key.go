@@ -0,0 +1,21 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/goodfoodcesi/auth-api/infrastructure/database/sqlc"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+var ErrAPIKeyNotFound = errors.New("api key not found")
+
+type APIKeyRepository interface {
+	Create(ctx context.Context, key *db.ApiKey) (*db.ApiKey, error)
+	GetByPrefix(ctx context.Context, prefix string) (*db.ApiKey, error)
+	ListByRestaurant(ctx context.Context, restaurantID pgtype.UUID) ([]db.ApiKey, error)
+	// Revoke fails with ErrAPIKeyNotFound when the key does not belong to
+	// restaurantID or was already revoked.
+	Revoke(ctx context.Context, id, restaurantID pgtype.UUID) error
+	Touch(ctx context.Context, id pgtype.UUID, ip string) error
+}
@@ -0,0 +1,11 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/goodfoodcesi/auth-api/infrastructure/database/sqlc"
+)
+
+type AuditRepository interface {
+	Create(ctx context.Context, event *db.AuditEvent) error
+}
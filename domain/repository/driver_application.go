@@ -0,0 +1,26 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/goodfoodcesi/auth-api/infrastructure/database/sqlc"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+var (
+	ErrDriverApplicationNotFound = errors.New("driver application not found")
+	// ErrDriverApplicationConflict is returned when the application is no
+	// longer in the status the change was based on.
+	ErrDriverApplicationConflict = errors.New("driver application status has changed")
+)
+
+type DriverApplicationRepository interface {
+	GetByID(ctx context.Context, id pgtype.UUID) (*db.DriverApplication, error)
+	GetByUserID(ctx context.Context, userID pgtype.UUID) (*db.DriverApplication, error)
+	// List returns the applications in status, or the review queue when it is not valid.
+	List(ctx context.Context, status db.NullDriverApplicationStatus) ([]db.DriverApplication, error)
+	Submit(ctx context.Context, application *db.DriverApplication) (*db.DriverApplication, error)
+	Resubmit(ctx context.Context, application *db.DriverApplication) (*db.DriverApplication, error)
+	Review(ctx context.Context, application *db.DriverApplication, from db.DriverApplicationStatus) (*db.DriverApplication, error)
+}
@@ -0,0 +1,28 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/goodfoodcesi/auth-api/infrastructure/database/sqlc"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+var (
+	ErrInvitationNotFound = errors.New("invitation not found")
+	// ErrInvitationUnusable is returned when an invitation was already
+	// accepted, revoked or has expired.
+	ErrInvitationUnusable = errors.New("invitation can no longer be used")
+)
+
+type InvitationRepository interface {
+	Create(ctx context.Context, invitation *db.Invitation) (*db.Invitation, error)
+	GetByID(ctx context.Context, id pgtype.UUID) (*db.Invitation, error)
+	GetByTokenHash(ctx context.Context, tokenHash string) (*db.Invitation, error)
+	// List returns the invitations sent by invitedBy, or all of them when it is not valid.
+	List(ctx context.Context, invitedBy pgtype.UUID) ([]db.Invitation, error)
+	Revoke(ctx context.Context, id pgtype.UUID) error
+	// Redeem creates user, marks the invitation accepted and, for restaurant
+	// invitations, adds the user as staff in one transaction.
+	Redeem(ctx context.Context, invitation *db.Invitation, user *db.User) (*db.User, error)
+}
@@ -0,0 +1,20 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/goodfoodcesi/auth-api/infrastructure/database/sqlc"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+var ErrMembershipNotFound = errors.New("membership not found")
+
+type MembershipRepository interface {
+	// Save adds the member or changes the role of an existing one.
+	Save(ctx context.Context, membership *db.Membership) (*db.Membership, error)
+	Get(ctx context.Context, userID, restaurantID pgtype.UUID) (*db.Membership, error)
+	ListByUser(ctx context.Context, userID pgtype.UUID) ([]db.Membership, error)
+	ListByRestaurant(ctx context.Context, restaurantID pgtype.UUID) ([]db.Membership, error)
+	Delete(ctx context.Context, userID, restaurantID pgtype.UUID) error
+}
@@ -0,0 +1,30 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/goodfoodcesi/auth-api/infrastructure/database/sqlc"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+var ErrOutboxMessageNotFound = errors.New("outbox message not found")
+
+type OutboxRepository interface {
+	// Add must be called within the transaction of the change the message
+	// describes.
+	Add(ctx context.Context, message *db.Outbox) error
+	// Claim leases up to limit due messages until leaseUntil.
+	Claim(ctx context.Context, limit int32, leaseUntil time.Time) ([]db.Outbox, error)
+	MarkSent(ctx context.Context, id pgtype.UUID) error
+	MarkFailed(ctx context.Context, id pgtype.UUID, cause string, retryAt time.Time) error
+	Get(ctx context.Context, id pgtype.UUID) (*db.Outbox, error)
+	// List filters by status: pending, failed, sent, or all when empty.
+	List(ctx context.Context, status string, limit int32) ([]db.Outbox, error)
+	// Replay queues a message to be published again, even if it was sent.
+	Replay(ctx context.Context, id pgtype.UUID) error
+	// Lag returns how many messages wait to be published and when the oldest
+	// of them was written, zero when none are.
+	Lag(ctx context.Context) (int64, time.Time, error)
+}
@@ -0,0 +1,15 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/goodfoodcesi/auth-api/infrastructure/database/sqlc"
+)
+
+type PermissionRepository interface {
+	List(ctx context.Context) ([]db.Permission, error)
+	ListGrants(ctx context.Context) ([]db.RolePermission, error)
+	ForRole(ctx context.Context, role db.UserRole) ([]string, error)
+	// SetForRole replaces every permission granted to role in one transaction.
+	SetForRole(ctx context.Context, role db.UserRole, permissions []string) error
+}
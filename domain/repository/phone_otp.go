@@ -0,0 +1,21 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/goodfoodcesi/auth-api/infrastructure/database/sqlc"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+type PhoneOtpRepository interface {
+	// Create stores otp and invalidates the codes previously sent to the same number.
+	Create(ctx context.Context, otp *db.PhoneOtp) (*db.PhoneOtp, error)
+	CountSince(ctx context.Context, phoneNumber string, since time.Time) (int64, error)
+	GetActive(ctx context.Context, phoneNumber string) (*db.PhoneOtp, error)
+	// IncrementAttempts counts one more attempt and returns the new count, or
+	// false when maxAttempts were already used.
+	IncrementAttempts(ctx context.Context, id pgtype.UUID, maxAttempts int32) (int32, bool, error)
+	// Consume returns false when the code was already used by a concurrent request.
+	Consume(ctx context.Context, id pgtype.UUID) (bool, error)
+}
@@ -0,0 +1,15 @@
+package repository
+
+import (
+	"context"
+	"time"
+)
+
+type ProcessedMessageRepository interface {
+	// MarkProcessed records that consumer processed messageID and reports
+	// false when it already had. It must be called within the transaction of
+	// the consumer's side effects.
+	MarkProcessed(ctx context.Context, consumer, messageID string) (bool, error)
+	// DeleteBefore forgets the messages processed before, returning how many.
+	DeleteBefore(ctx context.Context, before time.Time) (int64, error)
+}
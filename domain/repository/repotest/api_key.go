@@ -0,0 +1,86 @@
+package repotest
+
+import (
+	"context"
+	"sync"
+
+	"github.com/goodfoodcesi/auth-api/domain/repository"
+	"github.com/goodfoodcesi/auth-api/infrastructure/database/sqlc"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+var _ repository.APIKeyRepository = (*APIKeys)(nil)
+
+type APIKeys struct {
+	mu   sync.Mutex
+	keys []*db.ApiKey
+}
+
+func NewAPIKeys() *APIKeys {
+	return &APIKeys{}
+}
+
+func (r *APIKeys) Create(_ context.Context, key *db.ApiKey) (*db.ApiKey, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	created := *key
+	created.ID = NewID()
+	created.CreatedAt = now()
+	r.keys = append(r.keys, &created)
+	found := created
+	return &found, nil
+}
+
+func (r *APIKeys) GetByPrefix(_ context.Context, prefix string) (*db.ApiKey, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, key := range r.keys {
+		if key.Prefix == prefix {
+			found := *key
+			return &found, nil
+		}
+	}
+	return nil, repository.ErrAPIKeyNotFound
+}
+
+func (r *APIKeys) ListByRestaurant(_ context.Context, restaurantID pgtype.UUID) ([]db.ApiKey, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// Newest first.
+	var keys []db.ApiKey
+	for i := len(r.keys) - 1; i >= 0; i-- {
+		if r.keys[i].RestaurantID == restaurantID {
+			keys = append(keys, *r.keys[i])
+		}
+	}
+	return keys, nil
+}
+
+func (r *APIKeys) Revoke(_ context.Context, id, restaurantID pgtype.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, key := range r.keys {
+		if key.ID == id && key.RestaurantID == restaurantID && !key.RevokedAt.Valid {
+			key.RevokedAt = now()
+			return nil
+		}
+	}
+	return repository.ErrAPIKeyNotFound
+}
+
+func (r *APIKeys) Touch(_ context.Context, id pgtype.UUID, ip string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, key := range r.keys {
+		if key.ID == id {
+			key.LastUsedAt = now()
+			key.LastUsedIp = pgtype.Text{String: ip, Valid: true}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,124 @@
+package repotest
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/goodfoodcesi/auth-api/domain/repository"
+	"github.com/goodfoodcesi/auth-api/infrastructure/database/sqlc"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+var _ repository.DriverApplicationRepository = (*DriverApplications)(nil)
+
+type DriverApplications struct {
+	mu           sync.Mutex
+	applications []*db.DriverApplication
+}
+
+func NewDriverApplications(applications ...db.DriverApplication) *DriverApplications {
+	r := &DriverApplications{}
+	for _, application := range applications {
+		if !application.ID.Valid {
+			application.ID = NewID()
+		}
+		if !application.SubmittedAt.Valid {
+			application.SubmittedAt = now()
+		}
+		r.applications = append(r.applications, &application)
+	}
+	return r
+}
+
+func (r *DriverApplications) GetByID(_ context.Context, id pgtype.UUID) (*db.DriverApplication, error) {
+	return r.find(func(application *db.DriverApplication) bool { return application.ID == id })
+}
+
+func (r *DriverApplications) GetByUserID(_ context.Context, userID pgtype.UUID) (*db.DriverApplication, error) {
+	return r.find(func(application *db.DriverApplication) bool { return application.UserID == userID })
+}
+
+func (r *DriverApplications) find(match func(application *db.DriverApplication) bool) (*db.DriverApplication, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, application := range r.applications {
+		if match(application) {
+			found := *application
+			return &found, nil
+		}
+	}
+	return nil, repository.ErrDriverApplicationNotFound
+}
+
+func (r *DriverApplications) List(_ context.Context, status db.NullDriverApplicationStatus) ([]db.DriverApplication, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var applications []db.DriverApplication
+	for _, application := range r.applications {
+		if status.Valid && application.Status != status.DriverApplicationStatus {
+			continue
+		}
+		if !status.Valid && application.Status != db.DriverApplicationStatusSubmitted && application.Status != db.DriverApplicationStatusUnderReview {
+			continue
+		}
+		applications = append(applications, *application)
+	}
+	sort.Slice(applications, func(i, j int) bool {
+		return applications[i].SubmittedAt.Time.Before(applications[j].SubmittedAt.Time)
+	})
+	return applications, nil
+}
+
+func (r *DriverApplications) Submit(_ context.Context, application *db.DriverApplication) (*db.DriverApplication, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	submitted := *application
+	submitted.ID = NewID()
+	submitted.Status = db.DriverApplicationStatusSubmitted
+	submitted.SubmittedAt = now()
+	submitted.UpdatedAt = submitted.SubmittedAt
+	r.applications = append(r.applications, &submitted)
+	found := submitted
+	return &found, nil
+}
+
+func (r *DriverApplications) Resubmit(_ context.Context, application *db.DriverApplication) (*db.DriverApplication, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, stored := range r.applications {
+		if stored.UserID == application.UserID && stored.Status == db.DriverApplicationStatusRejected {
+			stored.Status = db.DriverApplicationStatusSubmitted
+			stored.VehicleType = application.VehicleType
+			stored.LicenseNumber = application.LicenseNumber
+			stored.DocumentUrl = application.DocumentUrl
+			stored.SubmittedAt = now()
+			stored.UpdatedAt = stored.SubmittedAt
+			found := *stored
+			return &found, nil
+		}
+	}
+	return nil, repository.ErrDriverApplicationConflict
+}
+
+func (r *DriverApplications) Review(_ context.Context, application *db.DriverApplication, from db.DriverApplicationStatus) (*db.DriverApplication, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, stored := range r.applications {
+		if stored.ID == application.ID && stored.Status == from {
+			stored.Status = application.Status
+			stored.ReviewerNotes = application.ReviewerNotes
+			stored.ReviewedBy = application.ReviewedBy
+			stored.ReviewedAt = now()
+			stored.UpdatedAt = stored.ReviewedAt
+			found := *stored
+			return &found, nil
+		}
+	}
+	return nil, repository.ErrDriverApplicationConflict
+}
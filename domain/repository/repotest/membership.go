@@ -0,0 +1,102 @@
+package repotest
+
+import (
+	"context"
+	"sync"
+
+	"github.com/goodfoodcesi/auth-api/domain/repository"
+	"github.com/goodfoodcesi/auth-api/infrastructure/database/sqlc"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+var _ repository.MembershipRepository = (*Memberships)(nil)
+
+type Memberships struct {
+	mu          sync.Mutex
+	memberships []db.Membership
+}
+
+func NewMemberships(memberships ...db.Membership) *Memberships {
+	r := &Memberships{}
+	for _, membership := range memberships {
+		r.save(membership)
+	}
+	return r
+}
+
+func (r *Memberships) Save(_ context.Context, membership *db.Membership) (*db.Membership, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	saved := r.save(*membership)
+	return &saved, nil
+}
+
+func (r *Memberships) save(membership db.Membership) db.Membership {
+	for i, existing := range r.memberships {
+		if existing.UserID == membership.UserID && existing.RestaurantID == membership.RestaurantID {
+			r.memberships[i].Role = membership.Role
+			return r.memberships[i]
+		}
+	}
+	if !membership.ID.Valid {
+		membership.ID = NewID()
+	}
+	if !membership.CreatedAt.Valid {
+		membership.CreatedAt = now()
+	}
+	r.memberships = append(r.memberships, membership)
+	return membership
+}
+
+func (r *Memberships) Get(_ context.Context, userID, restaurantID pgtype.UUID) (*db.Membership, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, membership := range r.memberships {
+		if membership.UserID == userID && membership.RestaurantID == restaurantID {
+			found := membership
+			return &found, nil
+		}
+	}
+	return nil, repository.ErrMembershipNotFound
+}
+
+func (r *Memberships) ListByUser(_ context.Context, userID pgtype.UUID) ([]db.Membership, error) {
+	return r.list(func(membership db.Membership) bool { return membership.UserID == userID }), nil
+}
+
+func (r *Memberships) ListByRestaurant(_ context.Context, restaurantID pgtype.UUID) ([]db.Membership, error) {
+	return r.list(func(membership db.Membership) bool { return membership.RestaurantID == restaurantID }), nil
+}
+
+func (r *Memberships) list(match func(membership db.Membership) bool) []db.Membership {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var memberships []db.Membership
+	for _, membership := range r.memberships {
+		if match(membership) {
+			memberships = append(memberships, membership)
+		}
+	}
+	return memberships
+}
+
+func (r *Memberships) Delete(_ context.Context, userID, restaurantID pgtype.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, membership := range r.memberships {
+		if membership.UserID == userID && membership.RestaurantID == restaurantID {
+			r.memberships = append(r.memberships[:i], r.memberships[i+1:]...)
+			return nil
+		}
+	}
+	return repository.ErrMembershipNotFound
+}
+
+// All returns every membership, in the order they were added.
+func (r *Memberships) All() []db.Membership {
+	return r.list(func(db.Membership) bool { return true })
+}
@@ -0,0 +1,163 @@
+package repotest
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/goodfoodcesi/auth-api/domain/repository"
+	"github.com/goodfoodcesi/auth-api/infrastructure/database/sqlc"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+var _ repository.OutboxRepository = (*Outbox)(nil)
+
+type Outbox struct {
+	mu       sync.Mutex
+	messages []*db.Outbox
+}
+
+func NewOutbox() *Outbox {
+	return &Outbox{}
+}
+
+func (r *Outbox) Add(_ context.Context, message *db.Outbox) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored := *message
+	stored.ID = NewID()
+	stored.CreatedAt = now()
+	stored.AvailableAt = stored.CreatedAt
+	r.messages = append(r.messages, &stored)
+	return nil
+}
+
+// Claim ignores leaseUntil: a claimed message is due again until it is marked
+// sent or failed.
+func (r *Outbox) Claim(_ context.Context, limit int32, _ time.Time) ([]db.Outbox, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var due []db.Outbox
+	for _, message := range r.messages {
+		if len(due) == int(limit) {
+			break
+		}
+		if message.SentAt.Valid || message.AvailableAt.Time.After(time.Now()) {
+			continue
+		}
+		due = append(due, *message)
+	}
+	return due, nil
+}
+
+func (r *Outbox) MarkSent(_ context.Context, id pgtype.UUID) error {
+	return r.change(id, func(message *db.Outbox) {
+		message.SentAt = now()
+		message.LastError = pgtype.Text{}
+	})
+}
+
+func (r *Outbox) MarkFailed(_ context.Context, id pgtype.UUID, cause string, retryAt time.Time) error {
+	return r.change(id, func(message *db.Outbox) {
+		message.Attempts++
+		message.LastError = pgtype.Text{String: cause, Valid: true}
+		message.AvailableAt = pgtype.Timestamptz{Time: retryAt, Valid: true}
+	})
+}
+
+func (r *Outbox) Get(_ context.Context, id pgtype.UUID) (*db.Outbox, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, message := range r.messages {
+		if message.ID == id {
+			found := *message
+			return &found, nil
+		}
+	}
+	return nil, repository.ErrOutboxMessageNotFound
+}
+
+func (r *Outbox) List(_ context.Context, status string, limit int32) ([]db.Outbox, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// Newest first.
+	var messages []db.Outbox
+	for i := len(r.messages) - 1; i >= 0 && len(messages) < int(limit); i-- {
+		message := r.messages[i]
+		sent := message.SentAt.Valid
+		switch {
+		case status == "sent" && !sent,
+			status == "pending" && sent,
+			status == "failed" && (sent || message.Attempts == 0):
+			continue
+		}
+		messages = append(messages, *message)
+	}
+	return messages, nil
+}
+
+func (r *Outbox) Replay(_ context.Context, id pgtype.UUID) error {
+	return r.change(id, func(message *db.Outbox) {
+		message.SentAt = pgtype.Timestamptz{}
+		message.Attempts = 0
+		message.LastError = pgtype.Text{}
+		message.AvailableAt = now()
+	})
+}
+
+func (r *Outbox) Lag(_ context.Context) (int64, time.Time, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var pending int64
+	var oldest time.Time
+	for _, message := range r.messages {
+		if message.SentAt.Valid {
+			continue
+		}
+		pending++
+		if oldest.IsZero() || message.CreatedAt.Time.Before(oldest) {
+			oldest = message.CreatedAt.Time
+		}
+	}
+	return pending, oldest, nil
+}
+
+// Messages returns every message written so far, oldest first.
+func (r *Outbox) Messages() []db.Outbox {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	messages := make([]db.Outbox, 0, len(r.messages))
+	for _, message := range r.messages {
+		messages = append(messages, *message)
+	}
+	return messages
+}
+
+// EventTypes returns the event type of every message written so far, oldest
+// first.
+func (r *Outbox) EventTypes() []string {
+	var eventTypes []string
+	for _, message := range r.Messages() {
+		eventTypes = append(eventTypes, message.EventType)
+	}
+	return eventTypes
+}
+
+func (r *Outbox) change(id pgtype.UUID, fn func(message *db.Outbox)) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, message := range r.messages {
+		if message.ID == id {
+			fn(message)
+			return nil
+		}
+	}
+	return repository.ErrOutboxMessageNotFound
+}
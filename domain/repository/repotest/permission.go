@@ -0,0 +1,76 @@
+package repotest
+
+import (
+	"context"
+	"slices"
+	"sync"
+
+	"github.com/goodfoodcesi/auth-api/domain/repository"
+	"github.com/goodfoodcesi/auth-api/infrastructure/database/sqlc"
+)
+
+var _ repository.PermissionRepository = (*Permissions)(nil)
+
+type Permissions struct {
+	mu     sync.Mutex
+	grants map[db.UserRole][]string
+}
+
+// NewPermissions grants each role the permissions listed for it. Every
+// permission granted to a role is also a known permission.
+func NewPermissions(grants map[db.UserRole][]string) *Permissions {
+	r := &Permissions{grants: make(map[db.UserRole][]string)}
+	for role, permissions := range grants {
+		r.grants[role] = slices.Clone(permissions)
+	}
+	return r
+}
+
+func (r *Permissions) List(_ context.Context) ([]db.Permission, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var names []string
+	for _, permissions := range r.grants {
+		for _, permission := range permissions {
+			if !slices.Contains(names, permission) {
+				names = append(names, permission)
+			}
+		}
+	}
+	slices.Sort(names)
+
+	permissions := make([]db.Permission, 0, len(names))
+	for _, name := range names {
+		permissions = append(permissions, db.Permission{Name: name})
+	}
+	return permissions, nil
+}
+
+func (r *Permissions) ListGrants(_ context.Context) ([]db.RolePermission, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var grants []db.RolePermission
+	for role, permissions := range r.grants {
+		for _, permission := range permissions {
+			grants = append(grants, db.RolePermission{Role: role, Permission: permission})
+		}
+	}
+	return grants, nil
+}
+
+func (r *Permissions) ForRole(_ context.Context, role db.UserRole) ([]string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return slices.Clone(r.grants[role]), nil
+}
+
+func (r *Permissions) SetForRole(_ context.Context, role db.UserRole, permissions []string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.grants[role] = slices.Clone(permissions)
+	return nil
+}
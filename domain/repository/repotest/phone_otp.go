@@ -0,0 +1,98 @@
+package repotest
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/goodfoodcesi/auth-api/domain/repository"
+	"github.com/goodfoodcesi/auth-api/infrastructure/database/sqlc"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+var _ repository.PhoneOtpRepository = (*PhoneOtps)(nil)
+
+type PhoneOtps struct {
+	mu   sync.Mutex
+	otps []*db.PhoneOtp
+}
+
+func NewPhoneOtps() *PhoneOtps {
+	return &PhoneOtps{}
+}
+
+func (r *PhoneOtps) Create(_ context.Context, otp *db.PhoneOtp) (*db.PhoneOtp, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// Only the latest code sent to a number stays usable.
+	for _, previous := range r.otps {
+		if previous.PhoneNumber == otp.PhoneNumber && !previous.ConsumedAt.Valid {
+			previous.ConsumedAt = now()
+		}
+	}
+
+	created := *otp
+	created.ID = NewID()
+	created.Attempts = 0
+	created.ConsumedAt = pgtype.Timestamptz{}
+	created.CreatedAt = now()
+	r.otps = append(r.otps, &created)
+	found := created
+	return &found, nil
+}
+
+func (r *PhoneOtps) CountSince(_ context.Context, phoneNumber string, since time.Time) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var count int64
+	for _, otp := range r.otps {
+		if otp.PhoneNumber == phoneNumber && otp.CreatedAt.Time.After(since) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (r *PhoneOtps) GetActive(_ context.Context, phoneNumber string) (*db.PhoneOtp, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i := len(r.otps) - 1; i >= 0; i-- {
+		otp := r.otps[i]
+		if otp.PhoneNumber == phoneNumber && !otp.ConsumedAt.Valid && otp.ExpiresAt.Time.After(time.Now()) {
+			found := *otp
+			return &found, nil
+		}
+	}
+	// The database repository has no error of its own for this.
+	return nil, pgx.ErrNoRows
+}
+
+func (r *PhoneOtps) IncrementAttempts(_ context.Context, id pgtype.UUID, maxAttempts int32) (int32, bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, otp := range r.otps {
+		if otp.ID == id && otp.Attempts < maxAttempts {
+			otp.Attempts++
+			return otp.Attempts, true, nil
+		}
+	}
+	return 0, false, nil
+}
+
+func (r *PhoneOtps) Consume(_ context.Context, id pgtype.UUID) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, otp := range r.otps {
+		if otp.ID == id && !otp.ConsumedAt.Valid {
+			otp.ConsumedAt = now()
+			return true, nil
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,17 @@
+package repotest
+
+import (
+	"context"
+
+	"github.com/goodfoodcesi/auth-api/domain/repository"
+)
+
+var _ repository.Transactor = Transactor{}
+
+// Transactor runs fn straight away. The repositories of this package are not
+// transactional, so what fn did before failing is kept.
+type Transactor struct{}
+
+func (Transactor) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
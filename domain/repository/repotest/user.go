@@ -0,0 +1,238 @@
+// Package repotest keeps the repositories of domain/repository in memory, so
+// services can be tested without a database. Each one follows the queries it
+// stands in for, including their compare-and-set checks, but transactions are
+// not rolled back.
+package repotest
+
+import (
+	"bytes"
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/goodfoodcesi/auth-api/domain/repository"
+	"github.com/goodfoodcesi/auth-api/infrastructure/database/sqlc"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+var _ repository.UserRepository = (*Users)(nil)
+
+type Users struct {
+	mu    sync.Mutex
+	users map[pgtype.UUID]*db.User
+}
+
+// NewUsers stores users as they are, missing ids, versions and creation
+// times are filled in.
+func NewUsers(users ...*db.User) *Users {
+	r := &Users{users: make(map[pgtype.UUID]*db.User)}
+	for _, user := range users {
+		r.put(user)
+	}
+	return r
+}
+
+func (r *Users) put(user *db.User) *db.User {
+	stored := *user
+	if !stored.ID.Valid {
+		stored.ID = NewID()
+	}
+	if stored.Version == 0 {
+		stored.Version = 1
+	}
+	if stored.Status == "" {
+		stored.Status = db.UserStatusActive
+	}
+	if !stored.CreatedAt.Valid {
+		stored.CreatedAt = now()
+	}
+	stored.UpdatedAt = stored.CreatedAt
+	r.users[stored.ID] = &stored
+	return copyUser(&stored)
+}
+
+func (r *Users) Create(_ context.Context, user *db.User) (*db.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	created := *user
+	created.ID = pgtype.UUID{}
+	created.Version = 0
+	created.CreatedAt = pgtype.Timestamptz{}
+	return r.put(&created), nil
+}
+
+func (r *Users) GetByEmail(_ context.Context, email string) (*db.User, error) {
+	return r.find(func(user *db.User) bool { return user.Email == email })
+}
+
+func (r *Users) GetByID(_ context.Context, id pgtype.UUID) (*db.User, error) {
+	return r.find(func(user *db.User) bool { return user.ID == id })
+}
+
+func (r *Users) GetByPhone(_ context.Context, phoneNumber string) (*db.User, error) {
+	return r.find(func(user *db.User) bool { return user.PhoneNumber.Valid && user.PhoneNumber.String == phoneNumber })
+}
+
+func (r *Users) find(match func(user *db.User) bool) (*db.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, user := range r.users {
+		if match(user) {
+			return copyUser(user), nil
+		}
+	}
+	return nil, repository.ErrUserNotFound
+}
+
+func (r *Users) MarkPhoneVerified(_ context.Context, id pgtype.UUID) error {
+	return r.change(id, func(user *db.User) { user.PhoneVerifiedAt = now() })
+}
+
+func (r *Users) Update(_ context.Context, user *db.User) (*db.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.users[user.ID]
+	if !ok || stored.Version != user.Version {
+		return nil, repository.ErrVersionConflict
+	}
+	stored.Firstname = user.Firstname
+	stored.Lastname = user.Lastname
+	stored.PhoneNumber = user.PhoneNumber
+	stored.Locale = user.Locale
+	stored.Timezone = user.Timezone
+	stored.AvatarUrl = user.AvatarUrl
+	stored.Version++
+	stored.UpdatedAt = now()
+	return copyUser(stored), nil
+}
+
+// List follows the keyset pagination of the ListUsers query.
+func (r *Users) List(_ context.Context, filter repository.UserFilter) ([]db.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	sortBy := filter.Sort
+	if sortBy == "" {
+		sortBy = repository.UserSortCreatedAtDesc
+	}
+	before := func(a, b *db.User) bool {
+		switch sortBy {
+		case repository.UserSortCreatedAtAsc:
+			return a.CreatedAt.Time.Before(b.CreatedAt.Time) || a.CreatedAt.Time.Equal(b.CreatedAt.Time) && compareIDs(a.ID, b.ID) < 0
+		case repository.UserSortEmailAsc:
+			return a.Email < b.Email || a.Email == b.Email && compareIDs(a.ID, b.ID) < 0
+		case repository.UserSortEmailDesc:
+			return a.Email > b.Email || a.Email == b.Email && compareIDs(a.ID, b.ID) > 0
+		default:
+			return a.CreatedAt.Time.After(b.CreatedAt.Time) || a.CreatedAt.Time.Equal(b.CreatedAt.Time) && compareIDs(a.ID, b.ID) > 0
+		}
+	}
+
+	var cursor *db.User
+	if filter.After != nil {
+		cursor = &db.User{
+			ID:        filter.After.ID,
+			Email:     filter.After.Email,
+			CreatedAt: pgtype.Timestamptz{Time: filter.After.CreatedAt, Valid: true},
+		}
+	}
+	search := strings.ToLower(filter.Search)
+
+	var users []db.User
+	for _, user := range r.users {
+		switch {
+		case filter.Role != nil && user.Role != *filter.Role,
+			filter.Status != nil && user.Status != *filter.Status,
+			filter.CreatedAfter != nil && user.CreatedAt.Time.Before(*filter.CreatedAfter),
+			filter.CreatedBefore != nil && !user.CreatedAt.Time.Before(*filter.CreatedBefore),
+			search != "" && !strings.Contains(strings.ToLower(user.Email+" "+user.Firstname+" "+user.Lastname), search),
+			cursor != nil && !before(cursor, user):
+			continue
+		}
+		users = append(users, *copyUser(user))
+	}
+	sort.Slice(users, func(i, j int) bool { return before(&users[i], &users[j]) })
+	if filter.Limit > 0 && len(users) > int(filter.Limit) {
+		users = users[:filter.Limit]
+	}
+	return users, nil
+}
+
+func (r *Users) UpdateRole(_ context.Context, user *db.User) (*db.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.users[user.ID]
+	if !ok || stored.Version != user.Version {
+		return nil, repository.ErrVersionConflict
+	}
+	stored.Role = user.Role
+	stored.Version++
+	stored.UpdatedAt = now()
+	return copyUser(stored), nil
+}
+
+func (r *Users) UpdatePassword(_ context.Context, id pgtype.UUID, passwordHash string) error {
+	return r.change(id, func(user *db.User) {
+		user.PasswordHash = passwordHash
+		user.Version++
+	})
+}
+
+func (r *Users) RevokeSessions(_ context.Context, id pgtype.UUID) error {
+	return r.change(id, func(user *db.User) { user.SessionsRevokedAt = now() })
+}
+
+func (r *Users) UpdateStatus(_ context.Context, user *db.User, from db.UserStatus) (*db.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.users[user.ID]
+	if !ok || stored.Status != from {
+		return nil, repository.ErrStatusConflict
+	}
+	stored.Status = user.Status
+	stored.StatusReason = user.StatusReason
+	stored.StatusChangedBy = user.StatusChangedBy
+	stored.StatusChangedAt = now()
+	stored.Version++
+	stored.UpdatedAt = now()
+	return copyUser(stored), nil
+}
+
+// change applies fn to the stored user, like the :exec queries it does not
+// fail when the user does not exist.
+func (r *Users) change(id pgtype.UUID, fn func(user *db.User)) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if user, ok := r.users[id]; ok {
+		fn(user)
+		user.UpdatedAt = now()
+	}
+	return nil
+}
+
+func copyUser(user *db.User) *db.User {
+	copied := *user
+	return &copied
+}
+
+// NewID returns a random valid id.
+func NewID() pgtype.UUID {
+	return pgtype.UUID{Bytes: uuid.New(), Valid: true}
+}
+
+func compareIDs(a, b pgtype.UUID) int {
+	return bytes.Compare(a.Bytes[:], b.Bytes[:])
+}
+
+func now() pgtype.Timestamptz {
+	return pgtype.Timestamptz{Time: time.Now(), Valid: true}
+}
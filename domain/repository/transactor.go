@@ -0,0 +1,9 @@
+package repository
+
+import "context"
+
+// Transactor runs fn in a database transaction. Repositories called with the
+// context fn receives take part in it.
+type Transactor interface {
+	WithinTx(ctx context.Context, fn func(ctx context.Context) error) error
+}
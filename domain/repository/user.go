@@ -2,15 +2,67 @@ package repository
 
 import (
 	"context"
+	"errors"
+	"time"
+
 	"github.com/goodfoodcesi/auth-api/infrastructure/database/sqlc"
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+var (
+	ErrUserNotFound = errors.New("user not found")
+	// ErrVersionConflict is returned by Update when the stored user no longer
+	// carries the version the caller read.
+	ErrVersionConflict = errors.New("user version conflict")
+	// ErrStatusConflict is returned by UpdateStatus when another transition
+	// happened first.
+	ErrStatusConflict = errors.New("user status conflict")
+)
+
+// UserSort lists the orderings supported by List.
+type UserSort string
+
+const (
+	UserSortCreatedAtDesc UserSort = "created_at_desc"
+	UserSortCreatedAtAsc  UserSort = "created_at_asc"
+	UserSortEmailAsc      UserSort = "email_asc"
+	UserSortEmailDesc     UserSort = "email_desc"
+)
+
+// UserCursor is the position of the last user of the previous page.
+type UserCursor struct {
+	ID        pgtype.UUID
+	CreatedAt time.Time
+	Email     string
+}
+
+type UserFilter struct {
+	Role          *db.UserRole
+	Status        *db.UserStatus
+	CreatedAfter  *time.Time
+	CreatedBefore *time.Time
+	Search        string
+	Sort          UserSort
+	After         *UserCursor
+	Limit         int32
+}
+
 type UserRepository interface {
 	Create(ctx context.Context, user *db.User) (*db.User, error)
 	GetByEmail(ctx context.Context, email string) (*db.User, error)
 	GetByID(ctx context.Context, id pgtype.UUID) (*db.User, error)
-	Update(ctx context.Context, user *db.User) error
+	GetByPhone(ctx context.Context, phoneNumber string) (*db.User, error)
+	MarkPhoneVerified(ctx context.Context, id pgtype.UUID) error
+	// Update persists user only if user.Version still matches the stored row.
+	Update(ctx context.Context, user *db.User) (*db.User, error)
+	List(ctx context.Context, filter UserFilter) ([]db.User, error)
+	// UpdateRole persists user.Role only if user.Version still matches the stored row.
+	UpdateRole(ctx context.Context, user *db.User) (*db.User, error)
+	UpdatePassword(ctx context.Context, id pgtype.UUID, passwordHash string) error
+	// RevokeSessions makes the tokens issued to the user so far unusable.
+	RevokeSessions(ctx context.Context, id pgtype.UUID) error
+	// UpdateStatus moves user to user.Status only if its stored status is still from.
+	UpdateStatus(ctx context.Context, user *db.User, from db.UserStatus) (*db.User, error)
 }
 
 // Definition for a binary tree node.
@@ -0,0 +1,90 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/goodfoodcesi/auth-api/domain/repository"
+	"github.com/goodfoodcesi/auth-api/infrastructure/database/sqlc"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/zap"
+)
+
+var ErrAddressNotFound = errors.New("address not found")
+
+type AddressService struct {
+	repo   repository.AddressRepository
+	logger *zap.Logger
+}
+
+type AddressInput struct {
+	Label       string   `json:"label" validate:"required,max=50"`
+	Line1       string   `json:"line1" validate:"required,max=255"`
+	Line2       string   `json:"line2" validate:"omitempty,max=255"`
+	Postcode    string   `json:"postcode" validate:"required,max=20"`
+	City        string   `json:"city" validate:"required,max=100"`
+	CountryCode string   `json:"country_code" validate:"omitempty,iso3166_1_alpha2"`
+	Latitude    *float64 `json:"latitude" validate:"required_with=Longitude,omitempty,latitude"`
+	Longitude   *float64 `json:"longitude" validate:"required_with=Latitude,omitempty,longitude"`
+	IsDefault   bool     `json:"is_default"`
+}
+
+func NewAddressService(repo repository.AddressRepository, logger *zap.Logger) *AddressService {
+	return &AddressService{
+		repo:   repo,
+		logger: logger,
+	}
+}
+
+func (s *AddressService) List(ctx context.Context, userID pgtype.UUID) ([]db.UserAddress, error) {
+	return s.repo.ListByUser(ctx, userID)
+}
+
+func (s *AddressService) Create(ctx context.Context, userID pgtype.UUID, input AddressInput) (*db.UserAddress, error) {
+	address := input.toAddress()
+	address.UserID = userID
+
+	return s.repo.Create(ctx, address)
+}
+
+func (s *AddressService) Update(ctx context.Context, userID, id pgtype.UUID, input AddressInput) (*db.UserAddress, error) {
+	address := input.toAddress()
+	address.ID = id
+	address.UserID = userID
+
+	updated, err := s.repo.Update(ctx, address)
+	if errors.Is(err, repository.ErrAddressNotFound) {
+		return nil, ErrAddressNotFound
+	}
+	return updated, err
+}
+
+func (s *AddressService) Delete(ctx context.Context, userID, id pgtype.UUID) error {
+	err := s.repo.Delete(ctx, userID, id)
+	if errors.Is(err, repository.ErrAddressNotFound) {
+		return ErrAddressNotFound
+	}
+	return err
+}
+
+func (i AddressInput) toAddress() *db.UserAddress {
+	countryCode := i.CountryCode
+	if countryCode == "" {
+		countryCode = "FR"
+	}
+
+	address := &db.UserAddress{
+		Label:       i.Label,
+		Line1:       i.Line1,
+		Line2:       pgtype.Text{String: i.Line2, Valid: i.Line2 != ""},
+		Postcode:    i.Postcode,
+		City:        i.City,
+		CountryCode: countryCode,
+		IsDefault:   i.IsDefault,
+	}
+	if i.Latitude != nil && i.Longitude != nil {
+		address.Latitude = pgtype.Float8{Float64: *i.Latitude, Valid: true}
+		address.Longitude = pgtype.Float8{Float64: *i.Longitude, Valid: true}
+	}
+	return address
+}
@@ -0,0 +1,258 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net"
+	"time"
+
+	"github.com/goodfoodcesi/auth-api/crypto"
+	"github.com/goodfoodcesi/auth-api/domain/auth"
+	"github.com/goodfoodcesi/auth-api/domain/repository"
+	"github.com/goodfoodcesi/auth-api/infrastructure/database/sqlc"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/zap"
+)
+
+var (
+	ErrAPIKeyNotFound  = errors.New("api key not found")
+	ErrAPIKeyForbidden = errors.New("not allowed to manage this restaurant's api keys")
+	// ErrAPIKeyScope is returned when a key would be granted a permission its
+	// creator does not have.
+	ErrAPIKeyScope        = errors.New("api key scopes exceed your permissions")
+	ErrInvalidIPAllowlist = errors.New("ip allowlist entries must be IP addresses or CIDR ranges")
+	// ErrAPIKeyInvalid is returned for unknown, revoked or expired keys, and
+	// keys used from an address outside their allowlist.
+	ErrAPIKeyInvalid = errors.New("invalid api key")
+)
+
+type APIKeyService struct {
+	repo        repository.APIKeyRepository
+	memberships repository.MembershipRepository
+	userRepo    repository.UserRepository
+	permissions *PermissionService
+	logger      *zap.Logger
+}
+
+type CreateAPIKeyInput struct {
+	Name          string   `json:"name" validate:"required,min=2,max=100"`
+	Scopes        []string `json:"scopes" validate:"required,min=1,dive,required"`
+	IPAllowlist   []string `json:"ip_allowlist" validate:"omitempty,dive,required"`
+	ExpiresInDays int      `json:"expires_in_days" validate:"omitempty,min=1,max=365"`
+}
+
+func NewAPIKeyService(
+	repo repository.APIKeyRepository,
+	memberships repository.MembershipRepository,
+	userRepo repository.UserRepository,
+	permissions *PermissionService,
+	logger *zap.Logger,
+) *APIKeyService {
+	return &APIKeyService{
+		repo:        repo,
+		memberships: memberships,
+		userRepo:    userRepo,
+		permissions: permissions,
+		logger:      logger,
+	}
+}
+
+func (s *APIKeyService) List(ctx context.Context, actor Actor, restaurantID pgtype.UUID) ([]db.ApiKey, error) {
+	if err := s.authorize(ctx, actor, restaurantID); err != nil {
+		return nil, err
+	}
+	return s.repo.ListByRestaurant(ctx, restaurantID)
+}
+
+// Create returns the key together with its full value, which is only
+// available at creation time since just the hash of its secret is stored.
+func (s *APIKeyService) Create(ctx context.Context, actor Actor, restaurantID pgtype.UUID, input CreateAPIKeyInput) (*db.ApiKey, string, error) {
+	if err := s.authorize(ctx, actor, restaurantID); err != nil {
+		return nil, "", err
+	}
+
+	granted, err := s.permissions.ForRole(ctx, actor.Role)
+	if err != nil {
+		return nil, "", err
+	}
+	for _, scope := range input.Scopes {
+		if !contains(granted, scope) {
+			return nil, "", ErrAPIKeyScope
+		}
+		// A key creating a key cannot hand out more than it holds.
+		if actor.Scopes != nil && !contains(actor.Scopes, scope) {
+			return nil, "", ErrAPIKeyScope
+		}
+	}
+
+	for _, entry := range input.IPAllowlist {
+		if net.ParseIP(entry) == nil {
+			if _, _, err := net.ParseCIDR(entry); err != nil {
+				return nil, "", ErrInvalidIPAllowlist
+			}
+		}
+	}
+
+	value, prefix, secret, err := crypto.GenerateAPIKey()
+	if err != nil {
+		return nil, "", err
+	}
+
+	key := &db.ApiKey{
+		Prefix:       prefix,
+		SecretHash:   crypto.HashAPIKeySecret(secret),
+		Name:         input.Name,
+		OwnerID:      actor.ID,
+		RestaurantID: restaurantID,
+		Scopes:       input.Scopes,
+		IpAllowlist:  input.IPAllowlist,
+	}
+	if key.IpAllowlist == nil {
+		key.IpAllowlist = []string{}
+	}
+	if input.ExpiresInDays > 0 {
+		key.ExpiresAt = pgtype.Timestamptz{Time: time.Now().AddDate(0, 0, input.ExpiresInDays), Valid: true}
+	}
+
+	key, err = s.repo.Create(ctx, key)
+	if err != nil {
+		return nil, "", err
+	}
+
+	s.logger.Info("api key created",
+		zap.String("api_key_id", key.ID.String()),
+		zap.String("restaurant_id", restaurantID.String()),
+		zap.String("owner_id", actor.ID.String()),
+		zap.Strings("scopes", key.Scopes),
+	)
+
+	return key, value, nil
+}
+
+func (s *APIKeyService) Revoke(ctx context.Context, actor Actor, restaurantID, id pgtype.UUID) error {
+	if err := s.authorize(ctx, actor, restaurantID); err != nil {
+		return err
+	}
+
+	err := s.repo.Revoke(ctx, id, restaurantID)
+	if errors.Is(err, repository.ErrAPIKeyNotFound) {
+		return ErrAPIKeyNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	s.logger.Info("api key revoked",
+		zap.String("api_key_id", id.String()),
+		zap.String("restaurant_id", restaurantID.String()),
+		zap.String("actor_id", actor.ID.String()),
+	)
+
+	return nil
+}
+
+// AuthenticateAPIKey turns a key presented by a client calling from ip into a
+// principal acting for its owner, restricted to the key's scopes that the
+// owner's role still grants.
+func (s *APIKeyService) AuthenticateAPIKey(ctx context.Context, value, ip string) (*auth.Principal, error) {
+	prefix, secret, ok := crypto.ParseAPIKey(value)
+	if !ok {
+		return nil, ErrAPIKeyInvalid
+	}
+
+	key, err := s.repo.GetByPrefix(ctx, prefix)
+	if errors.Is(err, repository.ErrAPIKeyNotFound) {
+		return nil, ErrAPIKeyInvalid
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if !crypto.CompareAPIKeySecret(key.SecretHash, secret) || key.RevokedAt.Valid {
+		return nil, ErrAPIKeyInvalid
+	}
+	if key.ExpiresAt.Valid && time.Now().After(key.ExpiresAt.Time) {
+		return nil, ErrAPIKeyInvalid
+	}
+	if !ipAllowed(key.IpAllowlist, ip) {
+		return nil, ErrAPIKeyInvalid
+	}
+
+	owner, _ := s.userRepo.GetByID(ctx, key.OwnerID)
+	if owner == nil {
+		return nil, ErrAPIKeyInvalid
+	}
+
+	// A key keeps its scopes when its owner is demoted or their role loses
+	// permissions, so only those the role still grants are honoured.
+	granted, err := s.permissions.ForRole(ctx, owner.Role)
+	if err != nil {
+		return nil, err
+	}
+	permissions := []string{}
+	for _, scope := range key.Scopes {
+		if contains(granted, scope) {
+			permissions = append(permissions, scope)
+		}
+	}
+
+	// A key stops working once its owner no longer runs the restaurant.
+	principal := &auth.Principal{
+		Subject:     owner.ID.String(),
+		Kind:        auth.KindAPIKey,
+		Role:        owner.Role,
+		Permissions: permissions,
+		SessionID:   key.ID.String(),
+		Tenant:      key.RestaurantID.String(),
+	}
+	if owner.Role != db.UserRoleAdmin {
+		membership, err := s.memberships.Get(ctx, owner.ID, key.RestaurantID)
+		if errors.Is(err, repository.ErrMembershipNotFound) {
+			return nil, ErrAPIKeyInvalid
+		}
+		if err != nil {
+			return nil, err
+		}
+		principal.TenantRole = membership.Role
+	}
+
+	if err := s.repo.Touch(ctx, key.ID, ip); err != nil {
+		s.logger.Error("failed to record api key use", zap.String("api_key_id", key.ID.String()), zap.Error(err))
+	}
+
+	return principal, nil
+}
+
+func (s *APIKeyService) authorize(ctx context.Context, actor Actor, restaurantID pgtype.UUID) error {
+	owner, err := isRestaurantOwner(ctx, s.memberships, actor, restaurantID)
+	if err != nil {
+		return err
+	}
+	if !owner {
+		return ErrAPIKeyForbidden
+	}
+	return nil
+}
+
+// ipAllowed accepts any address when allowlist is empty.
+func ipAllowed(allowlist []string, ip string) bool {
+	if len(allowlist) == 0 {
+		return true
+	}
+	addr := net.ParseIP(ip)
+	if addr == nil {
+		return false
+	}
+	for _, entry := range allowlist {
+		if allowed := net.ParseIP(entry); allowed != nil {
+			if allowed.Equal(addr) {
+				return true
+			}
+			continue
+		}
+		if _, network, err := net.ParseCIDR(entry); err == nil && network.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,93 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/goodfoodcesi/auth-api/domain/auth"
+	"github.com/goodfoodcesi/auth-api/domain/repository/repotest"
+	"github.com/goodfoodcesi/auth-api/domain/service"
+	"github.com/goodfoodcesi/auth-api/infrastructure/database/sqlc"
+	"go.uber.org/zap"
+)
+
+func TestAuthenticateAPIKey(t *testing.T) {
+	restaurantID := repotest.NewID()
+	tests := []struct {
+		name string
+		// change is what happens to the owner after the key was created.
+		change    func(t *testing.T, u *users, owner *db.User)
+		wantErr   error
+		wantPerms []string
+	}{
+		{
+			name:      "acts with the scopes of the key",
+			wantPerms: []string{"restaurant:members", "orders:refund"},
+		},
+		{
+			name: "drops scopes the role lost",
+			change: func(t *testing.T, u *users, _ *db.User) {
+				if err := u.permissions.SetForRole(context.Background(), db.UserRoleManager, []string{"orders:refund"}); err != nil {
+					t.Fatal(err)
+				}
+			},
+			wantPerms: []string{"orders:refund"},
+		},
+		{
+			name: "drops scopes when the owner is demoted",
+			change: func(t *testing.T, u *users, owner *db.User) {
+				owner.Role = db.UserRoleClient
+				if _, err := u.repo.UpdateRole(context.Background(), owner); err != nil {
+					t.Fatal(err)
+				}
+			},
+			wantPerms: []string{},
+		},
+		{
+			name: "stops working once the owner leaves the restaurant",
+			change: func(t *testing.T, u *users, owner *db.User) {
+				if err := u.memberships.Delete(context.Background(), owner.ID, restaurantID); err != nil {
+					t.Fatal(err)
+				}
+			},
+			wantErr: service.ErrAPIKeyInvalid,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			owner := &db.User{ID: repotest.NewID(), Email: "owner@example.com", Role: db.UserRoleManager}
+			u := newUsers(owner)
+			membership := db.Membership{UserID: owner.ID, RestaurantID: restaurantID, Role: db.MembershipRoleOwner}
+			if _, err := u.memberships.Save(context.Background(), &membership); err != nil {
+				t.Fatal(err)
+			}
+			keys := service.NewAPIKeyService(repotest.NewAPIKeys(), u.memberships, u.repo, service.NewPermissionService(u.permissions, zap.NewNop()), zap.NewNop())
+
+			_, value, err := keys.Create(context.Background(), service.Actor{ID: owner.ID, Role: owner.Role}, restaurantID, service.CreateAPIKeyInput{
+				Name:   "till",
+				Scopes: []string{"restaurant:members", "orders:refund"},
+			})
+			if err != nil {
+				t.Fatalf("Create: %v", err)
+			}
+			if tt.change != nil {
+				tt.change(t, u, u.get(t, owner.ID))
+			}
+
+			principal, err := keys.AuthenticateAPIKey(context.Background(), value, "198.51.100.7")
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("AuthenticateAPIKey error = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if principal.Kind != auth.KindAPIKey || principal.Subject != owner.ID.String() || principal.Tenant != restaurantID.String() {
+				t.Errorf("principal = %+v", principal)
+			}
+			if !equal(principal.Permissions, tt.wantPerms) {
+				t.Errorf("permissions = %v, want %v", principal.Permissions, tt.wantPerms)
+			}
+		})
+	}
+}
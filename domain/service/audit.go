@@ -0,0 +1,59 @@
+package service
+
+import (
+	"context"
+
+	"github.com/goodfoodcesi/auth-api/domain/repository"
+	"github.com/goodfoodcesi/auth-api/infrastructure/database/sqlc"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/zap"
+)
+
+// Audit actions.
+const (
+	AuditImpersonationStarted = "impersonation.started"
+	AuditImpersonatedRequest  = "impersonation.request"
+)
+
+type AuditService struct {
+	repo   repository.AuditRepository
+	logger *zap.Logger
+}
+
+func NewAuditService(repo repository.AuditRepository, logger *zap.Logger) *AuditService {
+	return &AuditService{
+		repo:   repo,
+		logger: logger,
+	}
+}
+
+// Record stores an audit event. Failures are returned so that callers can
+// refuse to go on with an action that could not be audited.
+func (s *AuditService) Record(ctx context.Context, action string, actorID, subjectID pgtype.UUID, detail, ip string) error {
+	err := s.repo.Create(ctx, &db.AuditEvent{
+		Action:    action,
+		ActorID:   actorID,
+		SubjectID: subjectID,
+		Detail:    pgtype.Text{String: detail, Valid: detail != ""},
+		Ip:        pgtype.Text{String: ip, Valid: ip != ""},
+	})
+	if err != nil {
+		s.logger.Error("failed to record audit event", zap.Error(err), zap.String("action", action))
+		return err
+	}
+	return nil
+}
+
+// RecordImpersonatedRequest is called by AuthMiddleware for every request made
+// with an impersonation token.
+func (s *AuditService) RecordImpersonatedRequest(ctx context.Context, actorID, subjectID, method, path, ip string) error {
+	actor, err := parseUUID(actorID)
+	if err != nil {
+		return err
+	}
+	subject, err := parseUUID(subjectID)
+	if err != nil {
+		return err
+	}
+	return s.Record(ctx, AuditImpersonatedRequest, actor, subject, method+" "+path, ip)
+}
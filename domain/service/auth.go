@@ -5,7 +5,6 @@ import (
 	"errors"
 
 	"github.com/goodfoodcesi/auth-api/infrastructure/jwt"
-	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
@@ -27,10 +26,34 @@ func (s *AuthService) RefreshToken(ctx context.Context, refreshToken string) (*j
 		return nil, errors.New("invalid refresh token")
 	}
 
-	user, err := s.userService.GetByID(ctx, pgtype.UUID{Bytes: uuid.MustParse(claims.UserID), Valid: true})
+	id, err := parseUUID(claims.UserID)
+	if err != nil {
+		return nil, errors.New("invalid refresh token")
+	}
+
+	user, err := s.userService.GetByID(ctx, id)
 	if err != nil {
 		return nil, err
 	}
+	if claims.IssuedAt != nil && sessionRevoked(user, claims.IssuedAt.Time) {
+		return nil, errors.New("invalid refresh token")
+	}
+
+	// Keep the restaurant the session was scoped to, as long as the user is
+	// still a member of it.
+	if claims.RestaurantID != "" {
+		restaurantID, err := parseUUID(claims.RestaurantID)
+		if err != nil {
+			return nil, errors.New("invalid refresh token")
+		}
+		return s.userService.IssueRestaurantTokens(ctx, user, restaurantID)
+	}
+
+	return s.userService.IssueTokens(ctx, user)
+}
 
-	return s.tokenMgr.GenerateTokenPair(user.ID.String(), user.Role)
+// SwitchRestaurant exchanges the caller's session for tokens scoped to another
+// of their restaurants.
+func (s *AuthService) SwitchRestaurant(ctx context.Context, userID, restaurantID pgtype.UUID) (*jwt.TokenPair, error) {
+	return s.userService.SwitchRestaurant(ctx, userID, restaurantID)
 }
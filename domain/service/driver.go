@@ -0,0 +1,225 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/goodfoodcesi/auth-api/domain/events"
+	"github.com/goodfoodcesi/auth-api/domain/repository"
+	"github.com/goodfoodcesi/auth-api/infrastructure/database/sqlc"
+	"github.com/goodfoodcesi/auth-api/infrastructure/messaging/rabbitmq"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/zap"
+)
+
+var (
+	ErrDriverApplicationNotFound = errors.New("driver application not found")
+	ErrNotDriver                 = errors.New("only drivers can apply")
+	// ErrInvalidReviewTransition covers both illegal moves and applications
+	// that another reviewer already moved on.
+	ErrInvalidReviewTransition = errors.New("driver application cannot move to this status")
+	ErrRejectionNotesRequired  = errors.New("notes are required to reject an application")
+)
+
+// reviewTransitions lists, for each status, the statuses a reviewer may move
+// an application to. A rejected application only comes back through the
+// driver resubmitting it. A contract ending is reported by delivery-api, see
+// EndDriverContract.
+var reviewTransitions = map[db.DriverApplicationStatus][]db.DriverApplicationStatus{
+	db.DriverApplicationStatusSubmitted:     {db.DriverApplicationStatusUnderReview},
+	db.DriverApplicationStatusUnderReview:   {db.DriverApplicationStatusApproved, db.DriverApplicationStatusRejected},
+	db.DriverApplicationStatusApproved:      {},
+	db.DriverApplicationStatusRejected:      {},
+	db.DriverApplicationStatusContractEnded: {},
+}
+
+type DriverService struct {
+	repo        repository.DriverApplicationRepository
+	userService *UserService
+	logger      *zap.Logger
+}
+
+// DriverApplicationInput holds the documents a driver is checked against.
+type DriverApplicationInput struct {
+	VehicleType   string `json:"vehicle_type" validate:"required,oneof=bike scooter car"`
+	LicenseNumber string `json:"license_number" validate:"required,max=50"`
+	DocumentURL   string `json:"document_url" validate:"required,http_url,max=2048"`
+}
+
+type ReviewDriverInput struct {
+	Notes string `json:"notes" validate:"omitempty,max=2000"`
+}
+
+func NewDriverService(
+	repo repository.DriverApplicationRepository,
+	userService *UserService,
+	logger *zap.Logger,
+) *DriverService {
+	return &DriverService{
+		repo:        repo,
+		userService: userService,
+		logger:      logger,
+	}
+}
+
+func (s *DriverService) GetForUser(ctx context.Context, userID pgtype.UUID) (*db.DriverApplication, error) {
+	application, err := s.repo.GetByUserID(ctx, userID)
+	if errors.Is(err, repository.ErrDriverApplicationNotFound) {
+		return nil, ErrDriverApplicationNotFound
+	}
+	return application, err
+}
+
+// Submit files the application of a driver who joined through an invitation,
+// or sends a rejected application back to the review queue.
+func (s *DriverService) Submit(ctx context.Context, userID pgtype.UUID, role db.UserRole, input DriverApplicationInput) (*db.DriverApplication, error) {
+	if role != db.UserRoleDriver {
+		return nil, ErrNotDriver
+	}
+
+	application := newDriverApplication(userID, input)
+
+	existing, err := s.repo.GetByUserID(ctx, userID)
+	switch {
+	case errors.Is(err, repository.ErrDriverApplicationNotFound):
+		application, err = s.repo.Submit(ctx, application)
+	case err != nil:
+		return nil, err
+	case existing.Status == db.DriverApplicationStatusRejected:
+		application, err = s.repo.Resubmit(ctx, application)
+	default:
+		return nil, ErrInvalidReviewTransition
+	}
+	if errors.Is(err, repository.ErrDriverApplicationConflict) {
+		return nil, ErrInvalidReviewTransition
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("driver application submitted",
+		zap.String("application_id", application.ID.String()),
+		zap.String("user_id", userID.String()),
+	)
+
+	return application, nil
+}
+
+// List returns the applications in status, or the review queue when status is nil.
+func (s *DriverService) List(ctx context.Context, status *db.DriverApplicationStatus) ([]db.DriverApplication, error) {
+	filter := db.NullDriverApplicationStatus{}
+	if status != nil {
+		filter = db.NullDriverApplicationStatus{DriverApplicationStatus: *status, Valid: true}
+	}
+	return s.repo.List(ctx, filter)
+}
+
+func (s *DriverService) Get(ctx context.Context, id pgtype.UUID) (*db.DriverApplication, error) {
+	application, err := s.repo.GetByID(ctx, id)
+	if errors.Is(err, repository.ErrDriverApplicationNotFound) {
+		return nil, ErrDriverApplicationNotFound
+	}
+	return application, err
+}
+
+// Review moves an application to status on behalf of reviewerID and tells the
+// other services about the final decision.
+func (s *DriverService) Review(ctx context.Context, reviewerID, id pgtype.UUID, status db.DriverApplicationStatus, input ReviewDriverInput) (*db.DriverApplication, error) {
+	application, err := s.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	from := application.Status
+	if !canReview(from, status) {
+		return nil, ErrInvalidReviewTransition
+	}
+	if status == db.DriverApplicationStatusRejected && input.Notes == "" {
+		return nil, ErrRejectionNotesRequired
+	}
+
+	application.Status = status
+	application.ReviewedBy = reviewerID
+	if input.Notes != "" {
+		application.ReviewerNotes = pgtype.Text{String: input.Notes, Valid: true}
+	}
+
+	err = s.userService.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		application, err = s.repo.Review(ctx, application, from)
+		if err != nil {
+			return err
+		}
+		return s.enqueueReviewed(ctx, application)
+	})
+	if errors.Is(err, repository.ErrDriverApplicationConflict) {
+		return nil, ErrInvalidReviewTransition
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("driver application reviewed",
+		zap.String("application_id", id.String()),
+		zap.String("reviewer_id", reviewerID.String()),
+		zap.String("from", string(from)),
+		zap.String("to", string(status)),
+	)
+
+	return application, nil
+}
+
+// enqueueReviewed tells the other services about a final decision. It must
+// run in the transaction reviewing application.
+func (s *DriverService) enqueueReviewed(ctx context.Context, application *db.DriverApplication) error {
+	var routingKey string
+	var payload interface{}
+	switch application.Status {
+	case db.DriverApplicationStatusApproved:
+		routingKey = rabbitmq.DriverApprovedKey
+		payload = events.DriverApproved{
+			UserID:        application.UserID.String(),
+			ApplicationID: application.ID.String(),
+			ReviewedBy:    application.ReviewedBy.String(),
+			OccurredAt:    time.Now(),
+		}
+	case db.DriverApplicationStatusRejected:
+		routingKey = rabbitmq.DriverRejectedKey
+		payload = events.DriverRejected{
+			UserID:        application.UserID.String(),
+			ApplicationID: application.ID.String(),
+			ReviewedBy:    application.ReviewedBy.String(),
+			Notes:         application.ReviewerNotes.String,
+			OccurredAt:    time.Now(),
+		}
+	default:
+		return nil
+	}
+
+	return enqueue(ctx, s.userService.outbox, outboxEvent{
+		aggregateType: "driver_application",
+		aggregateID:   application.ID.String(),
+		eventType:     routingKey,
+		exchange:      rabbitmq.DriverExchange,
+		routingKey:    routingKey,
+		payload:       payload,
+	})
+}
+
+func canReview(from, to db.DriverApplicationStatus) bool {
+	for _, allowed := range reviewTransitions[from] {
+		if allowed == to {
+			return true
+		}
+	}
+	return false
+}
+
+func newDriverApplication(userID pgtype.UUID, input DriverApplicationInput) *db.DriverApplication {
+	return &db.DriverApplication{
+		UserID:        userID,
+		VehicleType:   input.VehicleType,
+		LicenseNumber: input.LicenseNumber,
+		DocumentUrl:   input.DocumentURL,
+	}
+}
@@ -0,0 +1,78 @@
+package service
+
+import (
+	"context"
+	"expvar"
+	"time"
+
+	"github.com/goodfoodcesi/auth-api/domain/repository"
+	"go.uber.org/zap"
+)
+
+const (
+	// inboxRetention must exceed the longest a message may be redelivered
+	// after, retries and dead-letter requeues included.
+	inboxRetention       = 7 * 24 * time.Hour
+	inboxCleanupInterval = time.Hour
+)
+
+// inboxMetrics is served with the other expvars on /debug/vars.
+var inboxMetrics = expvar.NewMap("inbox")
+
+// Inbox makes consumers idempotent: a message is handled at most once per
+// consumer, however many times it is delivered.
+type Inbox struct {
+	repo       repository.ProcessedMessageRepository
+	transactor repository.Transactor
+	logger     *zap.Logger
+}
+
+func NewInbox(repo repository.ProcessedMessageRepository, transactor repository.Transactor, logger *zap.Logger) *Inbox {
+	return &Inbox{
+		repo:       repo,
+		transactor: transactor,
+		logger:     logger,
+	}
+}
+
+// Process runs handle unless consumer already processed messageID. Both are
+// recorded in one transaction, which handle's writes join through ctx, so a
+// failed handle is not marked as processed.
+func (i *Inbox) Process(ctx context.Context, consumer, messageID string, handle func(ctx context.Context) error) error {
+	return i.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		fresh, err := i.repo.MarkProcessed(ctx, consumer, messageID)
+		if err != nil {
+			return err
+		}
+		if !fresh {
+			inboxMetrics.Add("duplicates", 1)
+			i.logger.Info("skipping duplicate message",
+				zap.String("consumer", consumer),
+				zap.String("message_id", messageID),
+			)
+			return nil
+		}
+		return handle(ctx)
+	})
+}
+
+// RunCleanup forgets old processed messages until ctx is done.
+func (i *Inbox) RunCleanup(ctx context.Context) {
+	ticker := time.NewTicker(inboxCleanupInterval)
+	defer ticker.Stop()
+
+	for {
+		deleted, err := i.repo.DeleteBefore(ctx, time.Now().Add(-inboxRetention))
+		if err != nil {
+			i.logger.Error("failed to clean up processed messages", zap.Error(err))
+		} else if deleted > 0 {
+			i.logger.Info("cleaned up processed messages", zap.Int64("deleted", deleted))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,213 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/goodfoodcesi/auth-api/crypto"
+	"github.com/goodfoodcesi/auth-api/domain/repository"
+	"github.com/goodfoodcesi/auth-api/infrastructure/database/sqlc"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/zap"
+)
+
+const defaultInvitationTTL = 7 * 24 * time.Hour
+
+var (
+	ErrInvitationNotFound  = errors.New("invitation not found")
+	ErrInvitationInvalid   = errors.New("invitation is invalid or expired")
+	ErrInvitationForbidden = errors.New("not allowed to manage this invitation")
+)
+
+type InvitationService struct {
+	repo        repository.InvitationRepository
+	memberships repository.MembershipRepository
+	userService *UserService
+	signer      *crypto.TokenSigner
+	logger      *zap.Logger
+}
+
+// Actor is the authenticated user performing a management action.
+type Actor struct {
+	ID   pgtype.UUID
+	Role db.UserRole
+	// Scopes is set when the actor called with an API key, which may do no
+	// more than its scopes allow whatever the role of its owner.
+	Scopes []string
+}
+
+type CreateInvitationInput struct {
+	Email          string      `json:"email" validate:"required,email"`
+	Role           db.UserRole `json:"role" validate:"required,oneof=manager driver admin"`
+	RestaurantID   string      `json:"restaurant_id" validate:"omitempty,uuid"`
+	ExpiresInHours int         `json:"expires_in_hours" validate:"omitempty,min=1,max=720"`
+}
+
+type RedeemInvitationInput struct {
+	Token     string `json:"token" validate:"required"`
+	FirstName string `json:"first_name" validate:"required,min=2,max=100"`
+	LastName  string `json:"last_name" validate:"required,min=2,max=100"`
+	Password  string `json:"password" validate:"required,min=8"`
+}
+
+func NewInvitationService(
+	repo repository.InvitationRepository,
+	memberships repository.MembershipRepository,
+	userService *UserService,
+	signer *crypto.TokenSigner,
+	logger *zap.Logger,
+) *InvitationService {
+	return &InvitationService{
+		repo:        repo,
+		memberships: memberships,
+		userService: userService,
+		signer:      signer,
+		logger:      logger,
+	}
+}
+
+// Create returns the invitation together with its token, which is only
+// available at creation time since just its hash is stored.
+func (s *InvitationService) Create(ctx context.Context, inviter Actor, input CreateInvitationInput) (*db.Invitation, string, error) {
+	invitation := &db.Invitation{
+		Email:     input.Email,
+		Role:      input.Role,
+		InvitedBy: inviter.ID,
+	}
+	if input.RestaurantID != "" {
+		restaurantID, err := parseUUID(input.RestaurantID)
+		if err != nil {
+			return nil, "", err
+		}
+		invitation.RestaurantID = restaurantID
+	}
+
+	// Restaurant managers may only bring in staff for a restaurant they own.
+	if inviter.Role != db.UserRoleAdmin {
+		if inviter.Role != db.UserRoleManager || input.Role != db.UserRoleManager || !invitation.RestaurantID.Valid {
+			return nil, "", ErrInvitationForbidden
+		}
+		membership, err := s.memberships.Get(ctx, inviter.ID, invitation.RestaurantID)
+		if errors.Is(err, repository.ErrMembershipNotFound) {
+			return nil, "", ErrInvitationForbidden
+		}
+		if err != nil {
+			return nil, "", err
+		}
+		if membership.Role != db.MembershipRoleOwner {
+			return nil, "", ErrInvitationForbidden
+		}
+	}
+
+	ttl := defaultInvitationTTL
+	if input.ExpiresInHours > 0 {
+		ttl = time.Duration(input.ExpiresInHours) * time.Hour
+	}
+	invitation.ExpiresAt = pgtype.Timestamptz{Time: time.Now().Add(ttl), Valid: true}
+
+	token, err := s.signer.Generate()
+	if err != nil {
+		return nil, "", err
+	}
+	invitation.TokenHash = s.signer.Hash(token)
+
+	invitation, err = s.repo.Create(ctx, invitation)
+	if err != nil {
+		return nil, "", err
+	}
+
+	s.logger.Info("invitation created",
+		zap.String("invitation_id", invitation.ID.String()),
+		zap.String("invited_by", inviter.ID.String()),
+		zap.String("role", string(invitation.Role)),
+	)
+
+	return invitation, token, nil
+}
+
+func (s *InvitationService) List(ctx context.Context, inviter Actor) ([]db.Invitation, error) {
+	if inviter.Role == db.UserRoleAdmin {
+		return s.repo.List(ctx, pgtype.UUID{})
+	}
+	return s.repo.List(ctx, inviter.ID)
+}
+
+func (s *InvitationService) Revoke(ctx context.Context, inviter Actor, id pgtype.UUID) error {
+	invitation, err := s.repo.GetByID(ctx, id)
+	if errors.Is(err, repository.ErrInvitationNotFound) {
+		return ErrInvitationNotFound
+	}
+	if err != nil {
+		return err
+	}
+	if inviter.Role != db.UserRoleAdmin && invitation.InvitedBy != inviter.ID {
+		return ErrInvitationForbidden
+	}
+
+	err = s.repo.Revoke(ctx, id)
+	if errors.Is(err, repository.ErrInvitationUnusable) {
+		return ErrInvitationInvalid
+	}
+	return err
+}
+
+// Redeem creates the invited account. The email and role always come from
+// the invitation, never from the request.
+func (s *InvitationService) Redeem(ctx context.Context, input RedeemInvitationInput) (*db.User, error) {
+	if !s.signer.Verify(input.Token) {
+		return nil, ErrInvitationInvalid
+	}
+
+	invitation, err := s.repo.GetByTokenHash(ctx, s.signer.Hash(input.Token))
+	if errors.Is(err, repository.ErrInvitationNotFound) {
+		return nil, ErrInvitationInvalid
+	}
+	if err != nil {
+		return nil, err
+	}
+	if invitation.AcceptedAt.Valid || invitation.RevokedAt.Valid || time.Now().After(invitation.ExpiresAt.Time) {
+		return nil, ErrInvitationInvalid
+	}
+
+	_, err = s.userService.repo.GetByEmail(ctx, invitation.Email)
+	if err == nil {
+		return nil, ErrEmailExists
+	}
+	if !errors.Is(err, repository.ErrUserNotFound) {
+		return nil, err
+	}
+
+	hashedPassword, err := s.userService.pwdManager.HashPassword(input.Password)
+	if err != nil {
+		return nil, err
+	}
+
+	var user *db.User
+	err = s.userService.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		user, err = s.repo.Redeem(ctx, invitation, &db.User{
+			Firstname:    input.FirstName,
+			Lastname:     input.LastName,
+			Email:        invitation.Email,
+			PasswordHash: hashedPassword,
+			Role:         invitation.Role,
+		})
+		if err != nil {
+			return err
+		}
+		return s.userService.enqueueUserCreated(ctx, user)
+	})
+	if errors.Is(err, repository.ErrInvitationUnusable) {
+		return nil, ErrInvitationInvalid
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("invitation redeemed",
+		zap.String("invitation_id", invitation.ID.String()),
+		zap.String("user_id", user.ID.String()),
+	)
+
+	return user, nil
+}
@@ -0,0 +1,147 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/goodfoodcesi/auth-api/domain/repository"
+	"github.com/goodfoodcesi/auth-api/infrastructure/database/sqlc"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/zap"
+)
+
+var (
+	ErrMembershipNotFound  = errors.New("membership not found")
+	ErrMembershipForbidden = errors.New("not allowed to manage this restaurant")
+	// ErrMembershipNotManager is returned when adding a user whose account
+	// role cannot run a restaurant.
+	ErrMembershipNotManager = errors.New("only managers can be restaurant members")
+)
+
+type MembershipService struct {
+	repo     repository.MembershipRepository
+	userRepo repository.UserRepository
+	logger   *zap.Logger
+}
+
+type SaveMembershipInput struct {
+	Role db.MembershipRole `json:"role" validate:"required,oneof=owner staff"`
+}
+
+func NewMembershipService(repo repository.MembershipRepository, userRepo repository.UserRepository, logger *zap.Logger) *MembershipService {
+	return &MembershipService{
+		repo:     repo,
+		userRepo: userRepo,
+		logger:   logger,
+	}
+}
+
+func (s *MembershipService) ListMine(ctx context.Context, userID pgtype.UUID) ([]db.Membership, error) {
+	return s.repo.ListByUser(ctx, userID)
+}
+
+// ListForRestaurant is open to admins and to the restaurant's own members.
+func (s *MembershipService) ListForRestaurant(ctx context.Context, actor Actor, restaurantID pgtype.UUID) ([]db.Membership, error) {
+	if actor.Role != db.UserRoleAdmin {
+		if _, err := s.repo.Get(ctx, actor.ID, restaurantID); err != nil {
+			if errors.Is(err, repository.ErrMembershipNotFound) {
+				return nil, ErrMembershipForbidden
+			}
+			return nil, err
+		}
+	}
+	return s.repo.ListByRestaurant(ctx, restaurantID)
+}
+
+// Save adds userID to the restaurant or changes their membership role.
+func (s *MembershipService) Save(ctx context.Context, actor Actor, restaurantID, userID pgtype.UUID, input SaveMembershipInput) (*db.Membership, error) {
+	if actor.ID == userID {
+		return nil, ErrSelfManagement
+	}
+	if err := s.authorizeOwner(ctx, actor, restaurantID); err != nil {
+		return nil, err
+	}
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if errors.Is(err, repository.ErrUserNotFound) {
+		return nil, ErrUserNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if user.Role != db.UserRoleManager {
+		return nil, ErrMembershipNotManager
+	}
+
+	membership, err := s.repo.Save(ctx, &db.Membership{
+		UserID:       userID,
+		RestaurantID: restaurantID,
+		Role:         input.Role,
+		CreatedBy:    actor.ID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("membership saved",
+		zap.String("restaurant_id", restaurantID.String()),
+		zap.String("user_id", userID.String()),
+		zap.String("actor_id", actor.ID.String()),
+		zap.String("role", string(input.Role)),
+	)
+
+	return membership, nil
+}
+
+func (s *MembershipService) Remove(ctx context.Context, actor Actor, restaurantID, userID pgtype.UUID) error {
+	if actor.ID == userID {
+		return ErrSelfManagement
+	}
+	if err := s.authorizeOwner(ctx, actor, restaurantID); err != nil {
+		return err
+	}
+
+	err := s.repo.Delete(ctx, userID, restaurantID)
+	if errors.Is(err, repository.ErrMembershipNotFound) {
+		return ErrMembershipNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	s.logger.Info("membership removed",
+		zap.String("restaurant_id", restaurantID.String()),
+		zap.String("user_id", userID.String()),
+		zap.String("actor_id", actor.ID.String()),
+	)
+
+	return nil
+}
+
+// authorizeOwner lets admins and the restaurant's owners manage its members.
+func (s *MembershipService) authorizeOwner(ctx context.Context, actor Actor, restaurantID pgtype.UUID) error {
+	owner, err := isRestaurantOwner(ctx, s.repo, actor, restaurantID)
+	if err != nil {
+		return err
+	}
+	if !owner {
+		return ErrMembershipForbidden
+	}
+	return nil
+}
+
+// isRestaurantOwner tells whether actor may manage restaurantID, which admins
+// always may.
+func isRestaurantOwner(ctx context.Context, memberships repository.MembershipRepository, actor Actor, restaurantID pgtype.UUID) (bool, error) {
+	if actor.Role == db.UserRoleAdmin {
+		return true, nil
+	}
+	membership, err := memberships.Get(ctx, actor.ID, restaurantID)
+	if errors.Is(err, repository.ErrMembershipNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return membership.Role == db.MembershipRoleOwner, nil
+}
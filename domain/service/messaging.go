@@ -2,96 +2,47 @@ package service
 
 import (
 	"context"
+	"errors"
+	"fmt"
 
-	"github.com/goodfoodcesi/api-utils-go/pkg/event"
-
+	"github.com/goodfoodcesi/auth-api/domain/events"
+	"github.com/goodfoodcesi/auth-api/domain/messaging"
 	"github.com/goodfoodcesi/auth-api/infrastructure/messaging/rabbitmq"
 	"go.uber.org/zap"
 )
 
+// ErrMessagingUnavailable is returned when the broker cannot take a message
+// right now. The request may be retried later.
+var ErrMessagingUnavailable = errors.New("messaging is temporarily unavailable")
+
 type MessagingService struct {
-	rabbit *rabbitmq.RabbitMQ
-	logger *zap.Logger
+	publisher messaging.Publisher
+	logger    *zap.Logger
 }
 
-func NewMessagingService(rabbit *rabbitmq.RabbitMQ, logger *zap.Logger) (*MessagingService, error) {
-	// Déclarer les exchanges
-	exchanges := []rabbitmq.ExchangeConfig{
-		{
-			Name:       rabbitmq.ClientExchange,
-			Type:       rabbitmq.FanoutExchange,
-			Durable:    true,
-			AutoDelete: false,
-			Internal:   false,
-			NoWait:     false,
-		},
-	}
-
-	for _, ex := range exchanges {
-		if err := rabbit.DeclareExchange(ex); err != nil {
-			return nil, err
-		}
-	}
-
-	// Déclarer les queues
-	queues := []rabbitmq.QueueConfig{
-		{
-			Name:       rabbitmq.ClientCreatedQueueNotificationAPI,
-			Durable:    true,
-			AutoDelete: false,
-			Exclusive:  false,
-			NoWait:     false,
-		},
-		{
-			Name:       rabbitmq.ClientCreatedQueueClientAPI,
-			Durable:    true,
-			AutoDelete: false,
-			Exclusive:  false,
-			NoWait:     false,
-		},
-	}
-
-	for _, q := range queues {
-		if err := rabbit.DeclareQueue(q); err != nil {
-			return nil, err
-		}
-	}
-
-	// Déclarer les bindings
-	bindings := []rabbitmq.BindingConfig{
-		{
-			Queue:    rabbitmq.ClientCreatedQueueNotificationAPI,
-			Exchange: rabbitmq.ClientExchange,
-			NoWait:   false,
-		},
-		{
-			Queue:    rabbitmq.ClientCreatedQueueClientAPI,
-			Exchange: rabbitmq.ClientExchange,
-			NoWait:   false,
-		},
-	}
-
-	for _, b := range bindings {
-		if err := rabbit.BindQueue(b); err != nil {
-			return nil, err
-		}
-	}
-
+func NewMessagingService(publisher messaging.Publisher, logger *zap.Logger) *MessagingService {
 	return &MessagingService{
-		rabbit: rabbit,
-		logger: logger,
-	}, nil
+		publisher: publisher,
+		logger:    logger,
+	}
 }
 
-func (s *MessagingService) PublishUserCreated(ctx context.Context, userCreatedEvent event.UserCreatedEvent) error {
-	// Publier dans l'exchange utilisateur
-	err := s.rabbit.Publish(ctx, rabbitmq.ClientExchange, "", userCreatedEvent)
+func (s *MessagingService) PublishSmsOtpRequested(ctx context.Context, smsOtpRequested events.SmsOtpRequested) error {
+	err := s.publisher.Publish(ctx, rabbitmq.NotificationExchange, rabbitmq.SmsOtpKey, smsOtpRequested)
 	if err != nil {
-		s.logger.Error("failed to publish user created event", zap.Error(err))
-		return err
+		s.logger.Error("failed to publish sms otp requested event", zap.Error(err))
+		return messagingError(err)
 	}
 
-	s.logger.Info("user created event published")
+	s.logger.Info("sms otp requested event published")
 
 	return nil
 }
+
+// messagingError tells callers whether trying again later may succeed.
+func messagingError(err error) error {
+	if errors.Is(err, messaging.ErrNotConnected) || errors.Is(err, messaging.ErrNacked) || errors.Is(err, messaging.ErrConfirmTimeout) {
+		return fmt.Errorf("%w: %w", ErrMessagingUnavailable, err)
+	}
+	return err
+}
@@ -0,0 +1,183 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"expvar"
+	"time"
+
+	"github.com/goodfoodcesi/auth-api/domain/events"
+	"github.com/goodfoodcesi/auth-api/domain/messaging"
+	"github.com/goodfoodcesi/auth-api/domain/repository"
+	"github.com/goodfoodcesi/auth-api/infrastructure/database/sqlc"
+	"github.com/goodfoodcesi/auth-api/infrastructure/messaging/schema"
+	"go.uber.org/zap"
+)
+
+const (
+	outboxBatchSize  = 100
+	outboxInterval   = time.Second
+	outboxLease      = 30 * time.Second
+	outboxMinBackoff = time.Second
+	outboxMaxBackoff = 5 * time.Minute
+)
+
+// outboxMetrics is served with the other expvars on /debug/vars.
+var outboxMetrics = expvar.NewMap("outbox")
+
+// outboxEvent is an event to publish once the transaction writing it commits.
+type outboxEvent struct {
+	aggregateType string
+	aggregateID   string
+	eventType     string
+	exchange      string
+	routingKey    string
+	payload       interface{}
+}
+
+// enqueue writes event to the outbox. It must run within the transaction of
+// the change the event describes, so neither exists without the other. The
+// envelope is built now, while ctx still carries the request id.
+func enqueue(ctx context.Context, outbox repository.OutboxRepository, event outboxEvent) error {
+	envelope, err := messaging.NewEnvelope(ctx, events.Schemas, event.eventType, event.payload)
+	if err != nil {
+		return err
+	}
+	payload, err := json.Marshal(envelope)
+	if err != nil {
+		return err
+	}
+	return outbox.Add(ctx, &db.Outbox{
+		AggregateType: event.aggregateType,
+		AggregateID:   event.aggregateID,
+		EventType:     event.eventType,
+		Exchange:      event.exchange,
+		RoutingKey:    event.routingKey,
+		Payload:       payload,
+	})
+}
+
+// outboxEnvelope reads back the envelope stored by enqueue. Messages written
+// before envelopes existed hold the bare payload and are wrapped now.
+func outboxEnvelope(message db.Outbox) *messaging.Envelope {
+	var envelope messaging.Envelope
+	if err := json.Unmarshal(message.Payload, &envelope); err == nil && envelope.Producer != "" && envelope.Data != nil {
+		return &envelope
+	}
+	return &messaging.Envelope{
+		ID:         message.ID.String(),
+		Type:       message.EventType,
+		Version:    1,
+		OccurredAt: message.CreatedAt.Time,
+		Producer:   messaging.Producer,
+		Data:       message.Payload,
+	}
+}
+
+// OutboxRelay publishes outbox messages to the broker. Several relays may run
+// at once: each message is leased to one of them while it is published.
+type OutboxRelay struct {
+	repo      repository.OutboxRepository
+	publisher messaging.Publisher
+	logger    *zap.Logger
+}
+
+func NewOutboxRelay(repo repository.OutboxRepository, publisher messaging.Publisher, logger *zap.Logger) *OutboxRelay {
+	return &OutboxRelay{
+		repo:      repo,
+		publisher: publisher,
+		logger:    logger,
+	}
+}
+
+// Run relays messages until ctx is done.
+func (r *OutboxRelay) Run(ctx context.Context) {
+	ticker := time.NewTicker(outboxInterval)
+	defer ticker.Stop()
+
+	for {
+		for {
+			relayed, err := r.RelayBatch(ctx)
+			if err != nil {
+				r.logger.Error("failed to relay outbox messages", zap.Error(err))
+			}
+			// A full batch means more messages are probably due.
+			if err != nil || relayed < outboxBatchSize {
+				break
+			}
+		}
+		r.recordLag(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RelayBatch publishes the messages that are due and returns how many it
+// claimed. A message the broker does not confirm is retried with back-off.
+func (r *OutboxRelay) RelayBatch(ctx context.Context) (int, error) {
+	messages, err := r.repo.Claim(ctx, outboxBatchSize, time.Now().Add(outboxLease))
+	if err != nil {
+		return 0, err
+	}
+
+	for _, message := range messages {
+		err := r.publisher.PublishEnvelope(ctx, message.Exchange, message.RoutingKey, outboxEnvelope(message))
+		if err != nil {
+			outboxMetrics.Add("failed", 1)
+			r.logger.Error("failed to publish outbox message",
+				zap.String("message_id", message.ID.String()),
+				zap.String("event_type", message.EventType),
+				zap.Int32("attempts", message.Attempts+1),
+				zap.Error(err),
+			)
+			delay := outboxBackoff(message.Attempts)
+			if errors.Is(err, messaging.ErrUnroutable) || errors.Is(err, schema.ErrInvalidPayload) || errors.Is(err, schema.ErrUnknownSchema) {
+				// Retrying will not help until the topology or schema is fixed.
+				delay = outboxMaxBackoff
+			}
+			retryAt := time.Now().Add(delay)
+			if err := r.repo.MarkFailed(ctx, message.ID, err.Error(), retryAt); err != nil {
+				return 0, err
+			}
+			continue
+		}
+
+		if err := r.repo.MarkSent(ctx, message.ID); err != nil {
+			return 0, err
+		}
+		outboxMetrics.Add("published", 1)
+	}
+
+	return len(messages), nil
+}
+
+func (r *OutboxRelay) recordLag(ctx context.Context) {
+	pending, oldest, err := r.repo.Lag(ctx)
+	if err != nil {
+		r.logger.Error("failed to measure outbox lag", zap.Error(err))
+		return
+	}
+
+	lag := new(expvar.Float)
+	if !oldest.IsZero() {
+		lag.Set(time.Since(oldest).Seconds())
+	}
+	count := new(expvar.Int)
+	count.Set(pending)
+	outboxMetrics.Set("lag_seconds", lag)
+	outboxMetrics.Set("pending", count)
+}
+
+// outboxBackoff doubles the delay after each failed attempt.
+func outboxBackoff(attempts int32) time.Duration {
+	delay := outboxMinBackoff
+	for i := int32(0); i < attempts && delay < outboxMaxBackoff; i++ {
+		delay *= 2
+	}
+	return min(delay, outboxMaxBackoff)
+}
@@ -0,0 +1,162 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/goodfoodcesi/auth-api/domain/events"
+	"github.com/goodfoodcesi/auth-api/domain/repository/repotest"
+	"github.com/goodfoodcesi/auth-api/domain/service"
+	"github.com/goodfoodcesi/auth-api/infrastructure/database/sqlc"
+	"github.com/goodfoodcesi/auth-api/infrastructure/messaging/memory"
+	"github.com/goodfoodcesi/auth-api/infrastructure/messaging/rabbitmq"
+	"go.uber.org/zap"
+)
+
+func newBroker(t *testing.T, topology rabbitmq.Topology) *memory.Broker {
+	t.Helper()
+
+	broker := memory.NewBroker(events.Schemas)
+	if err := broker.DeclareTopology(topology); err != nil {
+		t.Fatalf("DeclareTopology: %v", err)
+	}
+	return broker
+}
+
+func TestRegisterPublishesUserCreatedThroughTheOutbox(t *testing.T) {
+	ctx := context.Background()
+	users := newUsers()
+	broker := newBroker(t, rabbitmq.AuthTopology)
+	relay := service.NewOutboxRelay(users.outbox, broker, zap.NewNop())
+
+	user, err := users.Register(ctx, service.RegisterUserInput{
+		FirstName: "Jane",
+		LastName:  "Doe",
+		Email:     "jane@example.com",
+		Password:  "correct horse",
+	})
+	if err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+	if published := broker.Published(); len(published) != 0 {
+		t.Fatalf("published %d events before the relay ran", len(published))
+	}
+
+	relayed, err := relay.RelayBatch(ctx)
+	if err != nil {
+		t.Fatalf("RelayBatch: %v", err)
+	}
+	if relayed != 1 {
+		t.Fatalf("relayed %d messages, want 1", relayed)
+	}
+
+	published := broker.PublishedOfType(rabbitmq.UserCreatedKey)
+	if len(published) != 1 {
+		t.Fatalf("published %d %s events, want 1", len(published), rabbitmq.UserCreatedKey)
+	}
+	var event events.UserCreated
+	if err := published[0].Decode(&event); err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+	if event.ID != user.ID || event.Email != "jane@example.com" {
+		t.Errorf("published user %s <%s>, want %s <jane@example.com>", event.ID, event.Email, user.ID)
+	}
+
+	for _, queue := range []string{rabbitmq.ClientCreatedQueueClientAPI, rabbitmq.ClientCreatedQueueNotificationAPI} {
+		if pending := broker.Pending(queue); len(pending) != 1 {
+			t.Errorf("%s holds %d events, want 1", queue, len(pending))
+		}
+	}
+	for _, message := range users.outbox.Messages() {
+		if !message.SentAt.Valid {
+			t.Errorf("outbox message %s was not marked sent", message.EventType)
+		}
+	}
+}
+
+func TestSuspendForFraudPublishesUserSuspended(t *testing.T) {
+	ctx := context.Background()
+	id := repotest.NewID()
+	users := newUsers(&db.User{ID: id, Email: "jane@example.com", Status: db.UserStatusActive})
+	broker := newBroker(t, rabbitmq.AuthTopology)
+	relay := service.NewOutboxRelay(users.outbox, broker, zap.NewNop())
+
+	accounts := service.NewAccountSyncService(users.repo, users.drivers, users.memberships, users.outbox, repotest.Transactor{}, zap.NewNop())
+
+	if err := accounts.SuspendForFraud(ctx, id, "chargebacks"); err != nil {
+		t.Fatalf("SuspendForFraud: %v", err)
+	}
+	if _, err := relay.RelayBatch(ctx); err != nil {
+		t.Fatalf("RelayBatch: %v", err)
+	}
+
+	published := broker.PublishedOfType(rabbitmq.UserSuspendedKey)
+	if len(published) != 1 {
+		t.Fatalf("published %d %s events, want 1", len(published), rabbitmq.UserSuspendedKey)
+	}
+	var event events.UserSuspended
+	if err := published[0].Decode(&event); err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+	if event.UserID != id.String() || event.Status != string(db.UserStatusSuspended) || event.Reason != "chargebacks" {
+		t.Errorf("published %+v", event)
+	}
+	if pending := broker.Pending(rabbitmq.UserStatusQueueOrderAPI); len(pending) != 1 {
+		t.Errorf("%s holds %d events, want 1", rabbitmq.UserStatusQueueOrderAPI, len(pending))
+	}
+
+	// The account is locked out already, flagging it again changes nothing.
+	if err := accounts.SuspendForFraud(ctx, id, "chargebacks"); err != nil {
+		t.Fatalf("SuspendForFraud again: %v", err)
+	}
+	if messages := users.outbox.Messages(); len(messages) != 1 {
+		t.Errorf("outbox holds %d messages, want 1", len(messages))
+	}
+}
+
+func TestOutboxRelayHoldsBackUnroutableMessages(t *testing.T) {
+	ctx := context.Background()
+	users := newUsers()
+	// The exchanges exist but no queue is bound to them.
+	broker := newBroker(t, rabbitmq.Topology{Exchanges: rabbitmq.AuthTopology.Exchanges})
+	relay := service.NewOutboxRelay(users.outbox, broker, zap.NewNop())
+
+	_, err := users.Register(ctx, service.RegisterUserInput{
+		FirstName: "Jane",
+		LastName:  "Doe",
+		Email:     "jane@example.com",
+		Password:  "correct horse",
+	})
+	if err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+
+	before := time.Now()
+	if _, err := relay.RelayBatch(ctx); err != nil {
+		t.Fatalf("RelayBatch: %v", err)
+	}
+	if published := broker.Published(); len(published) != 0 {
+		t.Fatalf("published %d unroutable events", len(published))
+	}
+
+	message := users.outbox.Messages()[0]
+	if message.SentAt.Valid {
+		t.Fatal("unroutable message was marked sent")
+	}
+	if message.Attempts != 1 || !message.LastError.Valid {
+		t.Errorf("attempts = %d, last error = %q", message.Attempts, message.LastError.String)
+	}
+	// Retrying will not help until the topology is fixed.
+	if retryIn := message.AvailableAt.Time.Sub(before); retryIn < 4*time.Minute {
+		t.Errorf("retried in %s, want the longest back-off", retryIn)
+	}
+
+	relayed, err := relay.RelayBatch(ctx)
+	if err != nil {
+		t.Fatalf("RelayBatch again: %v", err)
+	}
+	if relayed != 0 {
+		t.Errorf("relayed %d messages before they were due", relayed)
+	}
+}
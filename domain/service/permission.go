@@ -0,0 +1,108 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/goodfoodcesi/auth-api/domain/repository"
+	"github.com/goodfoodcesi/auth-api/infrastructure/database/sqlc"
+	"go.uber.org/zap"
+)
+
+// PermissionsManage is the permission needed to edit role permissions, which
+// admins always keep so they cannot lock themselves out.
+const PermissionsManage = "permissions:manage"
+
+var (
+	ErrUnknownPermission = errors.New("unknown permission")
+	ErrPermissionLockout = errors.New("admins must keep the permissions:manage permission")
+)
+
+type PermissionService struct {
+	repo   repository.PermissionRepository
+	logger *zap.Logger
+}
+
+type SetRolePermissionsInput struct {
+	Permissions []string `json:"permissions" validate:"required,dive,required,max=100"`
+}
+
+func NewPermissionService(repo repository.PermissionRepository, logger *zap.Logger) *PermissionService {
+	return &PermissionService{
+		repo:   repo,
+		logger: logger,
+	}
+}
+
+func (s *PermissionService) List(ctx context.Context) ([]db.Permission, error) {
+	return s.repo.List(ctx)
+}
+
+// Grants returns the permissions of every role, roles without any included.
+func (s *PermissionService) Grants(ctx context.Context) (map[db.UserRole][]string, error) {
+	grants, err := s.repo.ListGrants(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	byRole := map[db.UserRole][]string{
+		db.UserRoleClient:  {},
+		db.UserRoleManager: {},
+		db.UserRoleDriver:  {},
+		db.UserRoleAdmin:   {},
+	}
+	for _, grant := range grants {
+		byRole[grant.Role] = append(byRole[grant.Role], grant.Permission)
+	}
+	return byRole, nil
+}
+
+func (s *PermissionService) ForRole(ctx context.Context, role db.UserRole) ([]string, error) {
+	permissions, err := s.repo.ForRole(ctx, role)
+	if err != nil {
+		return nil, err
+	}
+	if permissions == nil {
+		permissions = []string{}
+	}
+	return permissions, nil
+}
+
+// SetForRole replaces the permissions of role. Tokens already issued keep
+// their permissions until they are refreshed.
+func (s *PermissionService) SetForRole(ctx context.Context, role db.UserRole, input SetRolePermissionsInput) ([]string, error) {
+	known, err := s.repo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	catalog := make(map[string]bool, len(known))
+	for _, permission := range known {
+		catalog[permission.Name] = true
+	}
+
+	unique := make([]string, 0, len(input.Permissions))
+	seen := make(map[string]bool, len(input.Permissions))
+	for _, permission := range input.Permissions {
+		if !catalog[permission] {
+			return nil, ErrUnknownPermission
+		}
+		if !seen[permission] {
+			seen[permission] = true
+			unique = append(unique, permission)
+		}
+	}
+	if role == db.UserRoleAdmin && !seen[PermissionsManage] {
+		return nil, ErrPermissionLockout
+	}
+
+	if err := s.repo.SetForRole(ctx, role, unique); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("role permissions updated",
+		zap.String("role", string(role)),
+		zap.Strings("permissions", unique),
+	)
+
+	return s.ForRole(ctx, role)
+}
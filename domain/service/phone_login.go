@@ -0,0 +1,159 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/goodfoodcesi/auth-api/crypto"
+	"github.com/goodfoodcesi/auth-api/domain/events"
+	"github.com/goodfoodcesi/auth-api/domain/repository"
+	"github.com/goodfoodcesi/auth-api/infrastructure/database/sqlc"
+	"github.com/goodfoodcesi/auth-api/infrastructure/jwt"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/zap"
+)
+
+const (
+	otpTTL            = 5 * time.Minute
+	otpMaxAttempts    = 5
+	otpThrottleWindow = 15 * time.Minute
+	otpMaxPerWindow   = 3
+)
+
+var (
+	ErrOtpThrottled        = errors.New("too many codes requested for this phone number")
+	ErrOtpInvalid          = errors.New("invalid or expired code")
+	ErrOtpAttemptsExceeded = errors.New("too many attempts for this code")
+)
+
+// SmsOtpPublisher hands a login code over to the notification pipeline.
+type SmsOtpPublisher interface {
+	PublishSmsOtpRequested(ctx context.Context, smsOtpRequested events.SmsOtpRequested) error
+}
+
+type PhoneLoginService struct {
+	userRepo    repository.UserRepository
+	otpRepo     repository.PhoneOtpRepository
+	userService *UserService
+	otpManager  *crypto.OTPManager
+	publisher   SmsOtpPublisher
+	logger      *zap.Logger
+}
+
+type PhoneLoginInput struct {
+	PhoneNumber string `json:"phone_number" validate:"required,e164"`
+}
+
+type PhoneVerifyInput struct {
+	PhoneNumber string `json:"phone_number" validate:"required,e164"`
+	Code        string `json:"code" validate:"required,numeric,len=6"`
+}
+
+func NewPhoneLoginService(
+	userRepo repository.UserRepository,
+	otpRepo repository.PhoneOtpRepository,
+	userService *UserService,
+	otpManager *crypto.OTPManager,
+	publisher SmsOtpPublisher,
+	logger *zap.Logger,
+) *PhoneLoginService {
+	return &PhoneLoginService{
+		userRepo:    userRepo,
+		otpRepo:     otpRepo,
+		userService: userService,
+		otpManager:  otpManager,
+		publisher:   publisher,
+		logger:      logger,
+	}
+}
+
+// RequestCode texts a login code to the number. Unknown numbers are silently
+// ignored so the endpoint cannot be used to discover registered phones.
+func (s *PhoneLoginService) RequestCode(ctx context.Context, input PhoneLoginInput) error {
+	sent, err := s.otpRepo.CountSince(ctx, input.PhoneNumber, time.Now().Add(-otpThrottleWindow))
+	if err != nil {
+		return err
+	}
+	if sent >= otpMaxPerWindow {
+		return ErrOtpThrottled
+	}
+
+	user, _ := s.userRepo.GetByPhone(ctx, input.PhoneNumber)
+	if user == nil {
+		s.logger.Info("phone login requested for unknown number")
+		return nil
+	}
+
+	code, err := s.otpManager.GenerateCode()
+	if err != nil {
+		return err
+	}
+
+	expiresAt := time.Now().Add(otpTTL)
+	_, err = s.otpRepo.Create(ctx, &db.PhoneOtp{
+		PhoneNumber: input.PhoneNumber,
+		CodeHash:    s.otpManager.HashCode(input.PhoneNumber, code),
+		ExpiresAt:   pgtype.Timestamptz{Time: expiresAt, Valid: true},
+	})
+	if err != nil {
+		return err
+	}
+
+	return s.publisher.PublishSmsOtpRequested(ctx, events.SmsOtpRequested{
+		PhoneNumber: input.PhoneNumber,
+		Code:        code,
+		Locale:      user.Locale,
+		ExpiresAt:   expiresAt,
+	})
+}
+
+// VerifyCode exchanges a valid code for a token pair and marks the phone as verified.
+func (s *PhoneLoginService) VerifyCode(ctx context.Context, input PhoneVerifyInput) (*jwt.TokenPair, error) {
+	otp, _ := s.otpRepo.GetActive(ctx, input.PhoneNumber)
+	if otp == nil {
+		return nil, ErrOtpInvalid
+	}
+
+	// The attempt is counted before the code is compared, so parallel guesses
+	// cannot all get past the limit.
+	attempts, ok, err := s.otpRepo.IncrementAttempts(ctx, otp.ID, otpMaxAttempts)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrOtpAttemptsExceeded
+	}
+
+	if !s.otpManager.CompareCode(otp.CodeHash, input.PhoneNumber, input.Code) {
+		if attempts >= otpMaxAttempts {
+			return nil, ErrOtpAttemptsExceeded
+		}
+		return nil, ErrOtpInvalid
+	}
+
+	consumed, err := s.otpRepo.Consume(ctx, otp.ID)
+	if err != nil {
+		return nil, err
+	}
+	if !consumed {
+		return nil, ErrOtpInvalid
+	}
+
+	user, err := s.userRepo.GetByPhone(ctx, input.PhoneNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.userRepo.MarkPhoneVerified(ctx, user.ID); err != nil {
+		s.logger.Error("failed to mark phone as verified", zap.Error(err))
+	}
+
+	tokens, err := s.userService.IssueTokens(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+
+	s.userService.recordLogin(ctx, user, "phone")
+	return tokens, nil
+}
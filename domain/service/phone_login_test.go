@@ -0,0 +1,174 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/goodfoodcesi/auth-api/crypto"
+	"github.com/goodfoodcesi/auth-api/domain/repository/repotest"
+	"github.com/goodfoodcesi/auth-api/domain/service"
+	"github.com/goodfoodcesi/auth-api/infrastructure/database/sqlc"
+	"github.com/goodfoodcesi/auth-api/infrastructure/messaging/memory"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/zap"
+)
+
+const phoneNumber = "+33612345678"
+
+// phoneLogin is a PhoneLoginService whose codes are kept by an in-memory
+// publisher instead of being texted.
+type phoneLogin struct {
+	*service.PhoneLoginService
+	users *users
+	sms   *memory.SmsOtpPublisher
+}
+
+func newPhoneLogin(t *testing.T) *phoneLogin {
+	t.Helper()
+
+	u := newUsers(&db.User{
+		ID:          repotest.NewID(),
+		Email:       "jane@example.com",
+		Role:        db.UserRoleClient,
+		PhoneNumber: pgtype.Text{String: phoneNumber, Valid: true},
+	})
+	sms := memory.NewSmsOtpPublisher()
+	return &phoneLogin{
+		PhoneLoginService: service.NewPhoneLoginService(u.repo, repotest.NewPhoneOtps(), u.UserService, crypto.NewOTPManager("otp-secret"), sms, zap.NewNop()),
+		users:             u,
+		sms:               sms,
+	}
+}
+
+// requestCode asks for a code and returns the one that was texted.
+func (p *phoneLogin) requestCode(t *testing.T) string {
+	t.Helper()
+
+	if err := p.RequestCode(context.Background(), service.PhoneLoginInput{PhoneNumber: phoneNumber}); err != nil {
+		t.Fatalf("RequestCode: %v", err)
+	}
+	code, ok := p.sms.LastCode(phoneNumber)
+	if !ok {
+		t.Fatal("no code was texted")
+	}
+	return code
+}
+
+func (p *phoneLogin) verify(code string) error {
+	_, err := p.VerifyCode(context.Background(), service.PhoneVerifyInput{PhoneNumber: phoneNumber, Code: code})
+	return err
+}
+
+// wrong returns a code that differs from code.
+func wrong(code string) string {
+	if code == "000000" {
+		return "111111"
+	}
+	return "000000"
+}
+
+func TestPhoneLoginWithTheTextedCode(t *testing.T) {
+	p := newPhoneLogin(t)
+	code := p.requestCode(t)
+
+	tokens, err := p.VerifyCode(context.Background(), service.PhoneVerifyInput{PhoneNumber: phoneNumber, Code: code})
+	if err != nil {
+		t.Fatalf("VerifyCode: %v", err)
+	}
+	claims, err := p.users.tokenManager.ValidateToken(tokens.AccessToken, false)
+	if err != nil {
+		t.Fatalf("ValidateToken: %v", err)
+	}
+
+	user, err := p.users.repo.GetByPhone(context.Background(), phoneNumber)
+	if err != nil {
+		t.Fatalf("GetByPhone: %v", err)
+	}
+	if claims.UserID != user.ID.String() {
+		t.Errorf("logged in as %s, want %s", claims.UserID, user.ID)
+	}
+	if !user.PhoneVerifiedAt.Valid {
+		t.Error("phone was not marked verified")
+	}
+
+	// A code logs in once.
+	if err := p.verify(code); !errors.Is(err, service.ErrOtpInvalid) {
+		t.Errorf("second VerifyCode error = %v, want %v", err, service.ErrOtpInvalid)
+	}
+}
+
+func TestPhoneLoginRefusesWrongCodes(t *testing.T) {
+	tests := []struct {
+		name    string
+		wrong   int
+		wantErr error
+	}{
+		{name: "after one wrong code", wrong: 1},
+		{name: "after four wrong codes", wrong: 4},
+		{name: "once the attempts are used up", wrong: 5, wantErr: service.ErrOtpAttemptsExceeded},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newPhoneLogin(t)
+			code := p.requestCode(t)
+
+			for i := 1; i <= tt.wrong; i++ {
+				want := service.ErrOtpInvalid
+				if i == 5 {
+					want = service.ErrOtpAttemptsExceeded
+				}
+				if err := p.verify(wrong(code)); !errors.Is(err, want) {
+					t.Fatalf("attempt %d: VerifyCode error = %v, want %v", i, err, want)
+				}
+			}
+
+			if err := p.verify(code); !errors.Is(err, tt.wantErr) {
+				t.Errorf("VerifyCode with the right code error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestPhoneLoginOnlyAcceptsTheLatestCode(t *testing.T) {
+	p := newPhoneLogin(t)
+	first := p.requestCode(t)
+	latest := p.requestCode(t)
+	if first == latest {
+		t.Skip("the same code was texted twice")
+	}
+
+	if err := p.verify(first); !errors.Is(err, service.ErrOtpInvalid) {
+		t.Errorf("VerifyCode with the first code error = %v, want %v", err, service.ErrOtpInvalid)
+	}
+	if err := p.verify(latest); err != nil {
+		t.Errorf("VerifyCode with the latest code: %v", err)
+	}
+}
+
+func TestPhoneLoginThrottlesCodeRequests(t *testing.T) {
+	p := newPhoneLogin(t)
+	for range 3 {
+		p.requestCode(t)
+	}
+
+	err := p.RequestCode(context.Background(), service.PhoneLoginInput{PhoneNumber: phoneNumber})
+	if !errors.Is(err, service.ErrOtpThrottled) {
+		t.Fatalf("fourth RequestCode error = %v, want %v", err, service.ErrOtpThrottled)
+	}
+	if sent := p.sms.Sent(); len(sent) != 3 {
+		t.Errorf("texted %d codes, want 3", len(sent))
+	}
+}
+
+func TestPhoneLoginIgnoresUnknownNumbers(t *testing.T) {
+	p := newPhoneLogin(t)
+
+	err := p.RequestCode(context.Background(), service.PhoneLoginInput{PhoneNumber: "+33700000000"})
+	if err != nil {
+		t.Fatalf("RequestCode: %v", err)
+	}
+	if sent := p.sms.Sent(); len(sent) != 0 {
+		t.Errorf("texted %d codes to an unknown number", len(sent))
+	}
+}
@@ -0,0 +1,185 @@
+package service
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/goodfoodcesi/auth-api/domain/repository"
+	"github.com/goodfoodcesi/auth-api/infrastructure/jwt"
+	"go.uber.org/zap"
+)
+
+const (
+	GrantTypeTokenExchange = "urn:ietf:params:oauth:grant-type:token-exchange"
+	TokenTypeAccessToken   = "urn:ietf:params:oauth:token-type:access_token"
+	TokenTypeJWT           = "urn:ietf:params:oauth:token-type:jwt"
+
+	exchangedTokenTTL = 5 * time.Minute
+)
+
+// Token exchange errors, named after the RFC 6749 error codes they map to.
+var (
+	ErrInvalidRequest       = errors.New("invalid_request")
+	ErrInvalidClient        = errors.New("invalid_client")
+	ErrInvalidGrant         = errors.New("invalid_grant")
+	ErrUnsupportedGrantType = errors.New("unsupported_grant_type")
+	ErrInvalidTarget        = errors.New("invalid_target")
+	ErrInvalidScope         = errors.New("invalid_scope")
+)
+
+// ServiceClient is a backend service allowed to exchange user tokens, and the
+// services it may request tokens for.
+type ServiceClient struct {
+	Secret    string   `json:"secret"`
+	Audiences []string `json:"audiences"`
+}
+
+// AccountChecker tells whether the account behind a valid token may still use
+// the API, as UserService.IsActive does.
+type AccountChecker interface {
+	IsActive(ctx context.Context, userID string, issuedAt time.Time) (bool, error)
+}
+
+type TokenExchangeService struct {
+	tokenMgr *jwt.TokenManager
+	accounts AccountChecker
+	clients  map[string]ServiceClient
+	logger   *zap.Logger
+}
+
+type TokenExchangeInput struct {
+	GrantType        string
+	ClientID         string
+	ClientSecret     string
+	SubjectToken     string
+	SubjectTokenType string
+	Audience         string
+	// Scope is a space separated subset of the subject token permissions.
+	Scope string
+}
+
+type ExchangedToken struct {
+	AccessToken     string `json:"access_token"`
+	IssuedTokenType string `json:"issued_token_type"`
+	TokenType       string `json:"token_type"`
+	ExpiresIn       int    `json:"expires_in"`
+	Scope           string `json:"scope,omitempty"`
+}
+
+// ParseServiceClients reads the SERVICE_CLIENTS configuration, a JSON object
+// keyed by client id.
+func ParseServiceClients(raw string) (map[string]ServiceClient, error) {
+	clients := map[string]ServiceClient{}
+	if raw == "" {
+		return clients, nil
+	}
+	if err := json.Unmarshal([]byte(raw), &clients); err != nil {
+		return nil, err
+	}
+	return clients, nil
+}
+
+func NewTokenExchangeService(tokenMgr *jwt.TokenManager, accounts AccountChecker, clients map[string]ServiceClient, logger *zap.Logger) *TokenExchangeService {
+	return &TokenExchangeService{
+		tokenMgr: tokenMgr,
+		accounts: accounts,
+		clients:  clients,
+		logger:   logger,
+	}
+}
+
+// Exchange trades a user's token for one that only the audience service
+// accepts, with at most the same permissions and a much shorter lifetime.
+func (s *TokenExchangeService) Exchange(ctx context.Context, input TokenExchangeInput) (*ExchangedToken, error) {
+	if input.GrantType != GrantTypeTokenExchange {
+		return nil, ErrUnsupportedGrantType
+	}
+
+	client, ok := s.clients[input.ClientID]
+	if !ok || subtle.ConstantTimeCompare([]byte(client.Secret), []byte(input.ClientSecret)) != 1 {
+		return nil, ErrInvalidClient
+	}
+
+	if input.SubjectToken == "" || input.Audience == "" {
+		return nil, ErrInvalidRequest
+	}
+	if input.SubjectTokenType != TokenTypeAccessToken && input.SubjectTokenType != TokenTypeJWT {
+		return nil, ErrInvalidRequest
+	}
+	if !contains(client.Audiences, input.Audience) {
+		return nil, ErrInvalidTarget
+	}
+
+	subject, err := s.tokenMgr.ValidateSubjectToken(input.SubjectToken)
+	if err != nil {
+		return nil, ErrInvalidGrant
+	}
+	// A client may only exchange tokens meant for everyone or for itself.
+	if !contains(subject.Audience, jwt.DefaultAudience) && !contains(subject.Audience, input.ClientID) {
+		return nil, ErrInvalidGrant
+	}
+
+	// A suspended user, or one whose sessions were revoked, must not have
+	// their token outlive that in another service.
+	var issuedAt time.Time
+	if subject.IssuedAt != nil {
+		issuedAt = subject.IssuedAt.Time
+	}
+	active, err := s.accounts.IsActive(ctx, subject.UserID, issuedAt)
+	if errors.Is(err, repository.ErrUserNotFound) {
+		return nil, ErrInvalidGrant
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !active {
+		return nil, ErrInvalidGrant
+	}
+
+	permissions := subject.Permissions
+	if input.Scope != "" {
+		permissions = strings.Fields(input.Scope)
+		for _, permission := range permissions {
+			if !contains(subject.Permissions, permission) {
+				return nil, ErrInvalidScope
+			}
+		}
+	}
+
+	ttl := exchangedTokenTTL
+	if remaining := time.Until(subject.ExpiresAt.Time); remaining < ttl {
+		ttl = remaining
+	}
+
+	token, expiresAt, err := s.tokenMgr.Exchange(subject, input.Audience, input.ClientID, permissions, ttl)
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("token exchanged",
+		zap.String("client_id", input.ClientID),
+		zap.String("audience", input.Audience),
+		zap.String("user_id", subject.UserID),
+	)
+
+	return &ExchangedToken{
+		AccessToken:     token,
+		IssuedTokenType: TokenTypeAccessToken,
+		TokenType:       "Bearer",
+		ExpiresIn:       int(time.Until(expiresAt).Seconds()),
+		Scope:           strings.Join(permissions, " "),
+	}, nil
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,215 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/goodfoodcesi/auth-api/domain/repository/repotest"
+	"github.com/goodfoodcesi/auth-api/domain/service"
+	"github.com/goodfoodcesi/auth-api/infrastructure/database/sqlc"
+	"github.com/goodfoodcesi/auth-api/infrastructure/jwt"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/zap"
+)
+
+var serviceClients = map[string]service.ServiceClient{
+	"order-api":    {Secret: "order-secret", Audiences: []string{"delivery-api", "restaurant-api"}},
+	"delivery-api": {Secret: "delivery-secret", Audiences: []string{"restaurant-api"}},
+}
+
+// customerID is the account whose tokens are exchanged.
+var customerID = repotest.NewID()
+
+// newTokenExchange checks accounts against a store holding customerID, active,
+// and any of others.
+func newTokenExchange(tokens *jwt.TokenManager, others ...*db.User) *service.TokenExchangeService {
+	users := newUsers(append(others, &db.User{ID: customerID, Email: "jane@example.com", Role: db.UserRoleClient})...)
+	return service.NewTokenExchangeService(tokens, users, serviceClients, zap.NewNop())
+}
+
+// userToken signs an access token for a client allowed to order and refund.
+func userToken(t *testing.T, tokens *jwt.TokenManager) string {
+	t.Helper()
+
+	return tokenFor(t, tokens, customerID)
+}
+
+func tokenFor(t *testing.T, tokens *jwt.TokenManager, id pgtype.UUID) string {
+	t.Helper()
+
+	pair, err := tokens.GenerateTokenPair(jwt.Subject{
+		UserID:      id.String(),
+		Role:        db.UserRoleClient,
+		Permissions: []string{"orders:create", "orders:refund"},
+	})
+	if err != nil {
+		t.Fatalf("GenerateTokenPair: %v", err)
+	}
+	return pair.AccessToken
+}
+
+func exchangeInput(subjectToken string) service.TokenExchangeInput {
+	return service.TokenExchangeInput{
+		GrantType:        service.GrantTypeTokenExchange,
+		ClientID:         "order-api",
+		ClientSecret:     "order-secret",
+		SubjectToken:     subjectToken,
+		SubjectTokenType: service.TokenTypeAccessToken,
+		Audience:         "delivery-api",
+	}
+}
+
+func TestTokenExchange(t *testing.T) {
+	tokens := jwt.NewTokenManager("access-secret", "refresh-secret", time.Hour, 24*time.Hour)
+	subjectToken := userToken(t, tokens)
+	other := jwt.NewTokenManager("other-secret", "refresh-secret", time.Hour, 24*time.Hour)
+
+	tests := []struct {
+		name      string
+		change    func(input *service.TokenExchangeInput)
+		wantErr   error
+		wantScope []string
+	}{
+		{name: "keeps every permission by default", wantScope: []string{"orders:create", "orders:refund"}},
+		{name: "narrows to the requested scope", change: func(in *service.TokenExchangeInput) { in.Scope = "orders:refund" }, wantScope: []string{"orders:refund"}},
+		{name: "refuses a scope the user lacks", change: func(in *service.TokenExchangeInput) { in.Scope = "orders:refund users:manage" }, wantErr: service.ErrInvalidScope},
+		{name: "refuses another grant type", change: func(in *service.TokenExchangeInput) { in.GrantType = "client_credentials" }, wantErr: service.ErrUnsupportedGrantType},
+		{name: "refuses an unknown client", change: func(in *service.TokenExchangeInput) { in.ClientID = "billing-api" }, wantErr: service.ErrInvalidClient},
+		{name: "refuses a wrong client secret", change: func(in *service.TokenExchangeInput) { in.ClientSecret = "delivery-secret" }, wantErr: service.ErrInvalidClient},
+		{name: "refuses an audience the client may not reach", change: func(in *service.TokenExchangeInput) { in.Audience = "payment-api" }, wantErr: service.ErrInvalidTarget},
+		{name: "refuses a missing audience", change: func(in *service.TokenExchangeInput) { in.Audience = "" }, wantErr: service.ErrInvalidRequest},
+		{name: "refuses another token type", change: func(in *service.TokenExchangeInput) {
+			in.SubjectTokenType = "urn:ietf:params:oauth:token-type:refresh_token"
+		}, wantErr: service.ErrInvalidRequest},
+		{name: "refuses a token signed elsewhere", change: func(in *service.TokenExchangeInput) { in.SubjectToken = userToken(t, other) }, wantErr: service.ErrInvalidGrant},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input := exchangeInput(subjectToken)
+			if tt.change != nil {
+				tt.change(&input)
+			}
+
+			exchanged, err := newTokenExchange(tokens).Exchange(context.Background(), input)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Exchange error = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+
+			claims, err := tokens.ValidateSubjectToken(exchanged.AccessToken)
+			if err != nil {
+				t.Fatalf("ValidateSubjectToken: %v", err)
+			}
+			if !equal(claims.Permissions, tt.wantScope) {
+				t.Errorf("permissions = %v, want %v", claims.Permissions, tt.wantScope)
+			}
+			if len(claims.Audience) != 1 || claims.Audience[0] != "delivery-api" {
+				t.Errorf("audience = %v, want [delivery-api]", claims.Audience)
+			}
+			if claims.UserID != customerID.String() || claims.Actor == nil || claims.Actor.Subject != "order-api" {
+				t.Errorf("issued to %s acted on by %+v", claims.UserID, claims.Actor)
+			}
+			// The exchanged token is not accepted by this API.
+			if _, err := tokens.ValidateToken(exchanged.AccessToken, false); err == nil {
+				t.Error("the exchanged token is accepted by auth-api")
+			}
+		})
+	}
+}
+
+func TestTokenExchangeClampsTheLifetime(t *testing.T) {
+	tests := []struct {
+		name      string
+		accessTTL time.Duration
+		want      time.Duration
+	}{
+		{name: "to five minutes", accessTTL: time.Hour, want: 5 * time.Minute},
+		{name: "to what is left of the subject token", accessTTL: 2 * time.Minute, want: 2 * time.Minute},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tokens := jwt.NewTokenManager("access-secret", "refresh-secret", tt.accessTTL, 24*time.Hour)
+
+			exchanged, err := newTokenExchange(tokens).Exchange(context.Background(), exchangeInput(userToken(t, tokens)))
+			if err != nil {
+				t.Fatalf("Exchange: %v", err)
+			}
+			claims, err := tokens.ValidateSubjectToken(exchanged.AccessToken)
+			if err != nil {
+				t.Fatalf("ValidateSubjectToken: %v", err)
+			}
+
+			if lifetime := time.Until(claims.ExpiresAt.Time); lifetime > tt.want || lifetime < tt.want-5*time.Second {
+				t.Errorf("lifetime = %s, want %s", lifetime, tt.want)
+			}
+			if expiresIn := time.Duration(exchanged.ExpiresIn) * time.Second; expiresIn > tt.want {
+				t.Errorf("expires_in = %s, want at most %s", expiresIn, tt.want)
+			}
+		})
+	}
+}
+
+func TestTokenExchangeOfAnExchangedToken(t *testing.T) {
+	tokens := jwt.NewTokenManager("access-secret", "refresh-secret", time.Hour, 24*time.Hour)
+	exchanged, err := newTokenExchange(tokens).Exchange(context.Background(), exchangeInput(userToken(t, tokens)))
+	if err != nil {
+		t.Fatalf("Exchange: %v", err)
+	}
+
+	// order-api cannot reuse a token it handed to delivery-api.
+	again := exchangeInput(exchanged.AccessToken)
+	again.Audience = "restaurant-api"
+	if _, err := newTokenExchange(tokens).Exchange(context.Background(), again); !errors.Is(err, service.ErrInvalidGrant) {
+		t.Errorf("Exchange by order-api error = %v, want %v", err, service.ErrInvalidGrant)
+	}
+
+	// delivery-api may pass it on, and the chain of actors is kept.
+	onward := exchangeInput(exchanged.AccessToken)
+	onward.ClientID, onward.ClientSecret, onward.Audience = "delivery-api", "delivery-secret", "restaurant-api"
+	delegated, err := newTokenExchange(tokens).Exchange(context.Background(), onward)
+	if err != nil {
+		t.Fatalf("Exchange by delivery-api: %v", err)
+	}
+	claims, err := tokens.ValidateSubjectToken(delegated.AccessToken)
+	if err != nil {
+		t.Fatalf("ValidateSubjectToken: %v", err)
+	}
+	if claims.Actor == nil || claims.Actor.Subject != "delivery-api" || claims.Actor.Actor == nil || claims.Actor.Actor.Subject != "order-api" {
+		t.Errorf("actor = %+v, want delivery-api acting for order-api", claims.Actor)
+	}
+}
+
+func TestTokenExchangeRefusesLockedOutAccounts(t *testing.T) {
+	tokens := jwt.NewTokenManager("access-secret", "refresh-secret", time.Hour, 24*time.Hour)
+	suspended := &db.User{ID: repotest.NewID(), Email: "suspended@example.com", Status: db.UserStatusSuspended}
+	banned := &db.User{ID: repotest.NewID(), Email: "banned@example.com", Status: db.UserStatusBanned}
+	// Signed out everywhere after the token was issued.
+	revoked := &db.User{
+		ID:                repotest.NewID(),
+		Email:             "revoked@example.com",
+		SessionsRevokedAt: pgtype.Timestamptz{Time: time.Now().Add(time.Minute), Valid: true},
+	}
+	exchange := newTokenExchange(tokens, suspended, banned, revoked)
+
+	tests := []struct {
+		name string
+		id   pgtype.UUID
+	}{
+		{name: "suspended", id: suspended.ID},
+		{name: "banned", id: banned.ID},
+		{name: "sessions revoked", id: revoked.ID},
+		{name: "unknown", id: repotest.NewID()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := exchange.Exchange(context.Background(), exchangeInput(tokenFor(t, tokens, tt.id)))
+			if !errors.Is(err, service.ErrInvalidGrant) {
+				t.Errorf("Exchange error = %v, want %v", err, service.ErrInvalidGrant)
+			}
+		})
+	}
+}
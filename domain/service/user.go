@@ -8,81 +8,184 @@ import (
 	"time"
 
 	"github.com/goodfoodcesi/auth-api/crypto"
+	"github.com/goodfoodcesi/auth-api/domain/events"
 	"github.com/goodfoodcesi/auth-api/domain/repository"
 	"github.com/goodfoodcesi/auth-api/infrastructure/database/sqlc"
 	"github.com/goodfoodcesi/auth-api/infrastructure/jwt"
+	"github.com/goodfoodcesi/auth-api/infrastructure/messaging/rabbitmq"
+	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+var (
+	ErrUserNotFound = errors.New("user not found")
+	ErrEmailExists  = errors.New("email already exists")
+	// ErrStaleVersion means the caller edited an outdated copy of the user.
+	ErrStaleVersion = errors.New("user has been modified since it was read")
+	// ErrInvalidPassword is returned when the current password given to
+	// change it does not match.
+	ErrInvalidPassword = errors.New("current password is invalid")
+	// ErrNotRestaurantMember is returned when a token is requested for a
+	// restaurant the user does not belong to.
+	ErrNotRestaurantMember = errors.New("user is not a member of this restaurant")
+)
+
 type UserService struct {
-	repo             repository.UserRepository
-	tokenMgr         *jwt.TokenManager
-	pwdManager       *crypto.PasswordManager
-	messagingService *MessagingService
-	logger           *zap.Logger
+	repo        repository.UserRepository
+	driverApps  repository.DriverApplicationRepository
+	memberships repository.MembershipRepository
+	permissions repository.PermissionRepository
+	outbox      repository.OutboxRepository
+	transactor  repository.Transactor
+	tokenMgr    *jwt.TokenManager
+	pwdManager  *crypto.PasswordManager
+	logger      *zap.Logger
 }
 
 type RegisterUserInput struct {
-	FirstName string      `json:"first_name" validate:"required,min=2,max=100"`
-	LastName  string      `json:"last_name" validate:"required,min=2,max=100"`
-	Email     string      `json:"email" validate:"required,email"`
-	Password  string      `json:"password" validate:"required,min=8"`
-	Role      db.UserRole `json:"role" validate:"required,oneof=client manager driver"`
+	FirstName string `json:"first_name" validate:"required,min=2,max=100"`
+	LastName  string `json:"last_name" validate:"required,min=2,max=100"`
+	Email     string `json:"email" validate:"required,email"`
+	Password  string `json:"password" validate:"required,min=8"`
 }
 
+// UpdateUserInput only changes the fields that are provided.
 type UpdateUserInput struct {
-	FirstName string `json:"first_name" validate:"omitempty,min=2,max=100"`
-	LastName  string `json:"last_name" validate:"omitempty,min=2,max=100"`
+	FirstName   string `json:"first_name" validate:"omitempty,min=2,max=100"`
+	LastName    string `json:"last_name" validate:"omitempty,min=2,max=100"`
+	PhoneNumber string `json:"phone_number" validate:"omitempty,e164"`
+	Locale      string `json:"locale" validate:"omitempty,bcp47_language_tag,max=35"`
+	Timezone    string `json:"timezone" validate:"omitempty,timezone,max=64"`
+	AvatarURL   string `json:"avatar_url" validate:"omitempty,http_url,max=2048"`
+}
+
+type ChangePasswordInput struct {
+	CurrentPassword string `json:"current_password" validate:"required"`
+	NewPassword     string `json:"new_password" validate:"required,min=8"`
 }
 
 type LoginInput struct {
 	Email    string `json:"email" validate:"required,email"`
 	Password string `json:"password" validate:"required"`
+	// RestaurantID optionally scopes the tokens to one of the user's restaurants.
+	RestaurantID string `json:"restaurant_id,omitempty"`
 }
 
 func NewUserService(
 	repo repository.UserRepository,
+	driverApps repository.DriverApplicationRepository,
+	memberships repository.MembershipRepository,
+	permissions repository.PermissionRepository,
+	outbox repository.OutboxRepository,
+	transactor repository.Transactor,
 	tokenMgr *jwt.TokenManager,
 	pwdManager *crypto.PasswordManager,
-	messagingService *MessagingService,
 	logger *zap.Logger,
 ) *UserService {
 	return &UserService{
-		repo:             repo,
-		tokenMgr:         tokenMgr,
-		pwdManager:       pwdManager,
-		messagingService: messagingService,
-		logger:           logger,
+		repo:        repo,
+		driverApps:  driverApps,
+		memberships: memberships,
+		permissions: permissions,
+		outbox:      outbox,
+		transactor:  transactor,
+		tokenMgr:    tokenMgr,
+		pwdManager:  pwdManager,
+		logger:      logger,
 	}
 }
 
-func (s *UserService) Update(ctx context.Context, id pgtype.UUID, input UpdateUserInput) (*db.User, error) {
+// Update applies input to the user only if version is still the stored one.
+func (s *UserService) Update(ctx context.Context, id pgtype.UUID, version int32, input UpdateUserInput) (*db.User, error) {
 	existingUser, _ := s.repo.GetByID(ctx, id)
 	if existingUser == nil {
-		return nil, errors.New("user not found")
+		return nil, ErrUserNotFound
+	}
+	if existingUser.Version != version {
+		return nil, ErrStaleVersion
 	}
 
-	user := &db.User{
-		ID:        existingUser.ID,
-		Firstname: input.FirstName,
-		Lastname:  input.LastName,
-		Email:     existingUser.Email,
-		Role:      existingUser.Role,
-		CreatedAt: existingUser.CreatedAt,
-		UpdatedAt: pgtype.Timestamptz{Time: time.Now()},
+	user := *existingUser
+	if input.FirstName != "" {
+		user.Firstname = input.FirstName
+	}
+	if input.LastName != "" {
+		user.Lastname = input.LastName
+	}
+	if input.PhoneNumber != "" {
+		user.PhoneNumber = pgtype.Text{String: input.PhoneNumber, Valid: true}
+	}
+	if input.Locale != "" {
+		user.Locale = input.Locale
+	}
+	if input.Timezone != "" {
+		user.Timezone = input.Timezone
+	}
+	if input.AvatarURL != "" {
+		user.AvatarUrl = pgtype.Text{String: input.AvatarURL, Valid: true}
 	}
 
-	if err := s.repo.Update(ctx, user); err != nil {
+	var updatedUser *db.User
+	err := s.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		updatedUser, err = s.repo.Update(ctx, &user)
+		if err != nil {
+			return err
+		}
+		return s.enqueueUserEvent(ctx, updatedUser.ID, rabbitmq.UserUpdatedKey, events.UserUpdated{
+			UserID:      updatedUser.ID.String(),
+			FirstName:   updatedUser.Firstname,
+			LastName:    updatedUser.Lastname,
+			Email:       updatedUser.Email,
+			Version:     updatedUser.Version,
+			OccurredAt:  time.Now(),
+			UserProfile: events.NewUserProfile(updatedUser),
+		})
+	})
+	if errors.Is(err, repository.ErrVersionConflict) {
+		return nil, ErrStaleVersion
+	}
+	if err != nil {
 		return nil, err
 	}
 
-	return user, nil
+	return updatedUser, nil
+}
+
+// ChangePassword replaces the password of a signed-in user who proves they
+// know the current one.
+func (s *UserService) ChangePassword(ctx context.Context, id pgtype.UUID, input ChangePasswordInput) error {
+	user, _ := s.repo.GetByID(ctx, id)
+	if user == nil {
+		return ErrUserNotFound
+	}
+	if !s.pwdManager.ComparePassword(user.PasswordHash, input.CurrentPassword) {
+		return ErrInvalidPassword
+	}
+
+	hashedPassword, err := s.pwdManager.HashPassword(input.NewPassword)
+	if err != nil {
+		return err
+	}
+
+	return s.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.repo.UpdatePassword(ctx, id, hashedPassword); err != nil {
+			return err
+		}
+		return s.enqueueUserEvent(ctx, id, rabbitmq.UserPasswordChangedKey, events.UserPasswordChanged{
+			UserID:     id.String(),
+			Email:      user.Email,
+			OccurredAt: time.Now(),
+		})
+	})
 }
 
+// Register is the public sign-up and only creates clients, privileged roles
+// are granted through invitations.
 func (s *UserService) Register(ctx context.Context, input RegisterUserInput) (*db.User, error) {
 	existingUser, _ := s.repo.GetByEmail(ctx, input.Email)
 	if existingUser != nil {
-		return nil, errors.New("email already exists")
+		return nil, ErrEmailExists
 	}
 
 	hashedPassword, err := s.pwdManager.HashPassword(input.Password)
@@ -95,29 +198,65 @@ func (s *UserService) Register(ctx context.Context, input RegisterUserInput) (*d
 		Lastname:     input.LastName,
 		Email:        input.Email,
 		PasswordHash: hashedPassword,
-		Role:         input.Role,
+		Role:         db.UserRoleClient,
 		CreatedAt:    pgtype.Timestamptz{Time: time.Now()},
 		UpdatedAt:    pgtype.Timestamptz{Time: time.Now()},
 	}
 
-	user, err = s.repo.Create(ctx, user)
+	err = s.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		user, err = s.repo.Create(ctx, user)
+		if err != nil {
+			return err
+		}
+		return s.enqueueUserCreated(ctx, user)
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	userCreatedEvent := event.UserCreatedEvent{
-		ID:        user.ID,
-		Email:     user.Email,
-		FirstName: user.Firstname,
-		LastName:  user.Lastname,
-		Role:      string(user.Role),
-		CreatedAt: time.Now(),
+	return user, nil
+}
+
+// enqueueUserCreated must run in the transaction creating user.
+func (s *UserService) enqueueUserCreated(ctx context.Context, user *db.User) error {
+	userCreatedEvent := events.UserCreated{
+		UserCreatedEvent: event.UserCreatedEvent{
+			ID:        user.ID,
+			Email:     user.Email,
+			FirstName: user.Firstname,
+			LastName:  user.Lastname,
+			Role:      string(user.Role),
+			CreatedAt: time.Now(),
+		},
+		UserProfile: events.NewUserProfile(user),
 	}
 
-	if err := s.messagingService.PublishUserCreated(ctx, userCreatedEvent); err != nil {
-		s.logger.Error("failed to publish user created event", zap.Error(err))
+	return s.enqueueUserEvent(ctx, user.ID, rabbitmq.UserCreatedKey, userCreatedEvent)
+}
+
+// enqueueUserEvent queues payload on the user exchange, routed by key.
+func (s *UserService) enqueueUserEvent(ctx context.Context, id pgtype.UUID, key string, payload any) error {
+	return enqueue(ctx, s.outbox, outboxEvent{
+		aggregateType: "user",
+		aggregateID:   id.String(),
+		eventType:     key,
+		exchange:      rabbitmq.UserExchange,
+		routingKey:    key,
+		payload:       payload,
+	})
+}
+
+// recordLogin is best effort, a sign-in must not fail because the event
+// could not be queued.
+func (s *UserService) recordLogin(ctx context.Context, user *db.User, method string) {
+	err := s.enqueueUserEvent(ctx, user.ID, rabbitmq.UserLoggedInKey, events.UserLoggedIn{
+		UserID:     user.ID.String(),
+		Method:     method,
+		OccurredAt: time.Now(),
+	})
+	if err != nil {
+		s.logger.Error("failed to enqueue user logged in event", zap.Error(err), zap.String("user_id", user.ID.String()))
 	}
-	return user, nil
 }
 
 func (s *UserService) Login(ctx context.Context, input LoginInput) (*jwt.TokenPair, error) {
@@ -130,7 +269,47 @@ func (s *UserService) Login(ctx context.Context, input LoginInput) (*jwt.TokenPa
 		return nil, errors.New("invalid credentials")
 	}
 
-	tokens, err := s.tokenMgr.GenerateTokenPair(user.ID.String(), user.Role)
+	var tokens *jwt.TokenPair
+	if input.RestaurantID != "" {
+		restaurantID, err := parseUUID(input.RestaurantID)
+		if err != nil {
+			return nil, ErrNotRestaurantMember
+		}
+		tokens, err = s.IssueRestaurantTokens(ctx, user, restaurantID)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		tokens, err = s.IssueTokens(ctx, user)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	s.recordLogin(ctx, user, "password")
+	return tokens, nil
+}
+
+// IssueTokens is the single place where a signed-in user gets a token pair,
+// whatever the way they proved their identity.
+func (s *UserService) IssueTokens(ctx context.Context, user *db.User) (*jwt.TokenPair, error) {
+	return s.IssueRestaurantTokens(ctx, user, pgtype.UUID{})
+}
+
+// IssueRestaurantTokens scopes the token pair to restaurantID, which the user
+// must be a member of. Without a restaurant, a manager of a single restaurant
+// gets that one.
+func (s *UserService) IssueRestaurantTokens(ctx context.Context, user *db.User, restaurantID pgtype.UUID) (*jwt.TokenPair, error) {
+	if user.Status != db.UserStatusActive {
+		return nil, ErrAccountInactive
+	}
+
+	sub, err := s.subject(ctx, user, restaurantID)
+	if err != nil {
+		return nil, err
+	}
+
+	tokens, err := s.tokenMgr.GenerateTokenPair(sub)
 	if err != nil {
 		return nil, err
 	}
@@ -138,6 +317,77 @@ func (s *UserService) Login(ctx context.Context, input LoginInput) (*jwt.TokenPa
 	return tokens, nil
 }
 
+// subject gathers what tokens issued to user say about them.
+func (s *UserService) subject(ctx context.Context, user *db.User, restaurantID pgtype.UUID) (jwt.Subject, error) {
+	permissions, err := s.permissions.ForRole(ctx, user.Role)
+	if err != nil {
+		return jwt.Subject{}, err
+	}
+
+	sub := jwt.Subject{UserID: user.ID.String(), Role: user.Role, Permissions: permissions}
+	membership, err := s.activeMembership(ctx, user, restaurantID)
+	if err != nil {
+		return jwt.Subject{}, err
+	}
+	if membership != nil {
+		sub.RestaurantID = membership.RestaurantID.String()
+		sub.RestaurantRole = membership.Role
+	}
+
+	if user.Role == db.UserRoleDriver {
+		approved, err := s.isApprovedDriver(ctx, user.ID)
+		if err != nil {
+			return jwt.Subject{}, err
+		}
+		sub.Approved = &approved
+	}
+
+	return sub, nil
+}
+
+// SwitchRestaurant is the token exchange for a signed-in user picking another
+// of their restaurants.
+func (s *UserService) SwitchRestaurant(ctx context.Context, userID, restaurantID pgtype.UUID) (*jwt.TokenPair, error) {
+	user, _ := s.repo.GetByID(ctx, userID)
+	if user == nil {
+		return nil, ErrUserNotFound
+	}
+	return s.IssueRestaurantTokens(ctx, user, restaurantID)
+}
+
+func (s *UserService) activeMembership(ctx context.Context, user *db.User, restaurantID pgtype.UUID) (*db.Membership, error) {
+	if restaurantID.Valid {
+		membership, err := s.memberships.Get(ctx, user.ID, restaurantID)
+		if errors.Is(err, repository.ErrMembershipNotFound) {
+			return nil, ErrNotRestaurantMember
+		}
+		return membership, err
+	}
+
+	if user.Role != db.UserRoleManager {
+		return nil, nil
+	}
+	memberships, err := s.memberships.ListByUser(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	if len(memberships) != 1 {
+		return nil, nil
+	}
+	return &memberships[0], nil
+}
+
+func (s *UserService) isApprovedDriver(ctx context.Context, userID pgtype.UUID) (bool, error) {
+	application, err := s.driverApps.GetByUserID(ctx, userID)
+	if errors.Is(err, repository.ErrDriverApplicationNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return application.Status == db.DriverApplicationStatusApproved, nil
+}
+
 func (s *UserService) GetByID(ctx context.Context, id pgtype.UUID) (*db.User, error) {
 	user, err := s.repo.GetByID(ctx, id)
 	if err != nil {
@@ -145,3 +395,11 @@ func (s *UserService) GetByID(ctx context.Context, id pgtype.UUID) (*db.User, er
 	}
 	return user, nil
 }
+
+func parseUUID(value string) (pgtype.UUID, error) {
+	id, err := uuid.Parse(value)
+	if err != nil {
+		return pgtype.UUID{}, err
+	}
+	return pgtype.UUID{Bytes: id, Valid: true}, nil
+}
@@ -0,0 +1,182 @@
+package service
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/goodfoodcesi/auth-api/domain/events"
+	"github.com/goodfoodcesi/auth-api/domain/repository"
+	"github.com/goodfoodcesi/auth-api/infrastructure/database/sqlc"
+	"github.com/goodfoodcesi/auth-api/infrastructure/messaging/rabbitmq"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultUserPageSize = 20
+	maxUserPageSize     = 100
+)
+
+var (
+	ErrInvalidCursor  = errors.New("invalid cursor")
+	ErrSelfManagement = errors.New("cannot change your own role, status or memberships")
+)
+
+type ListUsersInput struct {
+	Role          string     `validate:"omitempty,oneof=client manager driver admin"`
+	Status        string     `validate:"omitempty,oneof=pending active suspended banned deleted"`
+	CreatedAfter  *time.Time `validate:"omitempty"`
+	CreatedBefore *time.Time `validate:"omitempty"`
+	Search        string     `validate:"omitempty,max=255"`
+	Sort          string     `validate:"omitempty,oneof=created_at_desc created_at_asc email_asc email_desc"`
+	Cursor        string     `validate:"omitempty,base64rawurl"`
+	Limit         int        `validate:"omitempty,min=1,max=100"`
+}
+
+type ChangeRoleInput struct {
+	Role db.UserRole `json:"role" validate:"required,oneof=client manager driver admin"`
+}
+
+// UserPage is one page of ListUsers; NextCursor is empty on the last page.
+type UserPage struct {
+	Users      []db.User
+	NextCursor string
+}
+
+type userCursor struct {
+	ID        string    `json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	Email     string    `json:"email"`
+}
+
+func (s *UserService) ListUsers(ctx context.Context, input ListUsersInput) (*UserPage, error) {
+	limit := input.Limit
+	if limit <= 0 || limit > maxUserPageSize {
+		limit = defaultUserPageSize
+	}
+
+	filter := repository.UserFilter{
+		CreatedAfter:  input.CreatedAfter,
+		CreatedBefore: input.CreatedBefore,
+		Search:        input.Search,
+		Sort:          repository.UserSort(input.Sort),
+		// One extra row tells whether another page follows.
+		Limit: int32(limit + 1),
+	}
+	if filter.Sort == "" {
+		filter.Sort = repository.UserSortCreatedAtDesc
+	}
+	if input.Role != "" {
+		role := db.UserRole(input.Role)
+		filter.Role = &role
+	}
+	if input.Status != "" {
+		status := db.UserStatus(input.Status)
+		filter.Status = &status
+	}
+	if input.Cursor != "" {
+		cursor, err := decodeUserCursor(input.Cursor)
+		if err != nil {
+			return nil, ErrInvalidCursor
+		}
+		filter.After = cursor
+	}
+
+	users, err := s.repo.List(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &UserPage{Users: users}
+	if len(users) > limit {
+		page.Users = users[:limit]
+		page.NextCursor = encodeUserCursor(&page.Users[limit-1])
+	}
+
+	return page, nil
+}
+
+// ChangeRole sets the role of a user, version is the ETag the admin read.
+func (s *UserService) ChangeRole(ctx context.Context, adminID, id pgtype.UUID, version int32, input ChangeRoleInput) (*db.User, error) {
+	user, _ := s.repo.GetByID(ctx, id)
+	if user == nil {
+		return nil, ErrUserNotFound
+	}
+	if user.Version != version {
+		return nil, ErrStaleVersion
+	}
+	if adminID == id && input.Role != db.UserRoleAdmin {
+		return nil, ErrSelfManagement
+	}
+
+	from := user.Role
+	user.Role = input.Role
+	var updatedUser *db.User
+	err := s.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		updatedUser, err = s.repo.UpdateRole(ctx, user)
+		if err != nil {
+			return err
+		}
+		// Issued tokens still carry the old role and its permissions.
+		if err := s.repo.RevokeSessions(ctx, id); err != nil {
+			return err
+		}
+		return s.enqueueUserEvent(ctx, id, rabbitmq.UserRoleChangedKey, events.UserRoleChanged{
+			UserID:     id.String(),
+			From:       string(from),
+			To:         string(updatedUser.Role),
+			ChangedBy:  adminID.String(),
+			OccurredAt: time.Now(),
+		})
+	})
+	if errors.Is(err, repository.ErrVersionConflict) {
+		return nil, ErrStaleVersion
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("user role changed",
+		zap.String("user_id", id.String()),
+		zap.String("admin_id", adminID.String()),
+		zap.String("role", string(updatedUser.Role)),
+	)
+
+	return updatedUser, nil
+}
+
+func encodeUserCursor(user *db.User) string {
+	data, _ := json.Marshal(userCursor{
+		ID:        user.ID.String(),
+		CreatedAt: user.CreatedAt.Time,
+		Email:     user.Email,
+	})
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeUserCursor(value string) (*repository.UserCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, err
+	}
+
+	var cursor userCursor
+	if err := json.Unmarshal(data, &cursor); err != nil {
+		return nil, err
+	}
+
+	id, err := parseUUID(cursor.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &repository.UserCursor{
+		ID:        id,
+		CreatedAt: cursor.CreatedAt,
+		Email:     cursor.Email,
+	}, nil
+}
@@ -0,0 +1,164 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/goodfoodcesi/auth-api/domain/events"
+	"github.com/goodfoodcesi/auth-api/domain/repository"
+	"github.com/goodfoodcesi/auth-api/infrastructure/database/sqlc"
+	"github.com/goodfoodcesi/auth-api/infrastructure/messaging/rabbitmq"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/zap"
+)
+
+var (
+	ErrAccountInactive         = errors.New("account is not active")
+	ErrInvalidStatusTransition = errors.New("status transition is not allowed")
+)
+
+// statusTransitions lists, for each status, the statuses an admin may move
+// an account to. Deleted is terminal.
+var statusTransitions = map[db.UserStatus][]db.UserStatus{
+	db.UserStatusPending:   {db.UserStatusActive, db.UserStatusDeleted},
+	db.UserStatusActive:    {db.UserStatusSuspended, db.UserStatusBanned, db.UserStatusDeleted},
+	db.UserStatusSuspended: {db.UserStatusActive, db.UserStatusBanned, db.UserStatusDeleted},
+	db.UserStatusBanned:    {db.UserStatusActive, db.UserStatusDeleted},
+	db.UserStatusDeleted:   {},
+}
+
+type ChangeStatusInput struct {
+	Reason string `json:"reason" validate:"omitempty,max=500"`
+}
+
+func CanTransition(from, to db.UserStatus) bool {
+	for _, allowed := range statusTransitions[from] {
+		if allowed == to {
+			return true
+		}
+	}
+	return false
+}
+
+// ChangeStatus moves a user to status on behalf of actorID and notifies the
+// other services through the outbox.
+func (s *UserService) ChangeStatus(ctx context.Context, actorID, id pgtype.UUID, status db.UserStatus, input ChangeStatusInput) (*db.User, error) {
+	if actorID == id {
+		return nil, ErrSelfManagement
+	}
+
+	user, _ := s.repo.GetByID(ctx, id)
+	if user == nil {
+		return nil, ErrUserNotFound
+	}
+
+	from := user.Status
+	if !CanTransition(from, status) {
+		return nil, ErrInvalidStatusTransition
+	}
+
+	user.Status = status
+	user.StatusReason = pgtype.Text{String: input.Reason, Valid: input.Reason != ""}
+	user.StatusChangedBy = actorID
+
+	var updatedUser *db.User
+	err := s.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		updatedUser, err = s.repo.UpdateStatus(ctx, user, from)
+		if err != nil {
+			return err
+		}
+		return enqueueStatusChanged(ctx, s.outbox, actorID, id, from, status, input.Reason)
+	})
+	if errors.Is(err, repository.ErrStatusConflict) {
+		return nil, ErrStaleVersion
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("user status changed",
+		zap.String("user_id", id.String()),
+		zap.String("actor_id", actorID.String()),
+		zap.String("from", string(from)),
+		zap.String("to", string(status)),
+	)
+
+	return updatedUser, nil
+}
+
+// enqueueStatusChanged tells the other services when the account is deleted,
+// stops or resumes being usable.
+func enqueueStatusChanged(ctx context.Context, outbox repository.OutboxRepository, actorID, id pgtype.UUID, from, to db.UserStatus, reason string) error {
+	switch {
+	case to == db.UserStatusDeleted:
+		return enqueue(ctx, outbox, outboxEvent{
+			aggregateType: "user",
+			aggregateID:   id.String(),
+			eventType:     rabbitmq.UserDeletedKey,
+			exchange:      rabbitmq.UserExchange,
+			routingKey:    rabbitmq.UserDeletedKey,
+			payload: events.UserDeleted{
+				UserID:     id.String(),
+				Reason:     reason,
+				ChangedBy:  actorID.String(),
+				OccurredAt: time.Now(),
+			},
+		})
+	case from == db.UserStatusActive:
+		return enqueue(ctx, outbox, outboxEvent{
+			aggregateType: "user",
+			aggregateID:   id.String(),
+			eventType:     rabbitmq.UserSuspendedKey,
+			exchange:      rabbitmq.UserExchange,
+			routingKey:    rabbitmq.UserSuspendedKey,
+			payload: events.UserSuspended{
+				UserID:     id.String(),
+				Status:     string(to),
+				Reason:     reason,
+				ChangedBy:  actorID.String(),
+				OccurredAt: time.Now(),
+			},
+		})
+	case to == db.UserStatusActive:
+		return enqueue(ctx, outbox, outboxEvent{
+			aggregateType: "user",
+			aggregateID:   id.String(),
+			eventType:     rabbitmq.UserReactivatedKey,
+			exchange:      rabbitmq.UserExchange,
+			routingKey:    rabbitmq.UserReactivatedKey,
+			payload: events.UserReactivated{
+				UserID:     id.String(),
+				ChangedBy:  actorID.String(),
+				OccurredAt: time.Now(),
+			},
+		})
+	}
+	return nil
+}
+
+// IsActive reports whether the account behind a token issued at issuedAt may
+// still use the API.
+func (s *UserService) IsActive(ctx context.Context, userID string, issuedAt time.Time) (bool, error) {
+	id, err := parseUUID(userID)
+	if err != nil {
+		return false, err
+	}
+
+	user, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return false, err
+	}
+	return user.Status == db.UserStatusActive && !sessionRevoked(user, issuedAt), nil
+}
+
+// sessionRevoked tells whether a token issued at issuedAt predates the last
+// revocation of the user's sessions. Tokens only carry whole seconds, so one
+// issued in the same second as the revocation is still accepted.
+func sessionRevoked(user *db.User, issuedAt time.Time) bool {
+	if issuedAt.IsZero() || !user.SessionsRevokedAt.Valid {
+		return false
+	}
+	return issuedAt.Before(user.SessionsRevokedAt.Time.Truncate(time.Second))
+}
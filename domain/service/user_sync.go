@@ -0,0 +1,150 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/goodfoodcesi/auth-api/domain/repository"
+	"github.com/goodfoodcesi/auth-api/infrastructure/database/sqlc"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/zap"
+)
+
+// AccountSyncService applies what other services report about an account.
+// Its methods are called by consumers, so each one may run again for the same
+// event and must then leave the account as it is.
+type AccountSyncService struct {
+	repo        repository.UserRepository
+	driverApps  repository.DriverApplicationRepository
+	memberships repository.MembershipRepository
+	outbox      repository.OutboxRepository
+	transactor  repository.Transactor
+	logger      *zap.Logger
+}
+
+func NewAccountSyncService(
+	repo repository.UserRepository,
+	driverApps repository.DriverApplicationRepository,
+	memberships repository.MembershipRepository,
+	outbox repository.OutboxRepository,
+	transactor repository.Transactor,
+	logger *zap.Logger,
+) *AccountSyncService {
+	return &AccountSyncService{
+		repo:        repo,
+		driverApps:  driverApps,
+		memberships: memberships,
+		outbox:      outbox,
+		transactor:  transactor,
+		logger:      logger,
+	}
+}
+
+// RemoveRestaurant drops every membership of a deleted restaurant and ends
+// the sessions of its members, whose tokens may be scoped to it.
+func (s *AccountSyncService) RemoveRestaurant(ctx context.Context, restaurantID pgtype.UUID) error {
+	members, err := s.memberships.ListByRestaurant(ctx, restaurantID)
+	if err != nil {
+		return err
+	}
+	if len(members) == 0 {
+		return nil
+	}
+
+	err = s.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		for _, member := range members {
+			err := s.memberships.Delete(ctx, member.UserID, restaurantID)
+			if err != nil && !errors.Is(err, repository.ErrMembershipNotFound) {
+				return err
+			}
+			if err := s.repo.RevokeSessions(ctx, member.UserID); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	s.logger.Info("restaurant memberships removed",
+		zap.String("restaurant_id", restaurantID.String()),
+		zap.Int("members", len(members)),
+	)
+
+	return nil
+}
+
+// SuspendForFraud suspends an active account flagged by payment-api. An
+// account that is already locked out is left to the admins.
+func (s *AccountSyncService) SuspendForFraud(ctx context.Context, id pgtype.UUID, reason string) error {
+	user, err := s.repo.GetByID(ctx, id)
+	if errors.Is(err, repository.ErrUserNotFound) {
+		return ErrUserNotFound
+	}
+	if err != nil {
+		return err
+	}
+	if user.Status != db.UserStatusActive {
+		return nil
+	}
+
+	// No actor: the change was made by the system, not an admin.
+	user.Status = db.UserStatusSuspended
+	user.StatusReason = pgtype.Text{String: reason, Valid: reason != ""}
+	user.StatusChangedBy = pgtype.UUID{}
+
+	err = s.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		if _, err := s.repo.UpdateStatus(ctx, user, db.UserStatusActive); err != nil {
+			return err
+		}
+		return enqueueStatusChanged(ctx, s.outbox, pgtype.UUID{}, id, db.UserStatusActive, db.UserStatusSuspended, reason)
+	})
+	// An admin changed the status in the meantime.
+	if errors.Is(err, repository.ErrStatusConflict) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	s.logger.Info("user suspended for fraud", zap.String("user_id", id.String()))
+
+	return nil
+}
+
+// EndDriverContract disables an approved driver and ends their sessions, as
+// their tokens still say they are approved.
+func (s *AccountSyncService) EndDriverContract(ctx context.Context, userID pgtype.UUID, reason string) error {
+	application, err := s.driverApps.GetByUserID(ctx, userID)
+	if errors.Is(err, repository.ErrDriverApplicationNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if application.Status != db.DriverApplicationStatusApproved {
+		return nil
+	}
+
+	application.Status = db.DriverApplicationStatusContractEnded
+	application.ReviewerNotes = pgtype.Text{String: reason, Valid: reason != ""}
+	application.ReviewedBy = pgtype.UUID{}
+
+	err = s.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		if _, err := s.driverApps.Review(ctx, application, db.DriverApplicationStatusApproved); err != nil {
+			return err
+		}
+		return s.repo.RevokeSessions(ctx, userID)
+	})
+	if err != nil {
+		return err
+	}
+
+	s.logger.Info("driver contract ended",
+		zap.String("user_id", userID.String()),
+		zap.String("application_id", application.ID.String()),
+	)
+
+	return nil
+}
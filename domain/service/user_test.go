@@ -0,0 +1,439 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/goodfoodcesi/auth-api/crypto"
+	"github.com/goodfoodcesi/auth-api/domain/repository/repotest"
+	"github.com/goodfoodcesi/auth-api/domain/service"
+	"github.com/goodfoodcesi/auth-api/infrastructure/database/sqlc"
+	"github.com/goodfoodcesi/auth-api/infrastructure/jwt"
+	"github.com/goodfoodcesi/auth-api/infrastructure/messaging/rabbitmq"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/zap"
+)
+
+// grants are the role permissions the tests run with.
+var grants = map[db.UserRole][]string{
+	db.UserRoleClient:  {"orders:create"},
+	db.UserRoleManager: {"restaurant:members", "orders:refund"},
+	db.UserRoleDriver:  {"deliveries:accept"},
+	db.UserRoleAdmin:   {"users:read", "users:manage", service.PermissionsManage},
+}
+
+// users is a UserService over in-memory repositories.
+type users struct {
+	*service.UserService
+	repo         *repotest.Users
+	memberships  *repotest.Memberships
+	drivers      *repotest.DriverApplications
+	permissions  *repotest.Permissions
+	outbox       *repotest.Outbox
+	tokenManager *jwt.TokenManager
+}
+
+func newUsers(stored ...*db.User) *users {
+	u := &users{
+		repo:         repotest.NewUsers(stored...),
+		memberships:  repotest.NewMemberships(),
+		drivers:      repotest.NewDriverApplications(),
+		permissions:  repotest.NewPermissions(grants),
+		outbox:       repotest.NewOutbox(),
+		tokenManager: jwt.NewTokenManager("access-secret", "refresh-secret", time.Hour, 24*time.Hour),
+	}
+	return rebuild(u)
+}
+
+func (u *users) get(t *testing.T, id pgtype.UUID) *db.User {
+	t.Helper()
+
+	user, err := u.repo.GetByID(context.Background(), id)
+	if err != nil {
+		t.Fatalf("GetByID: %v", err)
+	}
+	return user
+}
+
+func TestUpdate(t *testing.T) {
+	tests := []struct {
+		name      string
+		version   int32
+		input     service.UpdateUserInput
+		wantErr   error
+		wantFirst string
+		wantPhone string
+	}{
+		{
+			name:      "changes the given fields only",
+			version:   1,
+			input:     service.UpdateUserInput{FirstName: "Janet", PhoneNumber: "+33612345678"},
+			wantFirst: "Janet",
+			wantPhone: "+33612345678",
+		},
+		{
+			name:      "refuses an outdated ETag",
+			version:   0,
+			input:     service.UpdateUserInput{FirstName: "Janet"},
+			wantErr:   service.ErrStaleVersion,
+			wantFirst: "Jane",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			id := repotest.NewID()
+			u := newUsers(&db.User{ID: id, Firstname: "Jane", Lastname: "Doe", Email: "jane@example.com"})
+
+			updated, err := u.Update(context.Background(), id, tt.version, tt.input)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Update error = %v, want %v", err, tt.wantErr)
+			}
+
+			stored := u.get(t, id)
+			if stored.Firstname != tt.wantFirst || stored.Lastname != "Doe" || stored.PhoneNumber.String != tt.wantPhone {
+				t.Errorf("stored %s %s %q", stored.Firstname, stored.Lastname, stored.PhoneNumber.String)
+			}
+			if tt.wantErr != nil {
+				if events := u.outbox.EventTypes(); len(events) != 0 {
+					t.Errorf("enqueued %v for a refused update", events)
+				}
+				return
+			}
+			// The new version is the ETag of the next update.
+			if updated.Version != tt.version+1 {
+				t.Errorf("version = %d, want %d", updated.Version, tt.version+1)
+			}
+			if events := u.outbox.EventTypes(); len(events) != 1 || events[0] != rabbitmq.UserUpdatedKey {
+				t.Errorf("enqueued %v, want %s", events, rabbitmq.UserUpdatedKey)
+			}
+		})
+	}
+}
+
+func TestChangeRole(t *testing.T) {
+	adminID, userID := repotest.NewID(), repotest.NewID()
+	tests := []struct {
+		name     string
+		actorID  pgtype.UUID
+		targetID pgtype.UUID
+		version  int32
+		role     db.UserRole
+		wantErr  error
+		wantRole db.UserRole
+	}{
+		{name: "promotes a user", actorID: adminID, targetID: userID, version: 1, role: db.UserRoleManager, wantRole: db.UserRoleManager},
+		{name: "refuses an outdated ETag", actorID: adminID, targetID: userID, version: 2, role: db.UserRoleManager, wantErr: service.ErrStaleVersion, wantRole: db.UserRoleClient},
+		{name: "refuses to demote yourself", actorID: adminID, targetID: adminID, version: 1, role: db.UserRoleClient, wantErr: service.ErrSelfManagement, wantRole: db.UserRoleAdmin},
+		{name: "refuses an unknown user", actorID: adminID, targetID: repotest.NewID(), version: 1, role: db.UserRoleManager, wantErr: service.ErrUserNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u := newUsers(
+				&db.User{ID: adminID, Email: "admin@example.com", Role: db.UserRoleAdmin},
+				&db.User{ID: userID, Email: "jane@example.com", Role: db.UserRoleClient},
+			)
+
+			_, err := u.ChangeRole(context.Background(), tt.actorID, tt.targetID, tt.version, service.ChangeRoleInput{Role: tt.role})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ChangeRole error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantRole == "" {
+				return
+			}
+			stored := u.get(t, tt.targetID)
+			if stored.Role != tt.wantRole {
+				t.Errorf("role = %s, want %s", stored.Role, tt.wantRole)
+			}
+			// Tokens issued before the change carry the old role.
+			if stored.SessionsRevokedAt.Valid != (tt.wantErr == nil) {
+				t.Errorf("sessions revoked = %t", stored.SessionsRevokedAt.Valid)
+			}
+			active, err := u.IsActive(context.Background(), tt.targetID.String(), time.Now().Add(-time.Minute))
+			if err != nil {
+				t.Fatalf("IsActive: %v", err)
+			}
+			if active != (tt.wantErr != nil) {
+				t.Errorf("earlier token active = %t", active)
+			}
+
+			wantEvents := 0
+			if tt.wantErr == nil {
+				wantEvents = 1
+			}
+			if events := u.outbox.EventTypes(); len(events) != wantEvents {
+				t.Errorf("enqueued %v, want %d %s", events, wantEvents, rabbitmq.UserRoleChangedKey)
+			}
+		})
+	}
+}
+
+func TestChangeStatus(t *testing.T) {
+	adminID := repotest.NewID()
+	tests := []struct {
+		from      db.UserStatus
+		to        db.UserStatus
+		wantErr   error
+		wantEvent string
+	}{
+		{from: db.UserStatusActive, to: db.UserStatusSuspended, wantEvent: rabbitmq.UserSuspendedKey},
+		{from: db.UserStatusActive, to: db.UserStatusBanned, wantEvent: rabbitmq.UserSuspendedKey},
+		{from: db.UserStatusSuspended, to: db.UserStatusActive, wantEvent: rabbitmq.UserReactivatedKey},
+		{from: db.UserStatusBanned, to: db.UserStatusActive, wantEvent: rabbitmq.UserReactivatedKey},
+		{from: db.UserStatusPending, to: db.UserStatusActive, wantEvent: rabbitmq.UserReactivatedKey},
+		{from: db.UserStatusSuspended, to: db.UserStatusDeleted, wantEvent: rabbitmq.UserDeletedKey},
+		{from: db.UserStatusSuspended, to: db.UserStatusBanned},
+		{from: db.UserStatusPending, to: db.UserStatusSuspended, wantErr: service.ErrInvalidStatusTransition},
+		{from: db.UserStatusBanned, to: db.UserStatusSuspended, wantErr: service.ErrInvalidStatusTransition},
+		{from: db.UserStatusDeleted, to: db.UserStatusActive, wantErr: service.ErrInvalidStatusTransition},
+		{from: db.UserStatusActive, to: db.UserStatusActive, wantErr: service.ErrInvalidStatusTransition},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.from)+" to "+string(tt.to), func(t *testing.T) {
+			id := repotest.NewID()
+			u := newUsers(&db.User{ID: id, Email: "jane@example.com", Status: tt.from})
+
+			_, err := u.ChangeStatus(context.Background(), adminID, id, tt.to, service.ChangeStatusInput{Reason: "support ticket"})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ChangeStatus error = %v, want %v", err, tt.wantErr)
+			}
+
+			stored := u.get(t, id)
+			want := tt.to
+			if tt.wantErr != nil {
+				want = tt.from
+			}
+			if stored.Status != want {
+				t.Errorf("status = %s, want %s", stored.Status, want)
+			}
+			if tt.wantErr == nil && (stored.StatusChangedBy != adminID || stored.StatusReason.String != "support ticket") {
+				t.Errorf("changed by %s because %q", stored.StatusChangedBy, stored.StatusReason.String)
+			}
+
+			var wantEvents []string
+			if tt.wantEvent != "" {
+				wantEvents = []string{tt.wantEvent}
+			}
+			if events := u.outbox.EventTypes(); !equal(events, wantEvents) {
+				t.Errorf("enqueued %v, want %v", events, wantEvents)
+			}
+		})
+	}
+
+	t.Run("refuses to change your own status", func(t *testing.T) {
+		u := newUsers(&db.User{ID: adminID, Email: "admin@example.com", Role: db.UserRoleAdmin})
+
+		_, err := u.ChangeStatus(context.Background(), adminID, adminID, db.UserStatusSuspended, service.ChangeStatusInput{})
+		if !errors.Is(err, service.ErrSelfManagement) {
+			t.Fatalf("ChangeStatus error = %v, want %v", err, service.ErrSelfManagement)
+		}
+	})
+}
+
+func TestListUsers(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	var stored []*db.User
+	for i, email := range []string{"e@example.com", "c@example.com", "a@example.com", "d@example.com", "b@example.com"} {
+		role := db.UserRoleClient
+		if i%2 == 1 {
+			role = db.UserRoleManager
+		}
+		stored = append(stored, &db.User{
+			Email:     email,
+			Role:      role,
+			CreatedAt: pgtype.Timestamptz{Time: start.Add(time.Duration(i) * time.Hour), Valid: true},
+		})
+	}
+
+	tests := []struct {
+		name  string
+		input service.ListUsersInput
+		want  [][]string
+	}{
+		{
+			name:  "newest first by default",
+			input: service.ListUsersInput{Limit: 2},
+			want:  [][]string{{"b@example.com", "d@example.com"}, {"a@example.com", "c@example.com"}, {"e@example.com"}},
+		},
+		{
+			name:  "by email",
+			input: service.ListUsersInput{Sort: "email_asc", Limit: 3},
+			want:  [][]string{{"a@example.com", "b@example.com", "c@example.com"}, {"d@example.com", "e@example.com"}},
+		},
+		{
+			name:  "by email descending",
+			input: service.ListUsersInput{Sort: "email_desc", Limit: 5},
+			want:  [][]string{{"e@example.com", "d@example.com", "c@example.com", "b@example.com", "a@example.com"}},
+		},
+		{
+			name:  "filtered by role",
+			input: service.ListUsersInput{Role: "manager", Sort: "created_at_asc", Limit: 1},
+			want:  [][]string{{"c@example.com"}, {"d@example.com"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u := newUsers(stored...)
+
+			input := tt.input
+			for i, want := range tt.want {
+				page, err := u.ListUsers(context.Background(), input)
+				if err != nil {
+					t.Fatalf("page %d: ListUsers: %v", i+1, err)
+				}
+				var emails []string
+				for _, user := range page.Users {
+					emails = append(emails, user.Email)
+				}
+				if !equal(emails, want) {
+					t.Errorf("page %d = %v, want %v", i+1, emails, want)
+				}
+
+				last := i == len(tt.want)-1
+				if last != (page.NextCursor == "") {
+					t.Fatalf("page %d: next cursor %q", i+1, page.NextCursor)
+				}
+				input.Cursor = page.NextCursor
+			}
+		})
+	}
+
+	t.Run("refuses a malformed cursor", func(t *testing.T) {
+		u := newUsers(stored...)
+
+		_, err := u.ListUsers(context.Background(), service.ListUsersInput{Cursor: "bm90LWpzb24"})
+		if !errors.Is(err, service.ErrInvalidCursor) {
+			t.Fatalf("ListUsers error = %v, want %v", err, service.ErrInvalidCursor)
+		}
+	})
+}
+
+func TestIssueRestaurantTokens(t *testing.T) {
+	restaurantID, otherRestaurantID := repotest.NewID(), repotest.NewID()
+	approved := true
+	tests := []struct {
+		name           string
+		user           db.User
+		memberships    []db.Membership
+		application    *db.DriverApplication
+		restaurantID   pgtype.UUID
+		wantErr        error
+		wantRestaurant pgtype.UUID
+		wantApproved   *bool
+	}{
+		{
+			name:           "scopes to a restaurant of the user",
+			user:           db.User{Role: db.UserRoleManager},
+			memberships:    []db.Membership{{RestaurantID: restaurantID, Role: db.MembershipRoleOwner}, {RestaurantID: otherRestaurantID, Role: db.MembershipRoleStaff}},
+			restaurantID:   otherRestaurantID,
+			wantRestaurant: otherRestaurantID,
+		},
+		{
+			name:         "refuses a restaurant the user does not belong to",
+			user:         db.User{Role: db.UserRoleManager},
+			memberships:  []db.Membership{{RestaurantID: restaurantID, Role: db.MembershipRoleOwner}},
+			restaurantID: otherRestaurantID,
+			wantErr:      service.ErrNotRestaurantMember,
+		},
+		{
+			name:           "picks the only restaurant of a manager",
+			user:           db.User{Role: db.UserRoleManager},
+			memberships:    []db.Membership{{RestaurantID: restaurantID, Role: db.MembershipRoleOwner}},
+			wantRestaurant: restaurantID,
+		},
+		{
+			name:        "leaves a manager of several restaurants unscoped",
+			user:        db.User{Role: db.UserRoleManager},
+			memberships: []db.Membership{{RestaurantID: restaurantID, Role: db.MembershipRoleOwner}, {RestaurantID: otherRestaurantID, Role: db.MembershipRoleStaff}},
+		},
+		{
+			name:         "says whether a driver is approved",
+			user:         db.User{Role: db.UserRoleDriver},
+			application:  &db.DriverApplication{Status: db.DriverApplicationStatusApproved},
+			wantApproved: &approved,
+		},
+		{
+			name:    "refuses an inactive account",
+			user:    db.User{Role: db.UserRoleClient, Status: db.UserStatusSuspended},
+			wantErr: service.ErrAccountInactive,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user := tt.user
+			user.ID = repotest.NewID()
+			u := newUsers(&user)
+			for _, membership := range tt.memberships {
+				membership.UserID = user.ID
+				if _, err := u.memberships.Save(context.Background(), &membership); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if tt.application != nil {
+				application := *tt.application
+				application.UserID = user.ID
+				u.drivers = repotest.NewDriverApplications(application)
+				u = rebuild(u)
+			}
+
+			tokens, err := u.IssueRestaurantTokens(context.Background(), u.get(t, user.ID), tt.restaurantID)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("IssueRestaurantTokens error = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+
+			claims, err := u.tokenManager.ValidateToken(tokens.AccessToken, false)
+			if err != nil {
+				t.Fatalf("ValidateToken: %v", err)
+			}
+			wantRestaurant := ""
+			if tt.wantRestaurant.Valid {
+				wantRestaurant = tt.wantRestaurant.String()
+			}
+			if claims.RestaurantID != wantRestaurant {
+				t.Errorf("restaurant = %q, want %q", claims.RestaurantID, wantRestaurant)
+			}
+			if claims.UserID != user.ID.String() || claims.Role != user.Role || !equal(claims.Permissions, grants[user.Role]) {
+				t.Errorf("issued to %s as %s with %v", claims.UserID, claims.Role, claims.Permissions)
+			}
+			if (claims.Approved == nil) != (tt.wantApproved == nil) || claims.Approved != nil && *claims.Approved != *tt.wantApproved {
+				t.Errorf("approved = %v, want %v", claims.Approved, tt.wantApproved)
+			}
+			if _, err := u.tokenManager.ValidateToken(tokens.RefreshToken, true); err != nil {
+				t.Errorf("refresh token: %v", err)
+			}
+		})
+	}
+}
+
+// rebuild makes the UserService of u over its repositories, again when one of
+// them was replaced.
+func rebuild(u *users) *users {
+	u.UserService = service.NewUserService(
+		u.repo,
+		u.drivers,
+		u.memberships,
+		u.permissions,
+		u.outbox,
+		repotest.Transactor{},
+		u.tokenManager,
+		crypto.NewPasswordManager("secret"),
+		zap.NewNop(),
+	)
+	return u
+}
+
+func equal(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
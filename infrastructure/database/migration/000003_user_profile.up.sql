@@ -0,0 +1,25 @@
+ALTER TABLE users
+    ADD COLUMN phone_number VARCHAR(16),
+    ADD COLUMN phone_verified_at TIMESTAMPTZ,
+    ADD COLUMN locale VARCHAR(35) NOT NULL DEFAULT 'fr-FR',
+    ADD COLUMN timezone VARCHAR(64) NOT NULL DEFAULT 'Europe/Paris',
+    ADD COLUMN avatar_url TEXT;
+
+CREATE TABLE user_addresses (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    label VARCHAR(50) NOT NULL,
+    line1 VARCHAR(255) NOT NULL,
+    line2 VARCHAR(255),
+    postcode VARCHAR(20) NOT NULL,
+    city VARCHAR(100) NOT NULL,
+    country_code CHAR(2) NOT NULL DEFAULT 'FR',
+    latitude DOUBLE PRECISION,
+    longitude DOUBLE PRECISION,
+    is_default BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_user_addresses_user_id ON user_addresses(user_id);
+CREATE UNIQUE INDEX idx_user_addresses_default ON user_addresses(user_id) WHERE is_default;
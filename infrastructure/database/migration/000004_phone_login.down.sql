@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS phone_otps;
+DROP INDEX IF EXISTS idx_users_phone_number;
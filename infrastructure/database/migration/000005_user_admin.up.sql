@@ -0,0 +1,8 @@
+ALTER TABLE users ADD COLUMN suspended_at TIMESTAMPTZ;
+
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+CREATE INDEX idx_users_created_at ON users(created_at DESC, id DESC);
+CREATE INDEX idx_users_role ON users(role);
+CREATE INDEX idx_users_email_trgm ON users USING gin (email gin_trgm_ops);
+CREATE INDEX idx_users_name_trgm ON users USING gin ((firstname || ' ' || lastname) gin_trgm_ops);
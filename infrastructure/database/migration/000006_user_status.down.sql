@@ -0,0 +1,13 @@
+ALTER TABLE users ADD COLUMN suspended_at TIMESTAMPTZ;
+
+UPDATE users SET suspended_at = COALESCE(status_changed_at, NOW()) WHERE status <> 'active';
+
+DROP INDEX IF EXISTS idx_users_status;
+
+ALTER TABLE users
+    DROP COLUMN IF EXISTS status_changed_at,
+    DROP COLUMN IF EXISTS status_changed_by,
+    DROP COLUMN IF EXISTS status_reason,
+    DROP COLUMN IF EXISTS status;
+
+DROP TYPE IF EXISTS user_status;
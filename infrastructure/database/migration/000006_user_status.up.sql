@@ -0,0 +1,13 @@
+CREATE TYPE user_status AS ENUM ('pending', 'active', 'suspended', 'banned', 'deleted');
+
+ALTER TABLE users
+    ADD COLUMN status user_status NOT NULL DEFAULT 'active',
+    ADD COLUMN status_reason TEXT,
+    ADD COLUMN status_changed_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    ADD COLUMN status_changed_at TIMESTAMPTZ;
+
+UPDATE users SET status = 'suspended', status_changed_at = suspended_at WHERE suspended_at IS NOT NULL;
+
+ALTER TABLE users DROP COLUMN suspended_at;
+
+CREATE INDEX idx_users_status ON users(status);
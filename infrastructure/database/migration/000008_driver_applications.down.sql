@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS driver_applications;
+DROP TYPE IF EXISTS driver_application_status;
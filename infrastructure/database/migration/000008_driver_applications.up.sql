@@ -0,0 +1,17 @@
+CREATE TYPE driver_application_status AS ENUM ('submitted', 'under_review', 'approved', 'rejected');
+
+CREATE TABLE driver_applications (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL UNIQUE REFERENCES users(id) ON DELETE CASCADE,
+    status driver_application_status NOT NULL DEFAULT 'submitted',
+    vehicle_type VARCHAR(50) NOT NULL,
+    license_number VARCHAR(50) NOT NULL,
+    document_url TEXT NOT NULL,
+    reviewer_notes TEXT,
+    reviewed_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    reviewed_at TIMESTAMPTZ,
+    submitted_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_driver_applications_queue ON driver_applications(status, submitted_at);
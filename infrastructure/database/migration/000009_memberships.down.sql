@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS memberships;
+DROP TYPE IF EXISTS membership_role;
@@ -0,0 +1,13 @@
+CREATE TYPE membership_role AS ENUM ('owner', 'staff');
+
+CREATE TABLE memberships (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    restaurant_id UUID NOT NULL,
+    role membership_role NOT NULL,
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (user_id, restaurant_id)
+);
+
+CREATE INDEX idx_memberships_restaurant ON memberships(restaurant_id);
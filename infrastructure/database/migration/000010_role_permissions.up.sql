@@ -0,0 +1,45 @@
+CREATE TABLE permissions (
+    name VARCHAR(100) PRIMARY KEY,
+    description TEXT NOT NULL
+);
+
+CREATE TABLE role_permissions (
+    role user_role NOT NULL,
+    permission VARCHAR(100) NOT NULL REFERENCES permissions(name) ON DELETE CASCADE,
+    PRIMARY KEY (role, permission)
+);
+
+INSERT INTO permissions (name, description) VALUES
+    ('profile:read', 'Read your own profile'),
+    ('profile:write', 'Update your own profile and addresses'),
+    ('orders:create', 'Place orders'),
+    ('orders:read', 'Read orders you are involved in'),
+    ('orders:refund', 'Refund an order'),
+    ('restaurant:manage', 'Edit restaurant details and menus'),
+    ('restaurant:members', 'Manage the members of a restaurant'),
+    ('invitations:manage', 'Invite staff and revoke invitations'),
+    ('deliveries:accept', 'Accept and carry out deliveries'),
+    ('drivers:review', 'Review driver applications'),
+    ('users:read', 'Read any user account'),
+    ('users:manage', 'Change the role and status of any user'),
+    ('permissions:manage', 'Edit the permissions granted to each role');
+
+INSERT INTO role_permissions (role, permission) VALUES
+    ('client', 'profile:read'),
+    ('client', 'profile:write'),
+    ('client', 'orders:create'),
+    ('client', 'orders:read'),
+    ('manager', 'profile:read'),
+    ('manager', 'profile:write'),
+    ('manager', 'orders:read'),
+    ('manager', 'orders:refund'),
+    ('manager', 'restaurant:manage'),
+    ('manager', 'restaurant:members'),
+    ('manager', 'invitations:manage'),
+    ('driver', 'profile:read'),
+    ('driver', 'profile:write'),
+    ('driver', 'orders:read'),
+    ('driver', 'deliveries:accept');
+
+INSERT INTO role_permissions (role, permission)
+SELECT 'admin', name FROM permissions;
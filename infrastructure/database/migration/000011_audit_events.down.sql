@@ -0,0 +1,2 @@
+DELETE FROM permissions WHERE name = 'users:impersonate';
+DROP TABLE IF EXISTS audit_events;
@@ -0,0 +1,18 @@
+CREATE TABLE audit_events (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    action VARCHAR(100) NOT NULL,
+    actor_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    subject_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    detail TEXT,
+    ip VARCHAR(45),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_audit_events_actor ON audit_events(actor_id, created_at DESC);
+CREATE INDEX idx_audit_events_subject ON audit_events(subject_id, created_at DESC);
+
+INSERT INTO permissions (name, description) VALUES
+    ('users:impersonate', 'Sign in as another user with a read-only token');
+
+INSERT INTO role_permissions (role, permission) VALUES
+    ('admin', 'users:impersonate');
@@ -0,0 +1,24 @@
+CREATE TABLE api_keys (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    prefix VARCHAR(16) NOT NULL UNIQUE,
+    secret_hash VARCHAR(64) NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    owner_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    restaurant_id UUID NOT NULL,
+    scopes TEXT[] NOT NULL DEFAULT '{}',
+    ip_allowlist TEXT[] NOT NULL DEFAULT '{}',
+    expires_at TIMESTAMPTZ,
+    last_used_at TIMESTAMPTZ,
+    last_used_ip VARCHAR(45),
+    revoked_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_api_keys_restaurant ON api_keys(restaurant_id, created_at DESC);
+
+INSERT INTO permissions (name, description) VALUES
+    ('api-keys:manage', 'Create and revoke API keys for a restaurant');
+
+INSERT INTO role_permissions (role, permission) VALUES
+    ('manager', 'api-keys:manage'),
+    ('admin', 'api-keys:manage');
@@ -0,0 +1,10 @@
+-- Consumers record here, in the transaction of their side effects, the
+-- messages they processed so that a redelivery is skipped.
+CREATE TABLE processed_messages (
+    message_id VARCHAR(100) NOT NULL,
+    consumer VARCHAR(100) NOT NULL,
+    processed_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (message_id, consumer)
+);
+
+CREATE INDEX idx_processed_messages_processed_at ON processed_messages(processed_at);
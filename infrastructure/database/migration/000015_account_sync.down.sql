@@ -0,0 +1,6 @@
+ALTER TABLE users DROP COLUMN IF EXISTS sessions_revoked_at;
+
+-- Enum values cannot be dropped. The drivers whose contract ended must not
+-- get their approval back, so their applications are marked rejected, the
+-- other status that keeps them off deliveries.
+UPDATE driver_applications SET status = 'rejected' WHERE status = 'contract_ended';
@@ -0,0 +1,6 @@
+-- Tokens issued before sessions_revoked_at are refused, so other services can
+-- have a user's sessions ended before they expire.
+ALTER TABLE users ADD COLUMN sessions_revoked_at TIMESTAMPTZ;
+
+-- Set when delivery-api reports that an approved driver's contract ended.
+ALTER TYPE driver_application_status ADD VALUE IF NOT EXISTS 'contract_ended';
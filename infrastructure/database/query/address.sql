@@ -0,0 +1,29 @@
+-- name: CreateAddress :one
+INSERT INTO user_addresses (
+    user_id, label, line1, line2, postcode, city, country_code, latitude, longitude, is_default
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10
+) RETURNING *;
+
+-- name: GetAddress :one
+SELECT * FROM user_addresses WHERE id = $1 AND user_id = $2;
+
+-- name: ListAddressesByUser :many
+SELECT * FROM user_addresses WHERE user_id = $1 ORDER BY is_default DESC, created_at;
+
+-- name: CountAddressesByUser :one
+SELECT count(*) FROM user_addresses WHERE user_id = $1;
+
+-- name: UpdateAddress :one
+UPDATE user_addresses
+SET label = $3, line1 = $4, line2 = $5, postcode = $6, city = $7, country_code = $8,
+    latitude = $9, longitude = $10, is_default = $11, updated_at = now()
+WHERE id = $1 AND user_id = $2
+RETURNING *;
+
+-- name: ClearDefaultAddress :exec
+-- the default flag is unique per user, so it is cleared before another address takes it
+UPDATE user_addresses SET is_default = FALSE, updated_at = now() WHERE user_id = $1 AND is_default;
+
+-- name: DeleteAddress :execrows
+DELETE FROM user_addresses WHERE id = $1 AND user_id = $2;
@@ -0,0 +1,18 @@
+-- name: CreateApiKey :one
+INSERT INTO api_keys (
+    prefix, secret_hash, name, owner_id, restaurant_id, scopes, ip_allowlist, expires_at
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8
+) RETURNING *;
+
+-- name: GetApiKeyByPrefix :one
+SELECT * FROM api_keys WHERE prefix = $1;
+
+-- name: ListApiKeysByRestaurant :many
+SELECT * FROM api_keys WHERE restaurant_id = $1 ORDER BY created_at DESC;
+
+-- name: RevokeApiKey :execrows
+UPDATE api_keys SET revoked_at = now() WHERE id = $1 AND restaurant_id = $2 AND revoked_at IS NULL;
+
+-- name: TouchApiKey :exec
+UPDATE api_keys SET last_used_at = now(), last_used_ip = $2 WHERE id = $1;
@@ -0,0 +1,6 @@
+-- name: CreateAuditEvent :exec
+INSERT INTO audit_events (
+    action, actor_id, subject_id, detail, ip
+) VALUES (
+    $1, $2, $3, $4, $5
+);
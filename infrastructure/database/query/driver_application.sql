@@ -0,0 +1,36 @@
+-- name: CreateDriverApplication :one
+INSERT INTO driver_applications (
+    user_id, vehicle_type, license_number, document_url
+) VALUES (
+    $1, $2, $3, $4
+) RETURNING *;
+
+-- name: GetDriverApplicationByID :one
+SELECT * FROM driver_applications WHERE id = $1;
+
+-- name: GetDriverApplicationByUserID :one
+SELECT * FROM driver_applications WHERE user_id = $1;
+
+-- name: ListDriverApplications :many
+-- without a status this is the review queue: pending applications, oldest first
+SELECT * FROM driver_applications
+WHERE (sqlc.narg('status')::driver_application_status IS NULL AND status IN ('submitted', 'under_review'))
+   OR status = sqlc.narg('status')::driver_application_status
+ORDER BY submitted_at ASC
+LIMIT 200;
+
+-- name: ReviewDriverApplication :one
+-- compare-and-set on the current status so two reviewers cannot decide the same application
+UPDATE driver_applications
+SET status = sqlc.arg('status'), reviewer_notes = sqlc.arg('reviewer_notes'), reviewed_by = sqlc.arg('reviewed_by'),
+    reviewed_at = now(), updated_at = now()
+WHERE id = sqlc.arg('id') AND status = sqlc.arg('from_status')
+RETURNING *;
+
+-- name: ResubmitDriverApplication :one
+-- only a rejected application goes back to the queue, the previous review notes are kept for the next reviewer
+UPDATE driver_applications
+SET status = 'submitted', vehicle_type = $2, license_number = $3, document_url = $4,
+    submitted_at = now(), updated_at = now()
+WHERE user_id = $1 AND status = 'rejected'
+RETURNING *;
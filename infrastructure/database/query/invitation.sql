@@ -0,0 +1,27 @@
+-- name: CreateInvitation :one
+INSERT INTO invitations (
+    email, role, restaurant_id, token_hash, invited_by, expires_at
+) VALUES (
+    $1, $2, $3, $4, $5, $6
+) RETURNING *;
+
+-- name: GetInvitationByID :one
+SELECT * FROM invitations WHERE id = $1;
+
+-- name: GetInvitationByTokenHash :one
+SELECT * FROM invitations WHERE token_hash = $1;
+
+-- name: ListInvitations :many
+-- admins list every invitation, other inviters only their own
+SELECT * FROM invitations
+WHERE sqlc.narg('invited_by')::uuid IS NULL OR invited_by = sqlc.narg('invited_by')::uuid
+ORDER BY created_at DESC
+LIMIT 200;
+
+-- name: RevokeInvitation :execrows
+UPDATE invitations SET revoked_at = now() WHERE id = $1 AND accepted_at IS NULL AND revoked_at IS NULL;
+
+-- name: AcceptInvitation :execrows
+-- an invitation can be redeemed once, before it expires and unless it was revoked
+UPDATE invitations SET accepted_at = now(), accepted_by = $2
+WHERE id = $1 AND accepted_at IS NULL AND revoked_at IS NULL AND expires_at > now();
@@ -0,0 +1,21 @@
+-- name: UpsertMembership :one
+-- adding an existing member only changes their role
+INSERT INTO memberships (
+    user_id, restaurant_id, role, created_by
+) VALUES (
+    $1, $2, $3, $4
+)
+ON CONFLICT (user_id, restaurant_id) DO UPDATE SET role = EXCLUDED.role
+RETURNING *;
+
+-- name: GetMembership :one
+SELECT * FROM memberships WHERE user_id = $1 AND restaurant_id = $2;
+
+-- name: ListMembershipsByUser :many
+SELECT * FROM memberships WHERE user_id = $1 ORDER BY created_at;
+
+-- name: ListMembershipsByRestaurant :many
+SELECT * FROM memberships WHERE restaurant_id = $1 ORDER BY created_at;
+
+-- name: DeleteMembership :execrows
+DELETE FROM memberships WHERE user_id = $1 AND restaurant_id = $2;
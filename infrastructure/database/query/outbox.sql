@@ -0,0 +1,44 @@
+-- name: CreateOutboxMessage :exec
+INSERT INTO outbox (
+    aggregate_type, aggregate_id, event_type, exchange, routing_key, payload
+) VALUES (
+    $1, $2, $3, $4, $5, $6
+);
+
+-- name: ClaimOutboxMessages :many
+-- Leases due messages until lease_until so concurrent relays skip them.
+UPDATE outbox SET available_at = sqlc.arg(lease_until)
+WHERE id IN (
+    SELECT id FROM outbox
+    WHERE sent_at IS NULL AND available_at <= now()
+    ORDER BY created_at
+    LIMIT sqlc.arg(batch_size)
+    FOR UPDATE SKIP LOCKED
+) RETURNING *;
+
+-- name: MarkOutboxMessageSent :exec
+UPDATE outbox SET sent_at = now(), last_error = NULL WHERE id = $1;
+
+-- name: MarkOutboxMessageFailed :exec
+UPDATE outbox SET attempts = attempts + 1, last_error = $2, available_at = $3 WHERE id = $1;
+
+-- name: GetOutboxMessage :one
+SELECT * FROM outbox WHERE id = $1;
+
+-- name: ListOutboxMessages :many
+SELECT * FROM outbox
+WHERE sqlc.arg(status)::text = ''
+   OR (sqlc.arg(status) = 'pending' AND sent_at IS NULL)
+   OR (sqlc.arg(status) = 'failed' AND sent_at IS NULL AND attempts > 0)
+   OR (sqlc.arg(status) = 'sent' AND sent_at IS NOT NULL)
+ORDER BY created_at DESC
+LIMIT sqlc.arg(max_rows);
+
+-- name: ReplayOutboxMessage :execrows
+UPDATE outbox SET sent_at = NULL, attempts = 0, last_error = NULL, available_at = now() WHERE id = $1;
+
+-- name: CountPendingOutboxMessages :one
+SELECT count(*) FROM outbox WHERE sent_at IS NULL;
+
+-- name: OldestPendingOutboxMessage :one
+SELECT min(created_at)::timestamptz AS oldest FROM outbox WHERE sent_at IS NULL;
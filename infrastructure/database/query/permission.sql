@@ -0,0 +1,14 @@
+-- name: ListPermissions :many
+SELECT * FROM permissions ORDER BY name;
+
+-- name: ListRolePermissions :many
+SELECT * FROM role_permissions ORDER BY role, permission;
+
+-- name: ListPermissionsForRole :many
+SELECT permission FROM role_permissions WHERE role = $1 ORDER BY permission;
+
+-- name: DeleteRolePermissions :exec
+DELETE FROM role_permissions WHERE role = $1;
+
+-- name: AddRolePermission :exec
+INSERT INTO role_permissions (role, permission) VALUES ($1, $2);
@@ -0,0 +1,28 @@
+-- name: CreatePhoneOtp :one
+INSERT INTO phone_otps (
+    phone_number, code_hash, expires_at
+) VALUES (
+    $1, $2, $3
+) RETURNING *;
+
+-- name: CountPhoneOtpsSince :one
+SELECT count(*) FROM phone_otps WHERE phone_number = $1 AND created_at > $2;
+
+-- name: GetActivePhoneOtp :one
+SELECT * FROM phone_otps
+WHERE phone_number = $1 AND consumed_at IS NULL AND expires_at > now()
+ORDER BY created_at DESC
+LIMIT 1;
+
+-- name: IncrementPhoneOtpAttempts :one
+-- counts the attempt only while some are left, so parallel guesses cannot exceed max_attempts
+UPDATE phone_otps SET attempts = attempts + 1
+WHERE id = sqlc.arg('id') AND attempts < sqlc.arg('max_attempts')
+RETURNING attempts;
+
+-- name: ConsumePhoneOtp :execrows
+UPDATE phone_otps SET consumed_at = now() WHERE id = $1 AND consumed_at IS NULL;
+
+-- name: InvalidatePhoneOtps :exec
+-- only the latest code sent to a number stays usable
+UPDATE phone_otps SET consumed_at = now() WHERE phone_number = $1 AND consumed_at IS NULL;
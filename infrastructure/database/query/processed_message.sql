@@ -0,0 +1,6 @@
+-- name: MarkMessageProcessed :execrows
+-- affects no row when the consumer already processed the message
+INSERT INTO processed_messages (message_id, consumer) VALUES ($1, $2) ON CONFLICT DO NOTHING;
+
+-- name: DeleteProcessedMessagesBefore :execrows
+DELETE FROM processed_messages WHERE processed_at < $1;
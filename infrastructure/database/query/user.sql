@@ -11,6 +11,61 @@ SELECT * FROM users WHERE email = $1;
 -- name: GetUserByID :one
 SELECT * FROM users WHERE id = $1;
 
--- name: UpdateUser :exec
--- only update firstname, lastname, updated_at
-UPDATE users SET firstname = $2, lastname = $3, updated_at = now() WHERE id = $1;
\ No newline at end of file
+-- name: GetUserByPhone :one
+SELECT * FROM users WHERE phone_number = $1;
+
+-- name: UpdateUser :one
+-- only update profile fields when the caller holds the current version, a new phone number loses its verification
+UPDATE users
+SET firstname = $2, lastname = $3, phone_number = $5,
+    phone_verified_at = CASE WHEN phone_number IS NOT DISTINCT FROM $5 THEN phone_verified_at END,
+    locale = $6, timezone = $7, avatar_url = $8, version = version + 1, updated_at = now()
+WHERE id = $1 AND version = $4
+RETURNING *;
+
+-- name: MarkPhoneVerified :exec
+-- proving possession of the phone through an OTP verifies it
+UPDATE users SET phone_verified_at = now(), version = version + 1, updated_at = now() WHERE id = $1 AND phone_verified_at IS NULL;
+
+-- name: ListUsers :many
+-- keyset pagination: the cursor holds the sort key and id of the last row of the previous page
+SELECT * FROM users
+WHERE (sqlc.narg('role')::user_role IS NULL OR role = sqlc.narg('role')::user_role)
+  AND (sqlc.narg('status')::user_status IS NULL OR status = sqlc.narg('status')::user_status)
+  AND (sqlc.narg('created_after')::timestamptz IS NULL OR created_at >= sqlc.narg('created_after')::timestamptz)
+  AND (sqlc.narg('created_before')::timestamptz IS NULL OR created_at < sqlc.narg('created_before')::timestamptz)
+  AND (sqlc.narg('search')::text IS NULL
+       OR email ILIKE '%' || sqlc.narg('search')::text || '%'
+       OR (firstname || ' ' || lastname) ILIKE '%' || sqlc.narg('search')::text || '%')
+  AND (sqlc.narg('cursor_id')::uuid IS NULL OR CASE sqlc.arg('sort')::text
+        WHEN 'created_at_asc' THEN (created_at, id) > (sqlc.narg('cursor_created_at')::timestamptz, sqlc.narg('cursor_id')::uuid)
+        WHEN 'email_asc' THEN (email, id) > (sqlc.narg('cursor_email')::text, sqlc.narg('cursor_id')::uuid)
+        WHEN 'email_desc' THEN (email, id) < (sqlc.narg('cursor_email')::text, sqlc.narg('cursor_id')::uuid)
+        ELSE (created_at, id) < (sqlc.narg('cursor_created_at')::timestamptz, sqlc.narg('cursor_id')::uuid)
+      END)
+ORDER BY
+    CASE WHEN sqlc.arg('sort')::text = 'created_at_asc' THEN created_at END ASC,
+    CASE WHEN sqlc.arg('sort')::text = 'email_asc' THEN email END ASC,
+    CASE WHEN sqlc.arg('sort')::text = 'email_desc' THEN email END DESC,
+    CASE WHEN sqlc.arg('sort')::text = 'created_at_desc' THEN created_at END DESC,
+    CASE WHEN sqlc.arg('sort')::text IN ('created_at_asc', 'email_asc') THEN id END ASC,
+    id DESC
+LIMIT sqlc.arg('page_limit')::int;
+
+-- name: UpdateUserRole :one
+UPDATE users SET role = $2, version = version + 1, updated_at = now() WHERE id = $1 AND version = $3 RETURNING *;
+
+-- name: RevokeUserSessions :exec
+-- tokens issued before sessions_revoked_at are no longer accepted
+UPDATE users SET sessions_revoked_at = now(), updated_at = now() WHERE id = $1;
+
+-- name: UpdateUserPassword :exec
+UPDATE users SET password_hash = $2, version = version + 1, updated_at = now() WHERE id = $1;
+
+-- name: UpdateUserStatus :one
+-- compare-and-set on the current status so two admins cannot apply conflicting transitions
+UPDATE users
+SET status = sqlc.arg('status'), status_reason = sqlc.arg('status_reason'), status_changed_by = sqlc.arg('status_changed_by'),
+    status_changed_at = now(), version = version + 1, updated_at = now()
+WHERE id = sqlc.arg('id') AND status = sqlc.arg('from_status')
+RETURNING *;
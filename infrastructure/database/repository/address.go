@@ -0,0 +1,136 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/goodfoodcesi/auth-api/domain/repository"
+	"github.com/goodfoodcesi/auth-api/infrastructure/database/sqlc"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type AddressRepository struct {
+	dbPool *pgxpool.Pool
+	q      *db.Queries
+}
+
+func NewAddressRepository(dbPool *pgxpool.Pool) *AddressRepository {
+	return &AddressRepository{
+		dbPool: dbPool,
+		q:      db.New(dbPool),
+	}
+}
+
+func (r *AddressRepository) Create(ctx context.Context, address *db.UserAddress) (*db.UserAddress, error) {
+	var created db.UserAddress
+	err := r.withTx(ctx, func(q *db.Queries) error {
+		count, err := q.CountAddressesByUser(ctx, address.UserID)
+		if err != nil {
+			return err
+		}
+
+		// The first address of a user is always the default one.
+		isDefault := address.IsDefault || count == 0
+		if isDefault {
+			if err := q.ClearDefaultAddress(ctx, address.UserID); err != nil {
+				return err
+			}
+		}
+
+		created, err = q.CreateAddress(ctx, db.CreateAddressParams{
+			UserID:      address.UserID,
+			Label:       address.Label,
+			Line1:       address.Line1,
+			Line2:       address.Line2,
+			Postcode:    address.Postcode,
+			City:        address.City,
+			CountryCode: address.CountryCode,
+			Latitude:    address.Latitude,
+			Longitude:   address.Longitude,
+			IsDefault:   isDefault,
+		})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &created, nil
+}
+
+func (r *AddressRepository) GetByID(ctx context.Context, userID, id pgtype.UUID) (*db.UserAddress, error) {
+	address, err := r.q.GetAddress(ctx, db.GetAddressParams{ID: id, UserID: userID})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, repository.ErrAddressNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &address, nil
+}
+
+func (r *AddressRepository) ListByUser(ctx context.Context, userID pgtype.UUID) ([]db.UserAddress, error) {
+	return r.q.ListAddressesByUser(ctx, userID)
+}
+
+func (r *AddressRepository) Update(ctx context.Context, address *db.UserAddress) (*db.UserAddress, error) {
+	var updated db.UserAddress
+	err := r.withTx(ctx, func(q *db.Queries) error {
+		if address.IsDefault {
+			if err := q.ClearDefaultAddress(ctx, address.UserID); err != nil {
+				return err
+			}
+		}
+
+		var err error
+		updated, err = q.UpdateAddress(ctx, db.UpdateAddressParams{
+			ID:          address.ID,
+			UserID:      address.UserID,
+			Label:       address.Label,
+			Line1:       address.Line1,
+			Line2:       address.Line2,
+			Postcode:    address.Postcode,
+			City:        address.City,
+			CountryCode: address.CountryCode,
+			Latitude:    address.Latitude,
+			Longitude:   address.Longitude,
+			IsDefault:   address.IsDefault,
+		})
+		if errors.Is(err, pgx.ErrNoRows) {
+			return repository.ErrAddressNotFound
+		}
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &updated, nil
+}
+
+func (r *AddressRepository) Delete(ctx context.Context, userID, id pgtype.UUID) error {
+	rows, err := r.q.DeleteAddress(ctx, db.DeleteAddressParams{ID: id, UserID: userID})
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return repository.ErrAddressNotFound
+	}
+	return nil
+}
+
+func (r *AddressRepository) withTx(ctx context.Context, fn func(q *db.Queries) error) error {
+	tx, err := begin(ctx, r.dbPool)
+	if err != nil {
+		return err
+	}
+	//nolint:errcheck
+	defer tx.Rollback(ctx)
+
+	if err := fn(r.q.WithTx(tx)); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
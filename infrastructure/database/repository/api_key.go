@@ -0,0 +1,75 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/goodfoodcesi/auth-api/domain/repository"
+	"github.com/goodfoodcesi/auth-api/infrastructure/database/sqlc"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type APIKeyRepository struct {
+	q *db.Queries
+}
+
+func NewAPIKeyRepository(dbPool *pgxpool.Pool) *APIKeyRepository {
+	return &APIKeyRepository{
+		q: db.New(dbPool),
+	}
+}
+
+func (r *APIKeyRepository) Create(ctx context.Context, key *db.ApiKey) (*db.ApiKey, error) {
+	created, err := r.q.CreateApiKey(ctx, db.CreateApiKeyParams{
+		Prefix:       key.Prefix,
+		SecretHash:   key.SecretHash,
+		Name:         key.Name,
+		OwnerID:      key.OwnerID,
+		RestaurantID: key.RestaurantID,
+		Scopes:       key.Scopes,
+		IpAllowlist:  key.IpAllowlist,
+		ExpiresAt:    key.ExpiresAt,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &created, nil
+}
+
+func (r *APIKeyRepository) GetByPrefix(ctx context.Context, prefix string) (*db.ApiKey, error) {
+	key, err := r.q.GetApiKeyByPrefix(ctx, prefix)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, repository.ErrAPIKeyNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
+
+func (r *APIKeyRepository) ListByRestaurant(ctx context.Context, restaurantID pgtype.UUID) ([]db.ApiKey, error) {
+	return r.q.ListApiKeysByRestaurant(ctx, restaurantID)
+}
+
+func (r *APIKeyRepository) Revoke(ctx context.Context, id, restaurantID pgtype.UUID) error {
+	rows, err := r.q.RevokeApiKey(ctx, db.RevokeApiKeyParams{
+		ID:           id,
+		RestaurantID: restaurantID,
+	})
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return repository.ErrAPIKeyNotFound
+	}
+	return nil
+}
+
+func (r *APIKeyRepository) Touch(ctx context.Context, id pgtype.UUID, ip string) error {
+	return r.q.TouchApiKey(ctx, db.TouchApiKeyParams{
+		ID:         id,
+		LastUsedIp: pgtype.Text{String: ip, Valid: ip != ""},
+	})
+}
@@ -0,0 +1,28 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/goodfoodcesi/auth-api/infrastructure/database/sqlc"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type AuditRepository struct {
+	q *db.Queries
+}
+
+func NewAuditRepository(dbPool *pgxpool.Pool) *AuditRepository {
+	return &AuditRepository{
+		q: db.New(dbPool),
+	}
+}
+
+func (r *AuditRepository) Create(ctx context.Context, event *db.AuditEvent) error {
+	return r.q.CreateAuditEvent(ctx, db.CreateAuditEventParams{
+		Action:    event.Action,
+		ActorID:   event.ActorID,
+		SubjectID: event.SubjectID,
+		Detail:    event.Detail,
+		Ip:        event.Ip,
+	})
+}
@@ -0,0 +1,96 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/goodfoodcesi/auth-api/domain/repository"
+	"github.com/goodfoodcesi/auth-api/infrastructure/database/sqlc"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type DriverApplicationRepository struct {
+	dbPool *pgxpool.Pool
+	q      *db.Queries
+}
+
+func NewDriverApplicationRepository(dbPool *pgxpool.Pool) *DriverApplicationRepository {
+	return &DriverApplicationRepository{
+		dbPool: dbPool,
+		q:      db.New(dbPool),
+	}
+}
+
+func (r *DriverApplicationRepository) GetByID(ctx context.Context, id pgtype.UUID) (*db.DriverApplication, error) {
+	application, err := r.q.GetDriverApplicationByID(ctx, id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, repository.ErrDriverApplicationNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &application, nil
+}
+
+func (r *DriverApplicationRepository) GetByUserID(ctx context.Context, userID pgtype.UUID) (*db.DriverApplication, error) {
+	application, err := r.q.GetDriverApplicationByUserID(ctx, userID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, repository.ErrDriverApplicationNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &application, nil
+}
+
+func (r *DriverApplicationRepository) List(ctx context.Context, status db.NullDriverApplicationStatus) ([]db.DriverApplication, error) {
+	return r.q.ListDriverApplications(ctx, status)
+}
+
+func (r *DriverApplicationRepository) Submit(ctx context.Context, application *db.DriverApplication) (*db.DriverApplication, error) {
+	created, err := r.q.CreateDriverApplication(ctx, db.CreateDriverApplicationParams{
+		UserID:        application.UserID,
+		VehicleType:   application.VehicleType,
+		LicenseNumber: application.LicenseNumber,
+		DocumentUrl:   application.DocumentUrl,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &created, nil
+}
+
+func (r *DriverApplicationRepository) Resubmit(ctx context.Context, application *db.DriverApplication) (*db.DriverApplication, error) {
+	updated, err := r.q.ResubmitDriverApplication(ctx, db.ResubmitDriverApplicationParams{
+		UserID:        application.UserID,
+		VehicleType:   application.VehicleType,
+		LicenseNumber: application.LicenseNumber,
+		DocumentUrl:   application.DocumentUrl,
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, repository.ErrDriverApplicationConflict
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &updated, nil
+}
+
+func (r *DriverApplicationRepository) Review(ctx context.Context, application *db.DriverApplication, from db.DriverApplicationStatus) (*db.DriverApplication, error) {
+	updated, err := queries(ctx, r.q).ReviewDriverApplication(ctx, db.ReviewDriverApplicationParams{
+		Status:        application.Status,
+		ReviewerNotes: application.ReviewerNotes,
+		ReviewedBy:    application.ReviewedBy,
+		ID:            application.ID,
+		FromStatus:    from,
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, repository.ErrDriverApplicationConflict
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &updated, nil
+}
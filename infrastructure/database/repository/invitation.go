@@ -0,0 +1,124 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/goodfoodcesi/auth-api/domain/repository"
+	"github.com/goodfoodcesi/auth-api/infrastructure/database/sqlc"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type InvitationRepository struct {
+	dbPool *pgxpool.Pool
+	q      *db.Queries
+}
+
+func NewInvitationRepository(dbPool *pgxpool.Pool) *InvitationRepository {
+	return &InvitationRepository{
+		dbPool: dbPool,
+		q:      db.New(dbPool),
+	}
+}
+
+func (r *InvitationRepository) Create(ctx context.Context, invitation *db.Invitation) (*db.Invitation, error) {
+	created, err := r.q.CreateInvitation(ctx, db.CreateInvitationParams{
+		Email:        invitation.Email,
+		Role:         invitation.Role,
+		RestaurantID: invitation.RestaurantID,
+		TokenHash:    invitation.TokenHash,
+		InvitedBy:    invitation.InvitedBy,
+		ExpiresAt:    invitation.ExpiresAt,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &created, nil
+}
+
+func (r *InvitationRepository) GetByID(ctx context.Context, id pgtype.UUID) (*db.Invitation, error) {
+	invitation, err := r.q.GetInvitationByID(ctx, id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, repository.ErrInvitationNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &invitation, nil
+}
+
+func (r *InvitationRepository) GetByTokenHash(ctx context.Context, tokenHash string) (*db.Invitation, error) {
+	invitation, err := r.q.GetInvitationByTokenHash(ctx, tokenHash)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, repository.ErrInvitationNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &invitation, nil
+}
+
+func (r *InvitationRepository) List(ctx context.Context, invitedBy pgtype.UUID) ([]db.Invitation, error) {
+	return r.q.ListInvitations(ctx, invitedBy)
+}
+
+func (r *InvitationRepository) Revoke(ctx context.Context, id pgtype.UUID) error {
+	rows, err := r.q.RevokeInvitation(ctx, id)
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return repository.ErrInvitationUnusable
+	}
+	return nil
+}
+
+func (r *InvitationRepository) Redeem(ctx context.Context, invitation *db.Invitation, user *db.User) (*db.User, error) {
+	tx, err := begin(ctx, r.dbPool)
+	if err != nil {
+		return nil, err
+	}
+	//nolint:errcheck
+	defer tx.Rollback(ctx)
+
+	q := r.q.WithTx(tx)
+	dbUser, err := q.CreateUser(ctx, db.CreateUserParams{
+		Firstname:    user.Firstname,
+		Lastname:     user.Lastname,
+		Email:        user.Email,
+		PasswordHash: user.PasswordHash,
+		Role:         user.Role,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := q.AcceptInvitation(ctx, db.AcceptInvitationParams{
+		ID:         invitation.ID,
+		AcceptedBy: dbUser.ID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if rows == 0 {
+		return nil, repository.ErrInvitationUnusable
+	}
+
+	if invitation.RestaurantID.Valid {
+		if _, err := q.UpsertMembership(ctx, db.UpsertMembershipParams{
+			UserID:       dbUser.ID,
+			RestaurantID: invitation.RestaurantID,
+			Role:         db.MembershipRoleStaff,
+			CreatedBy:    invitation.InvitedBy,
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return mapDBUserToEntity(dbUser), nil
+}
@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/goodfoodcesi/auth-api/domain/repository"
+	"github.com/goodfoodcesi/auth-api/infrastructure/database/sqlc"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type MembershipRepository struct {
+	q *db.Queries
+}
+
+func NewMembershipRepository(dbPool *pgxpool.Pool) *MembershipRepository {
+	return &MembershipRepository{
+		q: db.New(dbPool),
+	}
+}
+
+func (r *MembershipRepository) Save(ctx context.Context, membership *db.Membership) (*db.Membership, error) {
+	saved, err := r.q.UpsertMembership(ctx, db.UpsertMembershipParams{
+		UserID:       membership.UserID,
+		RestaurantID: membership.RestaurantID,
+		Role:         membership.Role,
+		CreatedBy:    membership.CreatedBy,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &saved, nil
+}
+
+func (r *MembershipRepository) Get(ctx context.Context, userID, restaurantID pgtype.UUID) (*db.Membership, error) {
+	membership, err := r.q.GetMembership(ctx, db.GetMembershipParams{
+		UserID:       userID,
+		RestaurantID: restaurantID,
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, repository.ErrMembershipNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &membership, nil
+}
+
+func (r *MembershipRepository) ListByUser(ctx context.Context, userID pgtype.UUID) ([]db.Membership, error) {
+	return r.q.ListMembershipsByUser(ctx, userID)
+}
+
+func (r *MembershipRepository) ListByRestaurant(ctx context.Context, restaurantID pgtype.UUID) ([]db.Membership, error) {
+	return r.q.ListMembershipsByRestaurant(ctx, restaurantID)
+}
+
+func (r *MembershipRepository) Delete(ctx context.Context, userID, restaurantID pgtype.UUID) error {
+	rows, err := queries(ctx, r.q).DeleteMembership(ctx, db.DeleteMembershipParams{
+		UserID:       userID,
+		RestaurantID: restaurantID,
+	})
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return repository.ErrMembershipNotFound
+	}
+	return nil
+}
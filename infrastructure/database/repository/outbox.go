@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/goodfoodcesi/auth-api/domain/repository"
+	"github.com/goodfoodcesi/auth-api/infrastructure/database/sqlc"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type OutboxRepository struct {
+	q *db.Queries
+}
+
+func NewOutboxRepository(dbPool *pgxpool.Pool) *OutboxRepository {
+	return &OutboxRepository{
+		q: db.New(dbPool),
+	}
+}
+
+func (r *OutboxRepository) Add(ctx context.Context, message *db.Outbox) error {
+	return queries(ctx, r.q).CreateOutboxMessage(ctx, db.CreateOutboxMessageParams{
+		AggregateType: message.AggregateType,
+		AggregateID:   message.AggregateID,
+		EventType:     message.EventType,
+		Exchange:      message.Exchange,
+		RoutingKey:    message.RoutingKey,
+		Payload:       message.Payload,
+	})
+}
+
+func (r *OutboxRepository) Claim(ctx context.Context, limit int32, leaseUntil time.Time) ([]db.Outbox, error) {
+	return r.q.ClaimOutboxMessages(ctx, db.ClaimOutboxMessagesParams{
+		LeaseUntil: pgtype.Timestamptz{Time: leaseUntil, Valid: true},
+		BatchSize:  limit,
+	})
+}
+
+func (r *OutboxRepository) MarkSent(ctx context.Context, id pgtype.UUID) error {
+	return r.q.MarkOutboxMessageSent(ctx, id)
+}
+
+func (r *OutboxRepository) MarkFailed(ctx context.Context, id pgtype.UUID, cause string, retryAt time.Time) error {
+	return r.q.MarkOutboxMessageFailed(ctx, db.MarkOutboxMessageFailedParams{
+		ID:          id,
+		LastError:   pgtype.Text{String: cause, Valid: true},
+		AvailableAt: pgtype.Timestamptz{Time: retryAt, Valid: true},
+	})
+}
+
+func (r *OutboxRepository) Get(ctx context.Context, id pgtype.UUID) (*db.Outbox, error) {
+	message, err := r.q.GetOutboxMessage(ctx, id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, repository.ErrOutboxMessageNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &message, nil
+}
+
+func (r *OutboxRepository) List(ctx context.Context, status string, limit int32) ([]db.Outbox, error) {
+	return r.q.ListOutboxMessages(ctx, db.ListOutboxMessagesParams{
+		Status:  status,
+		MaxRows: limit,
+	})
+}
+
+func (r *OutboxRepository) Replay(ctx context.Context, id pgtype.UUID) error {
+	rows, err := r.q.ReplayOutboxMessage(ctx, id)
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return repository.ErrOutboxMessageNotFound
+	}
+	return nil
+}
+
+func (r *OutboxRepository) Lag(ctx context.Context) (int64, time.Time, error) {
+	pending, err := r.q.CountPendingOutboxMessages(ctx)
+	if err != nil {
+		return 0, time.Time{}, err
+	}
+	oldest, err := r.q.OldestPendingOutboxMessage(ctx)
+	if err != nil {
+		return 0, time.Time{}, err
+	}
+	return pending, oldest.Time, nil
+}
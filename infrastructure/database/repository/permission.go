@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/goodfoodcesi/auth-api/infrastructure/database/sqlc"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type PermissionRepository struct {
+	dbPool *pgxpool.Pool
+	q      *db.Queries
+}
+
+func NewPermissionRepository(dbPool *pgxpool.Pool) *PermissionRepository {
+	return &PermissionRepository{
+		dbPool: dbPool,
+		q:      db.New(dbPool),
+	}
+}
+
+func (r *PermissionRepository) List(ctx context.Context) ([]db.Permission, error) {
+	return r.q.ListPermissions(ctx)
+}
+
+func (r *PermissionRepository) ListGrants(ctx context.Context) ([]db.RolePermission, error) {
+	return r.q.ListRolePermissions(ctx)
+}
+
+func (r *PermissionRepository) ForRole(ctx context.Context, role db.UserRole) ([]string, error) {
+	return r.q.ListPermissionsForRole(ctx, role)
+}
+
+func (r *PermissionRepository) SetForRole(ctx context.Context, role db.UserRole, permissions []string) error {
+	tx, err := begin(ctx, r.dbPool)
+	if err != nil {
+		return err
+	}
+	//nolint:errcheck
+	defer tx.Rollback(ctx)
+
+	q := r.q.WithTx(tx)
+	if err := q.DeleteRolePermissions(ctx, role); err != nil {
+		return err
+	}
+	for _, permission := range permissions {
+		if err := q.AddRolePermission(ctx, db.AddRolePermissionParams{
+			Role:       role,
+			Permission: permission,
+		}); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit(ctx)
+}
@@ -0,0 +1,89 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/goodfoodcesi/auth-api/infrastructure/database/sqlc"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type PhoneOtpRepository struct {
+	dbPool *pgxpool.Pool
+	q      *db.Queries
+}
+
+func NewPhoneOtpRepository(dbPool *pgxpool.Pool) *PhoneOtpRepository {
+	return &PhoneOtpRepository{
+		dbPool: dbPool,
+		q:      db.New(dbPool),
+	}
+}
+
+func (r *PhoneOtpRepository) Create(ctx context.Context, otp *db.PhoneOtp) (*db.PhoneOtp, error) {
+	tx, err := begin(ctx, r.dbPool)
+	if err != nil {
+		return nil, err
+	}
+	//nolint:errcheck
+	defer tx.Rollback(ctx)
+
+	q := r.q.WithTx(tx)
+	if err := q.InvalidatePhoneOtps(ctx, otp.PhoneNumber); err != nil {
+		return nil, err
+	}
+
+	created, err := q.CreatePhoneOtp(ctx, db.CreatePhoneOtpParams{
+		PhoneNumber: otp.PhoneNumber,
+		CodeHash:    otp.CodeHash,
+		ExpiresAt:   otp.ExpiresAt,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return &created, nil
+}
+
+func (r *PhoneOtpRepository) CountSince(ctx context.Context, phoneNumber string, since time.Time) (int64, error) {
+	return r.q.CountPhoneOtpsSince(ctx, db.CountPhoneOtpsSinceParams{
+		PhoneNumber: phoneNumber,
+		CreatedAt:   pgtype.Timestamptz{Time: since, Valid: true},
+	})
+}
+
+func (r *PhoneOtpRepository) GetActive(ctx context.Context, phoneNumber string) (*db.PhoneOtp, error) {
+	otp, err := r.q.GetActivePhoneOtp(ctx, phoneNumber)
+	if err != nil {
+		return nil, err
+	}
+	return &otp, nil
+}
+
+func (r *PhoneOtpRepository) IncrementAttempts(ctx context.Context, id pgtype.UUID, maxAttempts int32) (int32, bool, error) {
+	attempts, err := r.q.IncrementPhoneOtpAttempts(ctx, db.IncrementPhoneOtpAttemptsParams{
+		ID:          id,
+		MaxAttempts: maxAttempts,
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, err
+	}
+	return attempts, true, nil
+}
+
+func (r *PhoneOtpRepository) Consume(ctx context.Context, id pgtype.UUID) (bool, error) {
+	rows, err := r.q.ConsumePhoneOtp(ctx, id)
+	if err != nil {
+		return false, err
+	}
+	return rows == 1, nil
+}
@@ -0,0 +1,35 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/goodfoodcesi/auth-api/infrastructure/database/sqlc"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type ProcessedMessageRepository struct {
+	q *db.Queries
+}
+
+func NewProcessedMessageRepository(dbPool *pgxpool.Pool) *ProcessedMessageRepository {
+	return &ProcessedMessageRepository{
+		q: db.New(dbPool),
+	}
+}
+
+func (r *ProcessedMessageRepository) MarkProcessed(ctx context.Context, consumer, messageID string) (bool, error) {
+	inserted, err := queries(ctx, r.q).MarkMessageProcessed(ctx, db.MarkMessageProcessedParams{
+		MessageID: messageID,
+		Consumer:  consumer,
+	})
+	if err != nil {
+		return false, err
+	}
+	return inserted == 1, nil
+}
+
+func (r *ProcessedMessageRepository) DeleteBefore(ctx context.Context, before time.Time) (int64, error) {
+	return r.q.DeleteProcessedMessagesBefore(ctx, pgtype.Timestamptz{Time: before, Valid: true})
+}
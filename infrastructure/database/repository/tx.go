@@ -0,0 +1,55 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/goodfoodcesi/auth-api/infrastructure/database/sqlc"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type txKey struct{}
+
+// Transactor lets services group calls to several repositories in one
+// transaction, carried by the context given to the repositories.
+type Transactor struct {
+	dbPool *pgxpool.Pool
+}
+
+func NewTransactor(dbPool *pgxpool.Pool) *Transactor {
+	return &Transactor{
+		dbPool: dbPool,
+	}
+}
+
+// WithinTx commits when fn succeeds. Repositories called with the context
+// fn receives join the transaction.
+func (t *Transactor) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	tx, err := begin(ctx, t.dbPool)
+	if err != nil {
+		return err
+	}
+	//nolint:errcheck
+	defer tx.Rollback(ctx)
+
+	if err := fn(context.WithValue(ctx, txKey{}, tx)); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+// begin starts a transaction, or a savepoint when ctx already carries one.
+func begin(ctx context.Context, dbPool *pgxpool.Pool) (pgx.Tx, error) {
+	if tx, ok := ctx.Value(txKey{}).(pgx.Tx); ok {
+		return tx.Begin(ctx)
+	}
+	return dbPool.Begin(ctx)
+}
+
+// queries binds q to the transaction ctx carries, if any.
+func queries(ctx context.Context, q *db.Queries) *db.Queries {
+	if tx, ok := ctx.Value(txKey{}).(pgx.Tx); ok {
+		return q.WithTx(tx)
+	}
+	return q
+}
@@ -2,8 +2,11 @@ package repository
 
 import (
 	"context"
+	"errors"
 
+	"github.com/goodfoodcesi/auth-api/domain/repository"
 	"github.com/goodfoodcesi/auth-api/infrastructure/database/sqlc"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
@@ -21,7 +24,7 @@ func NewUserRepository(dbPool *pgxpool.Pool) *UserRepository {
 }
 
 func (r *UserRepository) Create(ctx context.Context, user *db.User) (*db.User, error) {
-	dbUser, err := r.q.CreateUser(ctx, db.CreateUserParams{
+	dbUser, err := queries(ctx, r.q).CreateUser(ctx, db.CreateUserParams{
 		Firstname:    user.Firstname,
 		Lastname:     user.Lastname,
 		Email:        user.Email,
@@ -38,6 +41,9 @@ func (r *UserRepository) Create(ctx context.Context, user *db.User) (*db.User, e
 
 func (r *UserRepository) GetByEmail(ctx context.Context, email string) (*db.User, error) {
 	dbUser, err := r.q.GetUserByEmail(ctx, email)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, repository.ErrUserNotFound
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -46,33 +52,140 @@ func (r *UserRepository) GetByEmail(ctx context.Context, email string) (*db.User
 
 func (r *UserRepository) GetByID(ctx context.Context, id pgtype.UUID) (*db.User, error) {
 	dbUser, err := r.q.GetUserByID(ctx, id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, repository.ErrUserNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return mapDBUserToEntity(dbUser), nil
+}
+
+func (r *UserRepository) GetByPhone(ctx context.Context, phoneNumber string) (*db.User, error) {
+	dbUser, err := r.q.GetUserByPhone(ctx, pgtype.Text{String: phoneNumber, Valid: true})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, repository.ErrUserNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return mapDBUserToEntity(dbUser), nil
+}
+
+func (r *UserRepository) MarkPhoneVerified(ctx context.Context, id pgtype.UUID) error {
+	return r.q.MarkPhoneVerified(ctx, id)
+}
+
+func (r *UserRepository) Update(ctx context.Context, user *db.User) (*db.User, error) {
+	dbUser, err := queries(ctx, r.q).UpdateUser(ctx, db.UpdateUserParams{
+		ID:          user.ID,
+		Firstname:   user.Firstname,
+		Lastname:    user.Lastname,
+		Version:     user.Version,
+		PhoneNumber: user.PhoneNumber,
+		Locale:      user.Locale,
+		Timezone:    user.Timezone,
+		AvatarUrl:   user.AvatarUrl,
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, repository.ErrVersionConflict
+	}
+	if err != nil {
+		return nil, err
+	}
+	return mapDBUserToEntity(dbUser), nil
+}
+
+func (r *UserRepository) List(ctx context.Context, filter repository.UserFilter) ([]db.User, error) {
+	params := db.ListUsersParams{
+		Search:    pgtype.Text{String: filter.Search, Valid: filter.Search != ""},
+		Sort:      string(filter.Sort),
+		PageLimit: filter.Limit,
+	}
+	if filter.Role != nil {
+		params.Role = db.NullUserRole{UserRole: *filter.Role, Valid: true}
+	}
+	if filter.Status != nil {
+		params.Status = db.NullUserStatus{UserStatus: *filter.Status, Valid: true}
+	}
+	if filter.CreatedAfter != nil {
+		params.CreatedAfter = pgtype.Timestamptz{Time: *filter.CreatedAfter, Valid: true}
+	}
+	if filter.CreatedBefore != nil {
+		params.CreatedBefore = pgtype.Timestamptz{Time: *filter.CreatedBefore, Valid: true}
+	}
+	if filter.After != nil {
+		params.CursorID = filter.After.ID
+		params.CursorCreatedAt = pgtype.Timestamptz{Time: filter.After.CreatedAt, Valid: true}
+		params.CursorEmail = pgtype.Text{String: filter.After.Email, Valid: true}
+	}
+
+	return r.q.ListUsers(ctx, params)
+}
+
+func (r *UserRepository) UpdateRole(ctx context.Context, user *db.User) (*db.User, error) {
+	dbUser, err := queries(ctx, r.q).UpdateUserRole(ctx, db.UpdateUserRoleParams{
+		ID:      user.ID,
+		Role:    user.Role,
+		Version: user.Version,
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, repository.ErrVersionConflict
+	}
 	if err != nil {
 		return nil, err
 	}
 	return mapDBUserToEntity(dbUser), nil
 }
 
-func (r *UserRepository) Update(ctx context.Context, user *db.User) error {
-	err := r.q.UpdateUser(ctx, db.UpdateUserParams{
-		ID:        user.ID,
-		Firstname: user.Firstname,
-		Lastname:  user.Lastname,
+func (r *UserRepository) UpdatePassword(ctx context.Context, id pgtype.UUID, passwordHash string) error {
+	return queries(ctx, r.q).UpdateUserPassword(ctx, db.UpdateUserPasswordParams{
+		ID:           id,
+		PasswordHash: passwordHash,
 	})
+}
+
+func (r *UserRepository) RevokeSessions(ctx context.Context, id pgtype.UUID) error {
+	return queries(ctx, r.q).RevokeUserSessions(ctx, id)
+}
+
+func (r *UserRepository) UpdateStatus(ctx context.Context, user *db.User, from db.UserStatus) (*db.User, error) {
+	dbUser, err := queries(ctx, r.q).UpdateUserStatus(ctx, db.UpdateUserStatusParams{
+		Status:          user.Status,
+		StatusReason:    user.StatusReason,
+		StatusChangedBy: user.StatusChangedBy,
+		ID:              user.ID,
+		FromStatus:      from,
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, repository.ErrStatusConflict
+	}
 	if err != nil {
-		return err
+		return nil, err
 	}
-	return nil
+	return mapDBUserToEntity(dbUser), nil
 }
 
 func mapDBUserToEntity(dbUser db.User) *db.User {
 	return &db.User{
-		ID:           dbUser.ID,
-		Firstname:    dbUser.Firstname,
-		Lastname:     dbUser.Lastname,
-		Email:        dbUser.Email,
-		PasswordHash: dbUser.PasswordHash,
-		Role:         dbUser.Role,
-		CreatedAt:    dbUser.CreatedAt,
-		UpdatedAt:    dbUser.UpdatedAt,
+		ID:                dbUser.ID,
+		Firstname:         dbUser.Firstname,
+		Lastname:          dbUser.Lastname,
+		Email:             dbUser.Email,
+		PasswordHash:      dbUser.PasswordHash,
+		Role:              dbUser.Role,
+		CreatedAt:         dbUser.CreatedAt,
+		UpdatedAt:         dbUser.UpdatedAt,
+		Version:           dbUser.Version,
+		PhoneNumber:       dbUser.PhoneNumber,
+		PhoneVerifiedAt:   dbUser.PhoneVerifiedAt,
+		Locale:            dbUser.Locale,
+		Timezone:          dbUser.Timezone,
+		AvatarUrl:         dbUser.AvatarUrl,
+		Status:            dbUser.Status,
+		StatusReason:      dbUser.StatusReason,
+		StatusChangedBy:   dbUser.StatusChangedBy,
+		StatusChangedAt:   dbUser.StatusChangedAt,
+		SessionsRevokedAt: dbUser.SessionsRevokedAt,
 	}
 }
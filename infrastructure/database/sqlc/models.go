@@ -11,6 +11,93 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+type DriverApplicationStatus string
+
+const (
+	DriverApplicationStatusSubmitted     DriverApplicationStatus = "submitted"
+	DriverApplicationStatusUnderReview   DriverApplicationStatus = "under_review"
+	DriverApplicationStatusApproved      DriverApplicationStatus = "approved"
+	DriverApplicationStatusRejected      DriverApplicationStatus = "rejected"
+	DriverApplicationStatusContractEnded DriverApplicationStatus = "contract_ended"
+)
+
+func (e *DriverApplicationStatus) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = DriverApplicationStatus(s)
+	case string:
+		*e = DriverApplicationStatus(s)
+	default:
+		return fmt.Errorf("unsupported scan type for DriverApplicationStatus: %T", src)
+	}
+	return nil
+}
+
+type NullDriverApplicationStatus struct {
+	DriverApplicationStatus DriverApplicationStatus `json:"driver_application_status"`
+	Valid                   bool                    `json:"valid"` // Valid is true if DriverApplicationStatus is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullDriverApplicationStatus) Scan(value interface{}) error {
+	if value == nil {
+		ns.DriverApplicationStatus, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.DriverApplicationStatus.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullDriverApplicationStatus) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.DriverApplicationStatus), nil
+}
+
+type MembershipRole string
+
+const (
+	MembershipRoleOwner MembershipRole = "owner"
+	MembershipRoleStaff MembershipRole = "staff"
+)
+
+func (e *MembershipRole) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = MembershipRole(s)
+	case string:
+		*e = MembershipRole(s)
+	default:
+		return fmt.Errorf("unsupported scan type for MembershipRole: %T", src)
+	}
+	return nil
+}
+
+type NullMembershipRole struct {
+	MembershipRole MembershipRole `json:"membership_role"`
+	Valid          bool           `json:"valid"` // Valid is true if MembershipRole is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullMembershipRole) Scan(value interface{}) error {
+	if value == nil {
+		ns.MembershipRole, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.MembershipRole.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullMembershipRole) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.MembershipRole), nil
+}
+
 type UserRole string
 
 const (
@@ -55,13 +142,189 @@ func (ns NullUserRole) Value() (driver.Value, error) {
 	return string(ns.UserRole), nil
 }
 
-type User struct {
+type UserStatus string
+
+const (
+	UserStatusPending   UserStatus = "pending"
+	UserStatusActive    UserStatus = "active"
+	UserStatusSuspended UserStatus = "suspended"
+	UserStatusBanned    UserStatus = "banned"
+	UserStatusDeleted   UserStatus = "deleted"
+)
+
+func (e *UserStatus) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = UserStatus(s)
+	case string:
+		*e = UserStatus(s)
+	default:
+		return fmt.Errorf("unsupported scan type for UserStatus: %T", src)
+	}
+	return nil
+}
+
+type NullUserStatus struct {
+	UserStatus UserStatus `json:"user_status"`
+	Valid      bool       `json:"valid"` // Valid is true if UserStatus is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullUserStatus) Scan(value interface{}) error {
+	if value == nil {
+		ns.UserStatus, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.UserStatus.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullUserStatus) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.UserStatus), nil
+}
+
+type ApiKey struct {
+	ID           pgtype.UUID        `json:"id"`
+	Prefix       string             `json:"prefix"`
+	SecretHash   string             `json:"secret_hash"`
+	Name         string             `json:"name"`
+	OwnerID      pgtype.UUID        `json:"owner_id"`
+	RestaurantID pgtype.UUID        `json:"restaurant_id"`
+	Scopes       []string           `json:"scopes"`
+	IpAllowlist  []string           `json:"ip_allowlist"`
+	ExpiresAt    pgtype.Timestamptz `json:"expires_at"`
+	LastUsedAt   pgtype.Timestamptz `json:"last_used_at"`
+	LastUsedIp   pgtype.Text        `json:"last_used_ip"`
+	RevokedAt    pgtype.Timestamptz `json:"revoked_at"`
+	CreatedAt    pgtype.Timestamptz `json:"created_at"`
+}
+
+type AuditEvent struct {
+	ID        pgtype.UUID        `json:"id"`
+	Action    string             `json:"action"`
+	ActorID   pgtype.UUID        `json:"actor_id"`
+	SubjectID pgtype.UUID        `json:"subject_id"`
+	Detail    pgtype.Text        `json:"detail"`
+	Ip        pgtype.Text        `json:"ip"`
+	CreatedAt pgtype.Timestamptz `json:"created_at"`
+}
+
+type DriverApplication struct {
+	ID            pgtype.UUID             `json:"id"`
+	UserID        pgtype.UUID             `json:"user_id"`
+	Status        DriverApplicationStatus `json:"status"`
+	VehicleType   string                  `json:"vehicle_type"`
+	LicenseNumber string                  `json:"license_number"`
+	DocumentUrl   string                  `json:"document_url"`
+	ReviewerNotes pgtype.Text             `json:"reviewer_notes"`
+	ReviewedBy    pgtype.UUID             `json:"reviewed_by"`
+	ReviewedAt    pgtype.Timestamptz      `json:"reviewed_at"`
+	SubmittedAt   pgtype.Timestamptz      `json:"submitted_at"`
+	UpdatedAt     pgtype.Timestamptz      `json:"updated_at"`
+}
+
+type Invitation struct {
 	ID           pgtype.UUID        `json:"id"`
-	Firstname    string             `json:"firstname"`
-	Lastname     string             `json:"lastname"`
 	Email        string             `json:"email"`
-	PasswordHash string             `json:"password_hash"`
 	Role         UserRole           `json:"role"`
+	RestaurantID pgtype.UUID        `json:"restaurant_id"`
+	TokenHash    string             `json:"token_hash"`
+	InvitedBy    pgtype.UUID        `json:"invited_by"`
+	ExpiresAt    pgtype.Timestamptz `json:"expires_at"`
+	AcceptedAt   pgtype.Timestamptz `json:"accepted_at"`
+	AcceptedBy   pgtype.UUID        `json:"accepted_by"`
+	RevokedAt    pgtype.Timestamptz `json:"revoked_at"`
 	CreatedAt    pgtype.Timestamptz `json:"created_at"`
-	UpdatedAt    pgtype.Timestamptz `json:"updated_at"`
+}
+
+type Membership struct {
+	ID           pgtype.UUID        `json:"id"`
+	UserID       pgtype.UUID        `json:"user_id"`
+	RestaurantID pgtype.UUID        `json:"restaurant_id"`
+	Role         MembershipRole     `json:"role"`
+	CreatedBy    pgtype.UUID        `json:"created_by"`
+	CreatedAt    pgtype.Timestamptz `json:"created_at"`
+}
+
+type Outbox struct {
+	ID            pgtype.UUID        `json:"id"`
+	AggregateType string             `json:"aggregate_type"`
+	AggregateID   string             `json:"aggregate_id"`
+	EventType     string             `json:"event_type"`
+	Exchange      string             `json:"exchange"`
+	RoutingKey    string             `json:"routing_key"`
+	Payload       []byte             `json:"payload"`
+	Attempts      int32              `json:"attempts"`
+	LastError     pgtype.Text        `json:"last_error"`
+	AvailableAt   pgtype.Timestamptz `json:"available_at"`
+	CreatedAt     pgtype.Timestamptz `json:"created_at"`
+	SentAt        pgtype.Timestamptz `json:"sent_at"`
+}
+
+type Permission struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+type PhoneOtp struct {
+	ID          pgtype.UUID        `json:"id"`
+	PhoneNumber string             `json:"phone_number"`
+	CodeHash    string             `json:"code_hash"`
+	Attempts    int32              `json:"attempts"`
+	ExpiresAt   pgtype.Timestamptz `json:"expires_at"`
+	ConsumedAt  pgtype.Timestamptz `json:"consumed_at"`
+	CreatedAt   pgtype.Timestamptz `json:"created_at"`
+}
+
+type ProcessedMessage struct {
+	MessageID   string             `json:"message_id"`
+	Consumer    string             `json:"consumer"`
+	ProcessedAt pgtype.Timestamptz `json:"processed_at"`
+}
+
+type RolePermission struct {
+	Role       UserRole `json:"role"`
+	Permission string   `json:"permission"`
+}
+
+type User struct {
+	ID                pgtype.UUID        `json:"id"`
+	Firstname         string             `json:"firstname"`
+	Lastname          string             `json:"lastname"`
+	Email             string             `json:"email"`
+	PasswordHash      string             `json:"password_hash"`
+	Role              UserRole           `json:"role"`
+	CreatedAt         pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt         pgtype.Timestamptz `json:"updated_at"`
+	Version           int32              `json:"version"`
+	PhoneNumber       pgtype.Text        `json:"phone_number"`
+	PhoneVerifiedAt   pgtype.Timestamptz `json:"phone_verified_at"`
+	Locale            string             `json:"locale"`
+	Timezone          string             `json:"timezone"`
+	AvatarUrl         pgtype.Text        `json:"avatar_url"`
+	Status            UserStatus         `json:"status"`
+	StatusReason      pgtype.Text        `json:"status_reason"`
+	StatusChangedBy   pgtype.UUID        `json:"status_changed_by"`
+	StatusChangedAt   pgtype.Timestamptz `json:"status_changed_at"`
+	SessionsRevokedAt pgtype.Timestamptz `json:"sessions_revoked_at"`
+}
+
+type UserAddress struct {
+	ID          pgtype.UUID        `json:"id"`
+	UserID      pgtype.UUID        `json:"user_id"`
+	Label       string             `json:"label"`
+	Line1       string             `json:"line1"`
+	Line2       pgtype.Text        `json:"line2"`
+	Postcode    string             `json:"postcode"`
+	City        string             `json:"city"`
+	CountryCode string             `json:"country_code"`
+	Latitude    pgtype.Float8      `json:"latitude"`
+	Longitude   pgtype.Float8      `json:"longitude"`
+	IsDefault   bool               `json:"is_default"`
+	CreatedAt   pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt   pgtype.Timestamptz `json:"updated_at"`
 }
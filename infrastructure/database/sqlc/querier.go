@@ -11,11 +11,85 @@ import (
 )
 
 type Querier interface {
+	// an invitation can be redeemed once, before it expires and unless it was revoked
+	AcceptInvitation(ctx context.Context, arg AcceptInvitationParams) (int64, error)
+	AddRolePermission(ctx context.Context, arg AddRolePermissionParams) error
+	// Leases due messages until lease_until so concurrent relays skip them.
+	ClaimOutboxMessages(ctx context.Context, arg ClaimOutboxMessagesParams) ([]Outbox, error)
+	// the default flag is unique per user, so it is cleared before another address takes it
+	ClearDefaultAddress(ctx context.Context, userID pgtype.UUID) error
+	ConsumePhoneOtp(ctx context.Context, id pgtype.UUID) (int64, error)
+	CountAddressesByUser(ctx context.Context, userID pgtype.UUID) (int64, error)
+	CountPendingOutboxMessages(ctx context.Context) (int64, error)
+	CountPhoneOtpsSince(ctx context.Context, arg CountPhoneOtpsSinceParams) (int64, error)
+	CreateAddress(ctx context.Context, arg CreateAddressParams) (UserAddress, error)
+	CreateApiKey(ctx context.Context, arg CreateApiKeyParams) (ApiKey, error)
+	CreateAuditEvent(ctx context.Context, arg CreateAuditEventParams) error
+	CreateDriverApplication(ctx context.Context, arg CreateDriverApplicationParams) (DriverApplication, error)
+	CreateInvitation(ctx context.Context, arg CreateInvitationParams) (Invitation, error)
+	CreateOutboxMessage(ctx context.Context, arg CreateOutboxMessageParams) error
+	CreatePhoneOtp(ctx context.Context, arg CreatePhoneOtpParams) (PhoneOtp, error)
 	CreateUser(ctx context.Context, arg CreateUserParams) (User, error)
+	DeleteAddress(ctx context.Context, arg DeleteAddressParams) (int64, error)
+	DeleteMembership(ctx context.Context, arg DeleteMembershipParams) (int64, error)
+	DeleteProcessedMessagesBefore(ctx context.Context, processedAt pgtype.Timestamptz) (int64, error)
+	DeleteRolePermissions(ctx context.Context, role UserRole) error
+	GetActivePhoneOtp(ctx context.Context, phoneNumber string) (PhoneOtp, error)
+	GetAddress(ctx context.Context, arg GetAddressParams) (UserAddress, error)
+	GetApiKeyByPrefix(ctx context.Context, prefix string) (ApiKey, error)
+	GetDriverApplicationByID(ctx context.Context, id pgtype.UUID) (DriverApplication, error)
+	GetDriverApplicationByUserID(ctx context.Context, userID pgtype.UUID) (DriverApplication, error)
+	GetInvitationByID(ctx context.Context, id pgtype.UUID) (Invitation, error)
+	GetInvitationByTokenHash(ctx context.Context, tokenHash string) (Invitation, error)
+	GetMembership(ctx context.Context, arg GetMembershipParams) (Membership, error)
+	GetOutboxMessage(ctx context.Context, id pgtype.UUID) (Outbox, error)
 	GetUserByEmail(ctx context.Context, email string) (User, error)
 	GetUserByID(ctx context.Context, id pgtype.UUID) (User, error)
-	// only update firstname, lastname, updated_at
-	UpdateUser(ctx context.Context, arg UpdateUserParams) error
+	GetUserByPhone(ctx context.Context, phoneNumber pgtype.Text) (User, error)
+	// counts the attempt only while some are left, so parallel guesses cannot exceed max_attempts
+	IncrementPhoneOtpAttempts(ctx context.Context, arg IncrementPhoneOtpAttemptsParams) (int32, error)
+	// only the latest code sent to a number stays usable
+	InvalidatePhoneOtps(ctx context.Context, phoneNumber string) error
+	ListAddressesByUser(ctx context.Context, userID pgtype.UUID) ([]UserAddress, error)
+	ListApiKeysByRestaurant(ctx context.Context, restaurantID pgtype.UUID) ([]ApiKey, error)
+	// without a status this is the review queue: pending applications, oldest first
+	ListDriverApplications(ctx context.Context, status NullDriverApplicationStatus) ([]DriverApplication, error)
+	// admins list every invitation, other inviters only their own
+	ListInvitations(ctx context.Context, invitedBy pgtype.UUID) ([]Invitation, error)
+	ListMembershipsByRestaurant(ctx context.Context, restaurantID pgtype.UUID) ([]Membership, error)
+	ListMembershipsByUser(ctx context.Context, userID pgtype.UUID) ([]Membership, error)
+	ListOutboxMessages(ctx context.Context, arg ListOutboxMessagesParams) ([]Outbox, error)
+	ListPermissions(ctx context.Context) ([]Permission, error)
+	ListPermissionsForRole(ctx context.Context, role UserRole) ([]string, error)
+	ListRolePermissions(ctx context.Context) ([]RolePermission, error)
+	// keyset pagination: the cursor holds the sort key and id of the last row of the previous page
+	ListUsers(ctx context.Context, arg ListUsersParams) ([]User, error)
+	// affects no row when the consumer already processed the message
+	MarkMessageProcessed(ctx context.Context, arg MarkMessageProcessedParams) (int64, error)
+	MarkOutboxMessageFailed(ctx context.Context, arg MarkOutboxMessageFailedParams) error
+	MarkOutboxMessageSent(ctx context.Context, id pgtype.UUID) error
+	// proving possession of the phone through an OTP verifies it
+	MarkPhoneVerified(ctx context.Context, id pgtype.UUID) error
+	OldestPendingOutboxMessage(ctx context.Context) (pgtype.Timestamptz, error)
+	ReplayOutboxMessage(ctx context.Context, id pgtype.UUID) (int64, error)
+	// only a rejected application goes back to the queue, the previous review notes are kept for the next reviewer
+	ResubmitDriverApplication(ctx context.Context, arg ResubmitDriverApplicationParams) (DriverApplication, error)
+	// compare-and-set on the current status so two reviewers cannot decide the same application
+	ReviewDriverApplication(ctx context.Context, arg ReviewDriverApplicationParams) (DriverApplication, error)
+	RevokeApiKey(ctx context.Context, arg RevokeApiKeyParams) (int64, error)
+	RevokeInvitation(ctx context.Context, id pgtype.UUID) (int64, error)
+	// tokens issued before sessions_revoked_at are no longer accepted
+	RevokeUserSessions(ctx context.Context, id pgtype.UUID) error
+	TouchApiKey(ctx context.Context, arg TouchApiKeyParams) error
+	UpdateAddress(ctx context.Context, arg UpdateAddressParams) (UserAddress, error)
+	// only update profile fields when the caller holds the current version, a new phone number loses its verification
+	UpdateUser(ctx context.Context, arg UpdateUserParams) (User, error)
+	UpdateUserPassword(ctx context.Context, arg UpdateUserPasswordParams) error
+	UpdateUserRole(ctx context.Context, arg UpdateUserRoleParams) (User, error)
+	// compare-and-set on the current status so two admins cannot apply conflicting transitions
+	UpdateUserStatus(ctx context.Context, arg UpdateUserStatusParams) (User, error)
+	// adding an existing member only changes their role
+	UpsertMembership(ctx context.Context, arg UpsertMembershipParams) (Membership, error)
 }
 
 var _ Querier = (*Queries)(nil)
@@ -16,7 +16,7 @@ INSERT INTO users (
      firstname, lastname, email, password_hash, role
 ) VALUES (
              $1, $2, $3, $4, $5
-         ) RETURNING id, firstname, lastname, email, password_hash, role, created_at, updated_at
+         ) RETURNING id, firstname, lastname, email, password_hash, role, created_at, updated_at, version, phone_number, phone_verified_at, locale, timezone, avatar_url, status, status_reason, status_changed_by, status_changed_at, sessions_revoked_at
 `
 
 type CreateUserParams struct {
@@ -45,12 +45,23 @@ func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, e
 		&i.Role,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Version,
+		&i.PhoneNumber,
+		&i.PhoneVerifiedAt,
+		&i.Locale,
+		&i.Timezone,
+		&i.AvatarUrl,
+		&i.Status,
+		&i.StatusReason,
+		&i.StatusChangedBy,
+		&i.StatusChangedAt,
+		&i.SessionsRevokedAt,
 	)
 	return i, err
 }
 
 const getUserByEmail = `-- name: GetUserByEmail :one
-SELECT id, firstname, lastname, email, password_hash, role, created_at, updated_at FROM users WHERE email = $1
+SELECT id, firstname, lastname, email, password_hash, role, created_at, updated_at, version, phone_number, phone_verified_at, locale, timezone, avatar_url, status, status_reason, status_changed_by, status_changed_at, sessions_revoked_at FROM users WHERE email = $1
 `
 
 func (q *Queries) GetUserByEmail(ctx context.Context, email string) (User, error) {
@@ -65,12 +76,23 @@ func (q *Queries) GetUserByEmail(ctx context.Context, email string) (User, error
 		&i.Role,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Version,
+		&i.PhoneNumber,
+		&i.PhoneVerifiedAt,
+		&i.Locale,
+		&i.Timezone,
+		&i.AvatarUrl,
+		&i.Status,
+		&i.StatusReason,
+		&i.StatusChangedBy,
+		&i.StatusChangedAt,
+		&i.SessionsRevokedAt,
 	)
 	return i, err
 }
 
 const getUserByID = `-- name: GetUserByID :one
-SELECT id, firstname, lastname, email, password_hash, role, created_at, updated_at FROM users WHERE id = $1
+SELECT id, firstname, lastname, email, password_hash, role, created_at, updated_at, version, phone_number, phone_verified_at, locale, timezone, avatar_url, status, status_reason, status_changed_by, status_changed_at, sessions_revoked_at FROM users WHERE id = $1
 `
 
 func (q *Queries) GetUserByID(ctx context.Context, id pgtype.UUID) (User, error) {
@@ -85,22 +107,316 @@ func (q *Queries) GetUserByID(ctx context.Context, id pgtype.UUID) (User, error)
 		&i.Role,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Version,
+		&i.PhoneNumber,
+		&i.PhoneVerifiedAt,
+		&i.Locale,
+		&i.Timezone,
+		&i.AvatarUrl,
+		&i.Status,
+		&i.StatusReason,
+		&i.StatusChangedBy,
+		&i.StatusChangedAt,
+		&i.SessionsRevokedAt,
 	)
 	return i, err
 }
 
-const updateUser = `-- name: UpdateUser :exec
-UPDATE users SET firstname = $2, lastname = $3, updated_at = now() WHERE id = $1
+const getUserByPhone = `-- name: GetUserByPhone :one
+SELECT id, firstname, lastname, email, password_hash, role, created_at, updated_at, version, phone_number, phone_verified_at, locale, timezone, avatar_url, status, status_reason, status_changed_by, status_changed_at, sessions_revoked_at FROM users WHERE phone_number = $1
+`
+
+func (q *Queries) GetUserByPhone(ctx context.Context, phoneNumber pgtype.Text) (User, error) {
+	row := q.db.QueryRow(ctx, getUserByPhone, phoneNumber)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Firstname,
+		&i.Lastname,
+		&i.Email,
+		&i.PasswordHash,
+		&i.Role,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Version,
+		&i.PhoneNumber,
+		&i.PhoneVerifiedAt,
+		&i.Locale,
+		&i.Timezone,
+		&i.AvatarUrl,
+		&i.Status,
+		&i.StatusReason,
+		&i.StatusChangedBy,
+		&i.StatusChangedAt,
+		&i.SessionsRevokedAt,
+	)
+	return i, err
+}
+
+const updateUser = `-- name: UpdateUser :one
+UPDATE users
+SET firstname = $2, lastname = $3, phone_number = $5,
+    phone_verified_at = CASE WHEN phone_number IS NOT DISTINCT FROM $5 THEN phone_verified_at END,
+    locale = $6, timezone = $7, avatar_url = $8, version = version + 1, updated_at = now()
+WHERE id = $1 AND version = $4
+RETURNING id, firstname, lastname, email, password_hash, role, created_at, updated_at, version, phone_number, phone_verified_at, locale, timezone, avatar_url, status, status_reason, status_changed_by, status_changed_at, sessions_revoked_at
 `
 
 type UpdateUserParams struct {
-	ID        pgtype.UUID `json:"id"`
-	Firstname string      `json:"firstname"`
-	Lastname  string      `json:"lastname"`
+	ID          pgtype.UUID `json:"id"`
+	Firstname   string      `json:"firstname"`
+	Lastname    string      `json:"lastname"`
+	Version     int32       `json:"version"`
+	PhoneNumber pgtype.Text `json:"phone_number"`
+	Locale      string      `json:"locale"`
+	Timezone    string      `json:"timezone"`
+	AvatarUrl   pgtype.Text `json:"avatar_url"`
+}
+
+// only update profile fields when the caller holds the current version, a new phone number loses its verification
+func (q *Queries) UpdateUser(ctx context.Context, arg UpdateUserParams) (User, error) {
+	row := q.db.QueryRow(ctx, updateUser,
+		arg.ID,
+		arg.Firstname,
+		arg.Lastname,
+		arg.Version,
+		arg.PhoneNumber,
+		arg.Locale,
+		arg.Timezone,
+		arg.AvatarUrl,
+	)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Firstname,
+		&i.Lastname,
+		&i.Email,
+		&i.PasswordHash,
+		&i.Role,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Version,
+		&i.PhoneNumber,
+		&i.PhoneVerifiedAt,
+		&i.Locale,
+		&i.Timezone,
+		&i.AvatarUrl,
+		&i.Status,
+		&i.StatusReason,
+		&i.StatusChangedBy,
+		&i.StatusChangedAt,
+		&i.SessionsRevokedAt,
+	)
+	return i, err
+}
+
+const markPhoneVerified = `-- name: MarkPhoneVerified :exec
+UPDATE users SET phone_verified_at = now(), version = version + 1, updated_at = now() WHERE id = $1 AND phone_verified_at IS NULL
+`
+
+// proving possession of the phone through an OTP verifies it
+func (q *Queries) MarkPhoneVerified(ctx context.Context, id pgtype.UUID) error {
+	_, err := q.db.Exec(ctx, markPhoneVerified, id)
+	return err
+}
+
+const listUsers = `-- name: ListUsers :many
+SELECT id, firstname, lastname, email, password_hash, role, created_at, updated_at, version, phone_number, phone_verified_at, locale, timezone, avatar_url, status, status_reason, status_changed_by, status_changed_at, sessions_revoked_at FROM users
+WHERE ($1::user_role IS NULL OR role = $1::user_role)
+  AND ($2::user_status IS NULL OR status = $2::user_status)
+  AND ($3::timestamptz IS NULL OR created_at >= $3::timestamptz)
+  AND ($4::timestamptz IS NULL OR created_at < $4::timestamptz)
+  AND ($5::text IS NULL
+       OR email ILIKE '%' || $5::text || '%'
+       OR (firstname || ' ' || lastname) ILIKE '%' || $5::text || '%')
+  AND ($6::uuid IS NULL OR CASE $7::text
+        WHEN 'created_at_asc' THEN (created_at, id) > ($8::timestamptz, $6::uuid)
+        WHEN 'email_asc' THEN (email, id) > ($9::text, $6::uuid)
+        WHEN 'email_desc' THEN (email, id) < ($9::text, $6::uuid)
+        ELSE (created_at, id) < ($8::timestamptz, $6::uuid)
+      END)
+ORDER BY
+    CASE WHEN $7::text = 'created_at_asc' THEN created_at END ASC,
+    CASE WHEN $7::text = 'email_asc' THEN email END ASC,
+    CASE WHEN $7::text = 'email_desc' THEN email END DESC,
+    CASE WHEN $7::text = 'created_at_desc' THEN created_at END DESC,
+    CASE WHEN $7::text IN ('created_at_asc', 'email_asc') THEN id END ASC,
+    id DESC
+LIMIT $10::int
+`
+
+type ListUsersParams struct {
+	Role            NullUserRole       `json:"role"`
+	Status          NullUserStatus     `json:"status"`
+	CreatedAfter    pgtype.Timestamptz `json:"created_after"`
+	CreatedBefore   pgtype.Timestamptz `json:"created_before"`
+	Search          pgtype.Text        `json:"search"`
+	CursorID        pgtype.UUID        `json:"cursor_id"`
+	Sort            string             `json:"sort"`
+	CursorCreatedAt pgtype.Timestamptz `json:"cursor_created_at"`
+	CursorEmail     pgtype.Text        `json:"cursor_email"`
+	PageLimit       int32              `json:"page_limit"`
+}
+
+// keyset pagination: the cursor holds the sort key and id of the last row of the previous page
+func (q *Queries) ListUsers(ctx context.Context, arg ListUsersParams) ([]User, error) {
+	rows, err := q.db.Query(ctx, listUsers,
+		arg.Role,
+		arg.Status,
+		arg.CreatedAfter,
+		arg.CreatedBefore,
+		arg.Search,
+		arg.CursorID,
+		arg.Sort,
+		arg.CursorCreatedAt,
+		arg.CursorEmail,
+		arg.PageLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []User
+	for rows.Next() {
+		var i User
+		if err := rows.Scan(
+			&i.ID,
+			&i.Firstname,
+			&i.Lastname,
+			&i.Email,
+			&i.PasswordHash,
+			&i.Role,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Version,
+			&i.PhoneNumber,
+			&i.PhoneVerifiedAt,
+			&i.Locale,
+			&i.Timezone,
+			&i.AvatarUrl,
+			&i.Status,
+			&i.StatusReason,
+			&i.StatusChangedBy,
+			&i.StatusChangedAt,
+			&i.SessionsRevokedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
 }
 
-// only update firstname, lastname, updated_at
-func (q *Queries) UpdateUser(ctx context.Context, arg UpdateUserParams) error {
-	_, err := q.db.Exec(ctx, updateUser, arg.ID, arg.Firstname, arg.Lastname)
+const updateUserRole = `-- name: UpdateUserRole :one
+UPDATE users SET role = $2, version = version + 1, updated_at = now() WHERE id = $1 AND version = $3 RETURNING id, firstname, lastname, email, password_hash, role, created_at, updated_at, version, phone_number, phone_verified_at, locale, timezone, avatar_url, status, status_reason, status_changed_by, status_changed_at, sessions_revoked_at
+`
+
+type UpdateUserRoleParams struct {
+	ID      pgtype.UUID `json:"id"`
+	Role    UserRole    `json:"role"`
+	Version int32       `json:"version"`
+}
+
+func (q *Queries) UpdateUserRole(ctx context.Context, arg UpdateUserRoleParams) (User, error) {
+	row := q.db.QueryRow(ctx, updateUserRole, arg.ID, arg.Role, arg.Version)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Firstname,
+		&i.Lastname,
+		&i.Email,
+		&i.PasswordHash,
+		&i.Role,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Version,
+		&i.PhoneNumber,
+		&i.PhoneVerifiedAt,
+		&i.Locale,
+		&i.Timezone,
+		&i.AvatarUrl,
+		&i.Status,
+		&i.StatusReason,
+		&i.StatusChangedBy,
+		&i.StatusChangedAt,
+		&i.SessionsRevokedAt,
+	)
+	return i, err
+}
+
+const revokeUserSessions = `-- name: RevokeUserSessions :exec
+UPDATE users SET sessions_revoked_at = now(), updated_at = now() WHERE id = $1
+`
+
+// tokens issued before sessions_revoked_at are no longer accepted
+func (q *Queries) RevokeUserSessions(ctx context.Context, id pgtype.UUID) error {
+	_, err := q.db.Exec(ctx, revokeUserSessions, id)
+	return err
+}
+
+const updateUserPassword = `-- name: UpdateUserPassword :exec
+UPDATE users SET password_hash = $2, version = version + 1, updated_at = now() WHERE id = $1
+`
+
+type UpdateUserPasswordParams struct {
+	ID           pgtype.UUID `json:"id"`
+	PasswordHash string      `json:"password_hash"`
+}
+
+func (q *Queries) UpdateUserPassword(ctx context.Context, arg UpdateUserPasswordParams) error {
+	_, err := q.db.Exec(ctx, updateUserPassword, arg.ID, arg.PasswordHash)
 	return err
 }
+
+const updateUserStatus = `-- name: UpdateUserStatus :one
+UPDATE users
+SET status = $1, status_reason = $2, status_changed_by = $3,
+    status_changed_at = now(), version = version + 1, updated_at = now()
+WHERE id = $4 AND status = $5
+RETURNING id, firstname, lastname, email, password_hash, role, created_at, updated_at, version, phone_number, phone_verified_at, locale, timezone, avatar_url, status, status_reason, status_changed_by, status_changed_at, sessions_revoked_at
+`
+
+type UpdateUserStatusParams struct {
+	Status          UserStatus  `json:"status"`
+	StatusReason    pgtype.Text `json:"status_reason"`
+	StatusChangedBy pgtype.UUID `json:"status_changed_by"`
+	ID              pgtype.UUID `json:"id"`
+	FromStatus      UserStatus  `json:"from_status"`
+}
+
+// compare-and-set on the current status so two admins cannot apply conflicting transitions
+func (q *Queries) UpdateUserStatus(ctx context.Context, arg UpdateUserStatusParams) (User, error) {
+	row := q.db.QueryRow(ctx, updateUserStatus,
+		arg.Status,
+		arg.StatusReason,
+		arg.StatusChangedBy,
+		arg.ID,
+		arg.FromStatus,
+	)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Firstname,
+		&i.Lastname,
+		&i.Email,
+		&i.PasswordHash,
+		&i.Role,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Version,
+		&i.PhoneNumber,
+		&i.PhoneVerifiedAt,
+		&i.Locale,
+		&i.Timezone,
+		&i.AvatarUrl,
+		&i.Status,
+		&i.StatusReason,
+		&i.StatusChangedBy,
+		&i.StatusChangedAt,
+		&i.SessionsRevokedAt,
+	)
+	return i, err
+}
@@ -3,9 +3,14 @@ package jwt
 import (
 	"github.com/golang-jwt/jwt/v5"
 	db "github.com/goodfoodcesi/auth-api/infrastructure/database/sqlc"
+	"github.com/google/uuid"
 	"time"
 )
 
+// DefaultAudience is the audience of tokens issued at login. Only tokens
+// obtained through a token exchange are meant for a single service.
+const DefaultAudience = "goodfood"
+
 type TokenPair struct {
 	AccessToken  string `json:"access_token"`
 	RefreshToken string `json:"refresh_token"`
@@ -14,9 +19,39 @@ type TokenPair struct {
 type Claims struct {
 	Role   db.UserRole `json:"role"`
 	UserID string      `json:"user_id"`
+	// Approved is only set for drivers, who may not take deliveries until
+	// their application has been approved.
+	Approved *bool `json:"approved,omitempty"`
+	// RestaurantID is the restaurant the token acts for, so restaurant-api
+	// can authorize a manager without looking the membership up.
+	RestaurantID   string            `json:"restaurant_id,omitempty"`
+	RestaurantRole db.MembershipRole `json:"restaurant_role,omitempty"`
+	// Permissions are the ones granted to Role when the token was issued.
+	Permissions []string `json:"perms,omitempty"`
+	// Actor is set on impersonation tokens to the admin acting as the user,
+	// following the "act" claim of RFC 8693.
+	Actor *Actor `json:"act,omitempty"`
+	// SessionID is shared by the access and refresh token of a pair.
+	SessionID string `json:"sid,omitempty"`
 	jwt.RegisteredClaims
 }
 
+type Actor struct {
+	Subject string `json:"sub"`
+	// Actor is the previous actor when a delegated token is exchanged again.
+	Actor *Actor `json:"act,omitempty"`
+}
+
+// Subject is who a token pair is issued to.
+type Subject struct {
+	UserID         string
+	Role           db.UserRole
+	Approved       *bool
+	RestaurantID   string
+	RestaurantRole db.MembershipRole
+	Permissions    []string
+}
+
 type TokenManager struct {
 	accessTokenSecret  []byte
 	refreshTokenSecret []byte
@@ -33,18 +68,28 @@ func NewTokenManager(accessSecret, refreshSecret string, accessTTL, refreshTTL t
 	}
 }
 
-func (tm *TokenManager) GenerateTokenPair(userID string, role db.UserRole) (*TokenPair, error) {
-	claims := Claims{
-		role,
-		userID,
-		jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(tm.accessTokenTTL)),
+func (tm *TokenManager) claims(sub Subject, ttl time.Duration) Claims {
+	return Claims{
+		Role:           sub.Role,
+		UserID:         sub.UserID,
+		Approved:       sub.Approved,
+		RestaurantID:   sub.RestaurantID,
+		RestaurantRole: sub.RestaurantRole,
+		Permissions:    sub.Permissions,
+		SessionID:      uuid.NewString(),
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   sub.UserID,
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(ttl)),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
 			NotBefore: jwt.NewNumericDate(time.Now()),
 			Issuer:    "Goodfood",
-			Audience:  []string{userID},
+			Audience:  []string{DefaultAudience},
 		},
 	}
+}
+
+func (tm *TokenManager) GenerateTokenPair(sub Subject) (*TokenPair, error) {
+	claims := tm.claims(sub, tm.accessTokenTTL)
 
 	accessToken := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 
@@ -67,15 +112,61 @@ func (tm *TokenManager) GenerateTokenPair(userID string, role db.UserRole) (*Tok
 	}, nil
 }
 
+// GenerateImpersonationToken issues a lone access token for sub on behalf of
+// actorID. There is no refresh token: the session ends when it expires.
+func (tm *TokenManager) GenerateImpersonationToken(sub Subject, actorID string, ttl time.Duration) (string, time.Time, error) {
+	claims := tm.claims(sub, ttl)
+	claims.Actor = &Actor{Subject: actorID}
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(tm.accessTokenSecret)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	return token, claims.ExpiresAt.Time, nil
+}
+
+// Exchange issues an access token for the same user as subject, accepted only
+// by audience and naming actorID as the party acting on the user's behalf.
+func (tm *TokenManager) Exchange(subject *Claims, audience, actorID string, permissions []string, ttl time.Duration) (string, time.Time, error) {
+	claims := *subject
+	claims.Permissions = permissions
+	claims.Actor = &Actor{Subject: actorID, Actor: subject.Actor}
+	claims.RegisteredClaims = jwt.RegisteredClaims{
+		Subject:   subject.UserID,
+		ExpiresAt: jwt.NewNumericDate(time.Now().Add(ttl)),
+		IssuedAt:  jwt.NewNumericDate(time.Now()),
+		NotBefore: jwt.NewNumericDate(time.Now()),
+		Issuer:    "Goodfood",
+		Audience:  []string{audience},
+	}
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(tm.accessTokenSecret)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	return token, claims.ExpiresAt.Time, nil
+}
+
+// ValidateToken accepts tokens issued for DefaultAudience only, so a token
+// exchanged for another service cannot be replayed against this one.
 func (tm *TokenManager) ValidateToken(tokenString string, isRefresh bool) (*Claims, error) {
 	secret := tm.accessTokenSecret
 	if isRefresh {
 		secret = tm.refreshTokenSecret
 	}
+	return tm.parse(tokenString, secret, jwt.WithAudience(DefaultAudience))
+}
+
+// ValidateSubjectToken checks an access token presented in a token exchange,
+// whatever its audience. The caller decides which audiences it accepts.
+func (tm *TokenManager) ValidateSubjectToken(tokenString string) (*Claims, error) {
+	return tm.parse(tokenString, tm.accessTokenSecret)
+}
 
+func (tm *TokenManager) parse(tokenString string, secret []byte, options ...jwt.ParserOption) (*Claims, error) {
 	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (interface{}, error) {
 		return secret, nil
-	})
+	}, options...)
 
 	if err != nil {
 		return nil, err
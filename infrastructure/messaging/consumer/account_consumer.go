@@ -0,0 +1,98 @@
+package consumer
+
+import (
+	"context"
+	"errors"
+
+	"github.com/goodfoodcesi/auth-api/domain/events"
+	"github.com/goodfoodcesi/auth-api/domain/messaging"
+	"github.com/goodfoodcesi/auth-api/domain/service"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/zap"
+)
+
+// AccountConsumer keeps accounts in line with what the other services report
+// about restaurants, customers and drivers.
+type AccountConsumer struct {
+	accounts *service.AccountSyncService
+	logger   *zap.Logger
+}
+
+func NewAccountConsumer(accounts *service.AccountSyncService, logger *zap.Logger) *AccountConsumer {
+	return &AccountConsumer{
+		accounts: accounts,
+		logger:   logger,
+	}
+}
+
+func (c *AccountConsumer) HandleRestaurantDeleted(ctx context.Context, envelope *messaging.Envelope) error {
+	var event events.RestaurantDeleted
+	if err := envelope.Decode(&event); err != nil {
+		return messaging.Permanent(err)
+	}
+	restaurantID, err := parseID(event.RestaurantID)
+	if err != nil {
+		return err
+	}
+
+	c.logger.Info("handling restaurant deleted event",
+		zap.String("restaurant_id", event.RestaurantID),
+		zap.String("correlation_id", envelope.CorrelationID),
+	)
+
+	return c.accounts.RemoveRestaurant(ctx, restaurantID)
+}
+
+func (c *AccountConsumer) HandleFraudFlagged(ctx context.Context, envelope *messaging.Envelope) error {
+	var event events.CustomerFraudFlagged
+	if err := envelope.Decode(&event); err != nil {
+		return messaging.Permanent(err)
+	}
+	userID, err := parseID(event.UserID)
+	if err != nil {
+		return err
+	}
+
+	c.logger.Info("handling customer fraud flagged event",
+		zap.String("user_id", event.UserID),
+		zap.String("correlation_id", envelope.CorrelationID),
+	)
+
+	return permanentIfMissing(c.accounts.SuspendForFraud(ctx, userID, event.Reason))
+}
+
+func (c *AccountConsumer) HandleDriverContractEnded(ctx context.Context, envelope *messaging.Envelope) error {
+	var event events.DriverContractEnded
+	if err := envelope.Decode(&event); err != nil {
+		return messaging.Permanent(err)
+	}
+	userID, err := parseID(event.UserID)
+	if err != nil {
+		return err
+	}
+
+	c.logger.Info("handling driver contract ended event",
+		zap.String("user_id", event.UserID),
+		zap.String("correlation_id", envelope.CorrelationID),
+	)
+
+	return c.accounts.EndDriverContract(ctx, userID, event.Reason)
+}
+
+// parseID fails permanently, as a redelivery carries the same id.
+func parseID(value string) (pgtype.UUID, error) {
+	id, err := uuid.Parse(value)
+	if err != nil {
+		return pgtype.UUID{}, messaging.Permanent(err)
+	}
+	return pgtype.UUID{Bytes: id, Valid: true}, nil
+}
+
+// permanentIfMissing stops retrying events about accounts that do not exist.
+func permanentIfMissing(err error) error {
+	if errors.Is(err, service.ErrUserNotFound) {
+		return messaging.Permanent(err)
+	}
+	return err
+}
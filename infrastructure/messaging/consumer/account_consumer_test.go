@@ -0,0 +1,199 @@
+package consumer_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/goodfoodcesi/auth-api/domain/events"
+	"github.com/goodfoodcesi/auth-api/domain/messaging"
+	"github.com/goodfoodcesi/auth-api/domain/repository/repotest"
+	"github.com/goodfoodcesi/auth-api/domain/service"
+	"github.com/goodfoodcesi/auth-api/infrastructure/database/sqlc"
+	"github.com/goodfoodcesi/auth-api/infrastructure/messaging/consumer"
+	"github.com/goodfoodcesi/auth-api/infrastructure/messaging/memory"
+	"github.com/goodfoodcesi/auth-api/infrastructure/messaging/rabbitmq"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/zap"
+)
+
+// accounts keeps the users, memberships and outbox the account consumer
+// works on in memory.
+type accounts struct {
+	users       *lookups
+	memberships *repotest.Memberships
+	outbox      *repotest.Outbox
+}
+
+// lookups counts how often an account is looked up.
+type lookups struct {
+	*repotest.Users
+	count int
+	// err, when set, is what looking an account up fails with.
+	err error
+}
+
+func (l *lookups) GetByID(ctx context.Context, id pgtype.UUID) (*db.User, error) {
+	l.count++
+	if l.err != nil {
+		return nil, l.err
+	}
+	return l.Users.GetByID(ctx, id)
+}
+
+func newAccounts(users ...*db.User) *accounts {
+	return &accounts{
+		users:       &lookups{Users: repotest.NewUsers(users...)},
+		memberships: repotest.NewMemberships(),
+		outbox:      repotest.NewOutbox(),
+	}
+}
+
+func (a *accounts) get(t *testing.T, id pgtype.UUID) *db.User {
+	t.Helper()
+
+	user, err := a.users.Users.GetByID(context.Background(), id)
+	if err != nil {
+		t.Fatalf("GetByID: %v", err)
+	}
+	return user
+}
+
+// consumeAccounts starts the account consumer on the queues of
+// rabbitmq.ConsumerTopology, as cmd/consumer does.
+func consumeAccounts(t *testing.T, store *accounts) *memory.Broker {
+	t.Helper()
+
+	broker := memory.NewBroker(events.Schemas)
+	if err := broker.DeclareTopology(rabbitmq.ConsumerTopology); err != nil {
+		t.Fatalf("DeclareTopology: %v", err)
+	}
+
+	accounts := service.NewAccountSyncService(
+		store.users,
+		repotest.NewDriverApplications(),
+		store.memberships,
+		store.outbox,
+		repotest.Transactor{},
+		zap.NewNop(),
+	)
+	accountConsumer := consumer.NewAccountConsumer(accounts, zap.NewNop())
+	handlers := map[string]messaging.Handler{
+		rabbitmq.RestaurantDeletedQueueAuthAPI:    accountConsumer.HandleRestaurantDeleted,
+		rabbitmq.CustomerFraudFlaggedQueueAuthAPI: accountConsumer.HandleFraudFlagged,
+	}
+	for queue, handler := range handlers {
+		if err := broker.Consume(queue, messaging.ConsumeOptions{}, handler); err != nil {
+			t.Fatalf("Consume %s: %v", queue, err)
+		}
+	}
+	return broker
+}
+
+func publishFraudFlagged(t *testing.T, broker *memory.Broker, userID string) {
+	t.Helper()
+
+	err := broker.Publish(context.Background(), rabbitmq.PaymentExchange, rabbitmq.CustomerFraudFlaggedKey, events.CustomerFraudFlagged{
+		UserID:     userID,
+		Reason:     "chargebacks",
+		OccurredAt: time.Now(),
+	})
+	if err != nil {
+		t.Fatalf("Publish: %v", err)
+	}
+}
+
+func TestFraudFlaggedSuspendsTheAccount(t *testing.T) {
+	id := repotest.NewID()
+	store := newAccounts(&db.User{ID: id, Status: db.UserStatusActive})
+	broker := consumeAccounts(t, store)
+
+	publishFraudFlagged(t, broker, id.String())
+
+	if status := store.get(t, id).Status; status != db.UserStatusSuspended {
+		t.Errorf("status = %s, want %s", status, db.UserStatusSuspended)
+	}
+	if events := store.outbox.EventTypes(); len(events) != 1 || events[0] != rabbitmq.UserSuspendedKey {
+		t.Errorf("outbox = %v, want one %s event", events, rabbitmq.UserSuspendedKey)
+	}
+	if deadLetters := broker.DeadLetters(rabbitmq.CustomerFraudFlaggedQueueAuthAPI); len(deadLetters) != 0 {
+		t.Errorf("dead-lettered %d events", len(deadLetters))
+	}
+}
+
+func TestFraudFlaggedForUnknownAccountIsDeadLettered(t *testing.T) {
+	store := newAccounts()
+	broker := consumeAccounts(t, store)
+
+	publishFraudFlagged(t, broker, repotest.NewID().String())
+
+	if deadLetters := broker.DeadLetters(rabbitmq.CustomerFraudFlaggedQueueAuthAPI); len(deadLetters) != 1 {
+		t.Fatalf("dead-lettered %d events, want 1", len(deadLetters))
+	}
+	// A redelivery would not find the account either.
+	if store.users.count != 1 {
+		t.Errorf("looked the account up %d times, want 1", store.users.count)
+	}
+}
+
+func TestFraudFlaggedIsRetriedWhileTheAccountCannotBeRead(t *testing.T) {
+	store := newAccounts()
+	store.users.err = errors.New("connection refused")
+	broker := consumeAccounts(t, store)
+
+	publishFraudFlagged(t, broker, repotest.NewID().String())
+
+	if deadLetters := broker.DeadLetters(rabbitmq.CustomerFraudFlaggedQueueAuthAPI); len(deadLetters) != 1 {
+		t.Fatalf("dead-lettered %d events, want 1", len(deadLetters))
+	}
+	if store.users.count != messaging.DefaultRetryPolicy.MaxAttempts {
+		t.Errorf("looked the account up %d times, want %d", store.users.count, messaging.DefaultRetryPolicy.MaxAttempts)
+	}
+}
+
+func TestFraudFlaggedWithMalformedUserIDIsDeadLettered(t *testing.T) {
+	store := newAccounts()
+	broker := consumeAccounts(t, store)
+
+	publishFraudFlagged(t, broker, "not-a-uuid")
+
+	if deadLetters := broker.DeadLetters(rabbitmq.CustomerFraudFlaggedQueueAuthAPI); len(deadLetters) != 1 {
+		t.Fatalf("dead-lettered %d events, want 1", len(deadLetters))
+	}
+	if store.users.count != 0 {
+		t.Errorf("looked the account up %d times, want 0", store.users.count)
+	}
+}
+
+func TestRestaurantDeletedRemovesItsMembers(t *testing.T) {
+	restaurantID, otherRestaurantID := repotest.NewID(), repotest.NewID()
+	owner, staff := repotest.NewID(), repotest.NewID()
+	store := newAccounts(&db.User{ID: owner}, &db.User{ID: staff})
+	store.memberships = repotest.NewMemberships(
+		db.Membership{UserID: owner, RestaurantID: restaurantID, Role: db.MembershipRoleOwner},
+		db.Membership{UserID: staff, RestaurantID: restaurantID, Role: db.MembershipRoleStaff},
+		db.Membership{UserID: staff, RestaurantID: otherRestaurantID, Role: db.MembershipRoleStaff},
+	)
+	broker := consumeAccounts(t, store)
+
+	event := events.RestaurantDeleted{RestaurantID: restaurantID.String(), OccurredAt: time.Now()}
+	// The second delivery finds nothing left to remove.
+	for range 2 {
+		if err := broker.Publish(context.Background(), rabbitmq.RestaurantExchange, rabbitmq.RestaurantDeletedKey, event); err != nil {
+			t.Fatalf("Publish: %v", err)
+		}
+	}
+
+	if memberships := store.memberships.All(); len(memberships) != 1 || memberships[0].RestaurantID != otherRestaurantID {
+		t.Errorf("memberships = %+v, want only the one of the other restaurant", memberships)
+	}
+	for _, id := range []pgtype.UUID{owner, staff} {
+		if !store.get(t, id).SessionsRevokedAt.Valid {
+			t.Errorf("sessions of %s were not revoked", id)
+		}
+	}
+	if deadLetters := broker.DeadLetters(rabbitmq.RestaurantDeletedQueueAuthAPI); len(deadLetters) != 0 {
+		t.Errorf("dead-lettered %d events", len(deadLetters))
+	}
+}
@@ -1,8 +1,10 @@
 package consumer
 
 import (
-	"encoding/json"
-	"github.com/goodfoodcesi/api-utils-go/pkg/event"
+	"context"
+
+	"github.com/goodfoodcesi/auth-api/domain/events"
+	"github.com/goodfoodcesi/auth-api/domain/messaging"
 	"go.uber.org/zap"
 )
 
@@ -16,15 +18,16 @@ func NewUserConsumer(logger *zap.Logger) *UserConsumer {
 	}
 }
 
-func (c *UserConsumer) HandleUserCreated(data []byte) error {
-	var userCreatedEvent event.UserCreatedEvent
-	if err := json.Unmarshal(data, &userCreatedEvent); err != nil {
-		return err
+func (c *UserConsumer) HandleUserCreated(ctx context.Context, envelope *messaging.Envelope) error {
+	var userCreatedEvent events.UserCreated
+	if err := envelope.Decode(&userCreatedEvent); err != nil {
+		return messaging.Permanent(err)
 	}
 
 	c.logger.Info("handling user created event",
 		zap.String("user_id", userCreatedEvent.ID.String()),
 		zap.String("email", userCreatedEvent.Email),
+		zap.String("correlation_id", envelope.CorrelationID),
 	)
 
 	// Logique de traitement...
@@ -0,0 +1,58 @@
+package consumer_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/goodfoodcesi/api-utils-go/pkg/event"
+	"github.com/goodfoodcesi/auth-api/domain/events"
+	"github.com/goodfoodcesi/auth-api/domain/messaging"
+	"github.com/goodfoodcesi/auth-api/domain/repository/repotest"
+	"github.com/goodfoodcesi/auth-api/infrastructure/messaging/consumer"
+	"github.com/goodfoodcesi/auth-api/infrastructure/messaging/memory"
+	"github.com/goodfoodcesi/auth-api/infrastructure/messaging/rabbitmq"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestUserCreatedIsHandled(t *testing.T) {
+	core, logs := observer.New(zap.InfoLevel)
+	broker := memory.NewBroker(events.Schemas)
+	if err := broker.DeclareTopology(rabbitmq.AuthTopology); err != nil {
+		t.Fatalf("DeclareTopology: %v", err)
+	}
+	userConsumer := consumer.NewUserConsumer(zap.New(core))
+	if err := broker.Consume(rabbitmq.ClientCreatedQueueClientAPI, messaging.ConsumeOptions{}, userConsumer.HandleUserCreated); err != nil {
+		t.Fatalf("Consume: %v", err)
+	}
+
+	id := repotest.NewID()
+	err := broker.Publish(context.Background(), rabbitmq.UserExchange, rabbitmq.UserCreatedKey, events.UserCreated{
+		UserCreatedEvent: event.UserCreatedEvent{
+			ID:        id,
+			Email:     "jane@example.com",
+			FirstName: "Jane",
+			LastName:  "Doe",
+			Role:      "client",
+			CreatedAt: time.Now(),
+		},
+	})
+	if err != nil {
+		t.Fatalf("Publish: %v", err)
+	}
+
+	handled := logs.FilterMessage("handling user created event").AllUntimed()
+	if len(handled) != 1 {
+		t.Fatalf("handled %d events, want 1", len(handled))
+	}
+	if fields := handled[0].ContextMap(); fields["user_id"] != id.String() || fields["email"] != "jane@example.com" {
+		t.Errorf("handled %v", fields)
+	}
+	if pending := broker.Pending(rabbitmq.ClientCreatedQueueClientAPI); len(pending) != 0 {
+		t.Errorf("%d events left in the queue", len(pending))
+	}
+	if deadLetters := broker.DeadLetters(rabbitmq.ClientCreatedQueueClientAPI); len(deadLetters) != 0 {
+		t.Errorf("dead-lettered %d events", len(deadLetters))
+	}
+}
@@ -0,0 +1,295 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/goodfoodcesi/auth-api/domain/messaging"
+	"github.com/goodfoodcesi/auth-api/infrastructure/messaging/rabbitmq"
+)
+
+var (
+	_ messaging.Publisher  = (*Broker)(nil)
+	_ messaging.Subscriber = (*Broker)(nil)
+)
+
+// Published is an event the broker accepted.
+type Published struct {
+	Exchange   string
+	RoutingKey string
+	Envelope   *messaging.Envelope
+}
+
+// Broker stands in for RabbitMQ in tests. It routes through direct, fanout
+// and topic exchanges like RabbitMQ does and delivers synchronously: when
+// Publish returns, the consumers have handled the event.
+type Broker struct {
+	mu          sync.Mutex
+	schemas     messaging.Schemas
+	exchanges   map[string]rabbitmq.ExchangeType
+	queues      map[string][]*messaging.Envelope
+	bindings    []rabbitmq.BindingConfig
+	consumers   map[string]memoryConsumer
+	published   []Published
+	deadLetters map[string][]*messaging.Envelope
+}
+
+type memoryConsumer struct {
+	options messaging.ConsumeOptions
+	handler messaging.Handler
+}
+
+func NewBroker(schemas messaging.Schemas) *Broker {
+	return &Broker{
+		schemas:     schemas,
+		exchanges:   make(map[string]rabbitmq.ExchangeType),
+		queues:      make(map[string][]*messaging.Envelope),
+		consumers:   make(map[string]memoryConsumer),
+		deadLetters: make(map[string][]*messaging.Envelope),
+	}
+}
+
+func (b *Broker) DeclareTopology(topology rabbitmq.Topology) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for _, exchange := range topology.Exchanges {
+		b.exchanges[exchange.Name] = exchange.Type
+	}
+	for _, queue := range topology.Queues {
+		if _, ok := b.queues[queue.Name]; !ok {
+			b.queues[queue.Name] = nil
+		}
+	}
+	for _, binding := range topology.Bindings {
+		if _, ok := b.exchanges[binding.Exchange]; !ok {
+			return fmt.Errorf("exchange %q is not declared", binding.Exchange)
+		}
+		if _, ok := b.queues[binding.Queue]; !ok {
+			return fmt.Errorf("queue %q is not declared", binding.Queue)
+		}
+		b.bindings = append(b.bindings, binding)
+	}
+	return nil
+}
+
+func (b *Broker) Publish(ctx context.Context, exchange, routingKey string, message interface{}) error {
+	envelope, err := messaging.NewEnvelope(ctx, b.schemas, routingKey, message)
+	if err != nil {
+		return &messaging.PublishError{Exchange: exchange, RoutingKey: routingKey, Err: err}
+	}
+	return b.PublishEnvelope(ctx, exchange, routingKey, envelope)
+}
+
+// PublishEnvelope fails with messaging.ErrUnroutable when no queue is bound
+// for the event, as a mandatory publish to RabbitMQ would.
+func (b *Broker) PublishEnvelope(_ context.Context, exchange, routingKey string, envelope *messaging.Envelope) error {
+	fail := func(err error) error {
+		return &messaging.PublishError{Exchange: exchange, RoutingKey: routingKey, MessageID: envelope.ID, Err: err}
+	}
+	if err := b.schemas.Validate(envelope.Type, envelope.Version, envelope.Data); err != nil {
+		return fail(err)
+	}
+
+	b.mu.Lock()
+	queues, err := b.route(exchange, routingKey)
+	if err != nil {
+		b.mu.Unlock()
+		return fail(err)
+	}
+	b.published = append(b.published, Published{Exchange: exchange, RoutingKey: routingKey, Envelope: envelope})
+	for _, queue := range queues {
+		b.queues[queue] = append(b.queues[queue], envelope)
+	}
+	b.mu.Unlock()
+
+	for _, queue := range queues {
+		b.deliver(queue)
+	}
+	return nil
+}
+
+// route returns the queues an event published to exchange with routingKey
+// ends up in. The default exchange routes to the queue named routingKey.
+func (b *Broker) route(exchange, routingKey string) ([]string, error) {
+	if exchange == "" {
+		if _, ok := b.queues[routingKey]; ok {
+			return []string{routingKey}, nil
+		}
+		return nil, messaging.ErrUnroutable
+	}
+
+	exchangeType, ok := b.exchanges[exchange]
+	if !ok {
+		return nil, fmt.Errorf("exchange %q is not declared", exchange)
+	}
+
+	var queues []string
+	seen := make(map[string]bool)
+	for _, binding := range b.bindings {
+		if binding.Exchange != exchange || seen[binding.Queue] {
+			continue
+		}
+		var matches bool
+		switch exchangeType {
+		case rabbitmq.FanoutExchange:
+			matches = true
+		case rabbitmq.TopicExchange:
+			matches = topicMatches(strings.Split(binding.RoutingKey, "."), strings.Split(routingKey, "."))
+		default:
+			matches = binding.RoutingKey == routingKey
+		}
+		if matches {
+			seen[binding.Queue] = true
+			queues = append(queues, binding.Queue)
+		}
+	}
+	if len(queues) == 0 {
+		return nil, messaging.ErrUnroutable
+	}
+	return queues, nil
+}
+
+// topicMatches applies a topic binding pattern, where * stands for exactly
+// one word and # for zero or more.
+func topicMatches(pattern, words []string) bool {
+	if len(pattern) == 0 {
+		return len(words) == 0
+	}
+	switch pattern[0] {
+	case "#":
+		for i := 0; i <= len(words); i++ {
+			if topicMatches(pattern[1:], words[i:]) {
+				return true
+			}
+		}
+		return false
+	case "*":
+		return len(words) > 0 && topicMatches(pattern[1:], words[1:])
+	default:
+		return len(words) > 0 && pattern[0] == words[0] && topicMatches(pattern[1:], words[1:])
+	}
+}
+
+// Consume hands the events already waiting in queue to handler, then every
+// event routed to it.
+func (b *Broker) Consume(queue string, options messaging.ConsumeOptions, handler messaging.Handler) error {
+	b.mu.Lock()
+	if _, ok := b.queues[queue]; !ok {
+		b.mu.Unlock()
+		return fmt.Errorf("queue %q is not declared", queue)
+	}
+	b.consumers[queue] = memoryConsumer{options: options.WithDefaults(queue), handler: handler}
+	b.mu.Unlock()
+
+	b.deliver(queue)
+	return nil
+}
+
+// Shutdown stops every consumer, the events routed afterwards wait in their
+// queue.
+func (b *Broker) Shutdown(context.Context) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.consumers = make(map[string]memoryConsumer)
+	return nil
+}
+
+// deliver empties queue into its consumer, if it has one. Failed events are
+// retried at once, without the policy's delays, then dead-lettered.
+func (b *Broker) deliver(queue string) {
+	for {
+		b.mu.Lock()
+		consumer, ok := b.consumers[queue]
+		if !ok || len(b.queues[queue]) == 0 {
+			b.mu.Unlock()
+			return
+		}
+		envelope := b.queues[queue][0]
+		b.queues[queue] = b.queues[queue][1:]
+		b.mu.Unlock()
+
+		var err error
+		for attempt := 1; attempt <= consumer.options.Retry.MaxAttempts; attempt++ {
+			err = handle(consumer, envelope)
+			if err == nil || errors.Is(err, messaging.ErrPermanent) {
+				break
+			}
+		}
+		if err != nil {
+			b.mu.Lock()
+			b.deadLetters[queue] = append(b.deadLetters[queue], envelope)
+			b.mu.Unlock()
+		}
+	}
+}
+
+func handle(consumer memoryConsumer, envelope *messaging.Envelope) (err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			err = messaging.Permanent(fmt.Errorf("handler panicked: %v", p))
+		}
+	}()
+
+	ctx := messaging.WithEnvelope(context.Background(), envelope)
+	if consumer.options.Inbox == nil {
+		return consumer.handler(ctx, envelope)
+	}
+	return consumer.options.Inbox.Process(ctx, consumer.options.Name, envelope.ID, func(ctx context.Context) error {
+		return consumer.handler(ctx, envelope)
+	})
+}
+
+// Published returns every event the broker accepted, oldest first.
+func (b *Broker) Published() []Published {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return append([]Published(nil), b.published...)
+}
+
+// PublishedOfType returns the accepted events of eventType, oldest first.
+func (b *Broker) PublishedOfType(eventType string) []*messaging.Envelope {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	var envelopes []*messaging.Envelope
+	for _, published := range b.published {
+		if published.Envelope.Type == eventType {
+			envelopes = append(envelopes, published.Envelope)
+		}
+	}
+	return envelopes
+}
+
+// Pending returns the events waiting in queue for a consumer.
+func (b *Broker) Pending(queue string) []*messaging.Envelope {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return append([]*messaging.Envelope(nil), b.queues[queue]...)
+}
+
+// DeadLetters returns the events the consumer of queue gave up on.
+func (b *Broker) DeadLetters(queue string) []*messaging.Envelope {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return append([]*messaging.Envelope(nil), b.deadLetters[queue]...)
+}
+
+// Reset forgets every event but keeps the topology and consumers.
+func (b *Broker) Reset() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.published = nil
+	for queue := range b.queues {
+		b.queues[queue] = nil
+	}
+	b.deadLetters = make(map[string][]*messaging.Envelope)
+}
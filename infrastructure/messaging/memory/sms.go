@@ -0,0 +1,48 @@
+package memory
+
+import (
+	"context"
+	"sync"
+
+	"github.com/goodfoodcesi/auth-api/domain/events"
+)
+
+// SmsOtpPublisher keeps the requested codes in memory instead of sending them
+// through RabbitMQ, so the phone login flow can run without a broker.
+type SmsOtpPublisher struct {
+	mu   sync.Mutex
+	sent []events.SmsOtpRequested
+}
+
+func NewSmsOtpPublisher() *SmsOtpPublisher {
+	return &SmsOtpPublisher{}
+}
+
+func (p *SmsOtpPublisher) PublishSmsOtpRequested(_ context.Context, smsOtpRequested events.SmsOtpRequested) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.sent = append(p.sent, smsOtpRequested)
+	return nil
+}
+
+// LastCode returns the latest code sent to phoneNumber.
+func (p *SmsOtpPublisher) LastCode(phoneNumber string) (string, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for i := len(p.sent) - 1; i >= 0; i-- {
+		if p.sent[i].PhoneNumber == phoneNumber {
+			return p.sent[i].Code, true
+		}
+	}
+	return "", false
+}
+
+// Sent returns a copy of every published request, oldest first.
+func (p *SmsOtpPublisher) Sent() []events.SmsOtpRequested {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return append([]events.SmsOtpRequested(nil), p.sent...)
+}
@@ -23,6 +23,7 @@ type QueueConfig struct {
 	AutoDelete bool
 	Exclusive  bool
 	NoWait     bool
+	Args       map[string]interface{}
 }
 
 type BindingConfig struct {
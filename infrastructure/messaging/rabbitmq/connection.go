@@ -0,0 +1,143 @@
+package rabbitmq
+
+import (
+	"context"
+	"math/rand/v2"
+	"time"
+
+	"github.com/goodfoodcesi/auth-api/domain/messaging"
+	amqp "github.com/rabbitmq/amqp091-go"
+	"go.uber.org/zap"
+)
+
+const (
+	reconnectMinDelay = 500 * time.Millisecond
+	reconnectMaxDelay = 30 * time.Second
+)
+
+// Ready tells a readiness probe whether the broker can be used.
+func (r *RabbitMQ) Ready(ctx context.Context) error {
+	if !r.connected.Load() {
+		return messaging.ErrNotConnected
+	}
+	return nil
+}
+
+func (r *RabbitMQ) dial() (*amqp.Connection, *amqp.Channel, error) {
+	conn, err := amqp.Dial(r.url)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ch, err := conn.Channel()
+	if err != nil {
+		conn.Close()
+		return nil, nil, err
+	}
+	return conn, ch, nil
+}
+
+// watch reconnects once conn drops, unless Close was called.
+func (r *RabbitMQ) watch(conn *amqp.Connection) {
+	closed := conn.NotifyClose(make(chan *amqp.Error, 1))
+
+	select {
+	case <-r.closing:
+		return
+	case err := <-closed:
+		select {
+		case <-r.closing:
+			return
+		default:
+		}
+		r.connected.Store(false)
+		r.logger.Warn("RabbitMQ connection lost", zap.Error(err))
+	}
+
+	r.reconnect()
+}
+
+func (r *RabbitMQ) reconnect() {
+	for attempt := 0; ; attempt++ {
+		delay := reconnectDelay(attempt)
+		select {
+		case <-r.closing:
+			return
+		case <-time.After(delay):
+		}
+
+		if err := r.restore(); err != nil {
+			r.logger.Error("failed to reconnect to RabbitMQ",
+				zap.Int("attempt", attempt+1),
+				zap.Duration("delay", delay),
+				zap.Error(err),
+			)
+			continue
+		}
+
+		r.logger.Info("Reconnected to RabbitMQ", zap.Int("attempts", attempt+1))
+		return
+	}
+}
+
+// restore dials again and re-declares everything declared on the lost
+// connection before reporting it ready.
+func (r *RabbitMQ) restore() error {
+	conn, ch, err := r.dial()
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if err := r.restoreTopology(conn, ch); err != nil {
+		conn.Close()
+		return err
+	}
+
+	// Pooled publisher channels died with the old connection.
+	r.drainPublishers()
+	r.conn, r.channel = conn, ch
+	r.connected.Store(true)
+	go r.watch(conn)
+	return nil
+}
+
+func (r *RabbitMQ) restoreTopology(conn *amqp.Connection, ch *amqp.Channel) error {
+	for _, exchange := range r.exchanges {
+		if err := declareExchange(ch, exchange); err != nil {
+			return err
+		}
+	}
+	for _, queue := range r.queues {
+		if err := declareQueue(ch, queue); err != nil {
+			return err
+		}
+	}
+	for _, binding := range r.bindings {
+		if err := bindQueue(ch, binding); err != nil {
+			return err
+		}
+	}
+	if r.draining {
+		return nil
+	}
+	for _, consumer := range r.consumers {
+		if err := r.consume(conn, consumer); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// reconnectDelay doubles with each attempt, with jitter so that every
+// instance does not hit the broker at the same time.
+func reconnectDelay(attempt int) time.Duration {
+	delay := reconnectMinDelay
+	for i := 0; i < attempt && delay < reconnectMaxDelay; i++ {
+		delay *= 2
+	}
+	delay = min(delay, reconnectMaxDelay)
+	return delay/2 + rand.N(delay/2)
+}
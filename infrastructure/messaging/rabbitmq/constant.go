@@ -2,12 +2,39 @@ package rabbitmq
 
 const (
 	// Exchanges
-	ClientExchange = "client.events"
+	UserExchange         = "user.events"
+	NotificationExchange = "notification.events"
+	DriverExchange       = "driver.events"
+	// Exchanges of the other services, consumed by auth-api
+	RestaurantExchange = "restaurant.events"
+	PaymentExchange    = "payment.events"
+	DeliveryExchange   = "delivery.events"
 
 	// Queues
 	ClientCreatedQueueNotificationAPI = "client.created.notification-api"
 	ClientCreatedQueueClientAPI       = "client.created.client-api"
+	SmsOtpQueueNotificationAPI        = "sms.otp.notification-api"
+	UserStatusQueueOrderAPI           = "user.status.order-api"
+	UserProfileQueueClientAPI         = "user.profile.client-api"
+	UserSecurityQueueNotificationAPI  = "user.security.notification-api"
+	DriverQueueNotificationAPI        = "driver.application.notification-api"
+	RestaurantDeletedQueueAuthAPI     = "restaurant.deleted.auth-api"
+	CustomerFraudFlaggedQueueAuthAPI  = "customer.fraud_flagged.auth-api"
+	DriverContractEndedQueueAuthAPI   = "driver.contract_ended.auth-api"
 
-	// Routing Keys (not needed for fanout, but keeping for reference)
-	ClientCreatedKey = "client.created"
+	// Routing Keys
+	UserCreatedKey          = "user.created"
+	UserUpdatedKey          = "user.updated"
+	UserDeletedKey          = "user.deleted"
+	UserRoleChangedKey      = "user.role_changed"
+	UserSuspendedKey        = "user.suspended"
+	UserReactivatedKey      = "user.reactivated"
+	UserPasswordChangedKey  = "user.password_changed"
+	UserLoggedInKey         = "user.logged_in"
+	SmsOtpKey               = "sms.otp_requested"
+	DriverApprovedKey       = "driver.approved"
+	DriverRejectedKey       = "driver.rejected"
+	RestaurantDeletedKey    = "restaurant.deleted"
+	CustomerFraudFlaggedKey = "customer.fraud_flagged"
+	DriverContractEndedKey  = "driver.contract_ended"
 )
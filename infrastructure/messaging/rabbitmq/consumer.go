@@ -0,0 +1,176 @@
+package rabbitmq
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/goodfoodcesi/auth-api/domain/messaging"
+	"github.com/google/uuid"
+	amqp "github.com/rabbitmq/amqp091-go"
+	"go.uber.org/zap"
+)
+
+type consumer struct {
+	queue   string
+	options messaging.ConsumeOptions
+	handler messaging.Handler
+	// The channel and tag of the current subscription, they change when
+	// reconnecting. Guarded by RabbitMQ.mu.
+	channel *amqp.Channel
+	tag     string
+}
+
+// Consume hands every valid event of queueName to handler, and keeps doing so
+// across reconnections. A message the handler fails on is retried as
+// options.Retry says, then moved to the dead-letter queue of queueName.
+func (r *RabbitMQ) Consume(queueName string, options messaging.ConsumeOptions, handler messaging.Handler) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.draining {
+		return messaging.ErrShuttingDown
+	}
+
+	c := &consumer{queue: queueName, options: options.WithDefaults(queueName), handler: handler}
+	for _, queue := range retryQueues(c.options.Retry, queueName) {
+		if err := declareQueue(r.channel, queue); err != nil {
+			return err
+		}
+		r.queues[queue.Name] = queue
+	}
+
+	if err := r.consume(r.conn, c); err != nil {
+		return err
+	}
+
+	r.consumers = append(r.consumers, c)
+	return nil
+}
+
+// consume subscribes c on a channel of its own, as the prefetch applies to a
+// whole channel, and starts its workers.
+func (r *RabbitMQ) consume(conn *amqp.Connection, c *consumer) error {
+	ch, err := conn.Channel()
+	if err != nil {
+		return fmt.Errorf("failed to open channel: %w", err)
+	}
+	if err := ch.Qos(c.options.Prefetch, 0, false); err != nil {
+		ch.Close()
+		return fmt.Errorf("failed to set prefetch: %w", err)
+	}
+
+	tag := c.queue + "-" + uuid.NewString()
+	msgs, err := ch.Consume(
+		c.queue,
+		tag,
+		false, // auto-ack
+		false, // exclusive
+		false, // no-local
+		false, // no-wait
+		nil,   // args
+	)
+	if err != nil {
+		ch.Close()
+		return fmt.Errorf("failed to register consumer: %w", err)
+	}
+	c.channel, c.tag = ch, tag
+
+	// msgs is closed when the connection drops, the consumer is then
+	// registered again on a new channel.
+	for i := 0; i < c.options.Workers; i++ {
+		r.workers.Add(1)
+		go func() {
+			defer r.workers.Done()
+			for msg := range msgs {
+				r.handle(c, msg)
+			}
+		}()
+	}
+
+	return nil
+}
+
+func (r *RabbitMQ) handle(c *consumer, msg amqp.Delivery) {
+	envelope, err := r.receive(msg)
+	if err != nil {
+		r.fail(c, msg, messaging.Permanent(err))
+		return
+	}
+
+	if err := r.call(c, envelope); err != nil {
+		r.fail(c, msg, err)
+		return
+	}
+	if err := msg.Ack(false); err != nil {
+		// The channel is gone, the message will be redelivered.
+		r.logger.Error("failed to ack message", zap.Error(err), zap.String("message_id", envelope.ID))
+	}
+}
+
+// call runs the handler of c. A panic fails the message for good instead of
+// killing the worker.
+func (r *RabbitMQ) call(c *consumer, envelope *messaging.Envelope) (err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			r.logger.Error("message handler panicked",
+				zap.Any("panic", p),
+				zap.String("queue", c.queue),
+				zap.String("message_id", envelope.ID),
+				zap.Stack("stack"),
+			)
+			err = messaging.Permanent(fmt.Errorf("handler panicked: %v", p))
+		}
+	}()
+
+	ctx := messaging.WithEnvelope(r.handlerCtx, envelope)
+	if c.options.Inbox == nil {
+		return c.handler(ctx, envelope)
+	}
+	return c.options.Inbox.Process(ctx, c.options.Name, envelope.ID, func(ctx context.Context) error {
+		return c.handler(ctx, envelope)
+	})
+}
+
+// receive reads the envelope of msg and checks its payload against its schema.
+func (r *RabbitMQ) receive(msg amqp.Delivery) (*messaging.Envelope, error) {
+	envelope, err := envelopeFromDelivery(msg)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.schemas.Validate(envelope.Type, envelope.Version, envelope.Data); err != nil {
+		return nil, err
+	}
+	return envelope, nil
+}
+
+// Shutdown stops every consumer and waits for the messages being handled
+// until ctx is done. The handlers' context is then cancelled, and whatever
+// they did not ack is redelivered once the connection is closed.
+func (r *RabbitMQ) Shutdown(ctx context.Context) error {
+	r.mu.Lock()
+	r.draining = true
+	for _, c := range r.consumers {
+		if c.channel == nil {
+			continue
+		}
+		// The workers end once the deliveries already received are handled.
+		if err := c.channel.Cancel(c.tag, false); err != nil {
+			r.logger.Warn("failed to cancel consumer", zap.String("queue", c.queue), zap.Error(err))
+		}
+	}
+	r.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		r.workers.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		r.cancelHandlers()
+		return ctx.Err()
+	}
+}
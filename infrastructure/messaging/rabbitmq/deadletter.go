@@ -0,0 +1,142 @@
+package rabbitmq
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// DeadLetter is a message the consumer of Queue gave up on.
+type DeadLetter struct {
+	MessageID      string
+	Type           string
+	Queue          string
+	Attempts       int
+	Reason         string
+	DeadLetteredAt time.Time
+	Headers        amqp.Table
+	Body           []byte
+}
+
+func newDeadLetter(msg amqp.Delivery) DeadLetter {
+	reason, _ := msg.Headers[deadLetterReasonHeader].(string)
+	queue, _ := msg.Headers[originalQueueHeader].(string)
+	deadLetteredAt, _ := msg.Headers[deadLetteredAtHeader].(string)
+	at, _ := time.Parse(time.RFC3339, deadLetteredAt)
+
+	return DeadLetter{
+		MessageID:      msg.MessageId,
+		Type:           msg.Type,
+		Queue:          queue,
+		Attempts:       deliveryAttempts(msg),
+		Reason:         reason,
+		DeadLetteredAt: at,
+		Headers:        msg.Headers,
+		Body:           msg.Body,
+	}
+}
+
+// DeadLetters returns up to limit messages of the dead-letter queue of queue,
+// oldest first, leaving them in it.
+func (r *RabbitMQ) DeadLetters(queue string, limit int) ([]DeadLetter, error) {
+	ch, err := r.adminChannel()
+	if err != nil {
+		return nil, err
+	}
+	// Closing the channel puts the unacked messages back.
+	defer ch.Close()
+
+	var deadLetters []DeadLetter
+	for len(deadLetters) < limit {
+		msg, ok, err := ch.Get(DeadLetterQueue(queue), false)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read dead letters: %w", err)
+		}
+		if !ok {
+			break
+		}
+		deadLetters = append(deadLetters, newDeadLetter(msg))
+	}
+	return deadLetters, nil
+}
+
+// RequeueDeadLetters moves the dead letters of queue with the given message
+// ids, or all of them when none is given, back to queue with their attempts
+// reset. It returns how many were moved.
+func (r *RabbitMQ) RequeueDeadLetters(ctx context.Context, queue string, messageIDs []string) (int, error) {
+	wanted := make(map[string]bool, len(messageIDs))
+	for _, id := range messageIDs {
+		wanted[id] = true
+	}
+
+	ch, err := r.adminChannel()
+	if err != nil {
+		return 0, err
+	}
+	defer ch.Close()
+
+	// Only the messages there now are looked at: one the consumer fails
+	// again comes back to the end of the queue and would be read forever.
+	dlq, err := ch.QueueDeclarePassive(DeadLetterQueue(queue), true, false, false, false, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to inspect dead letters: %w", err)
+	}
+
+	requeued := 0
+	for i := 0; i < dlq.Messages; i++ {
+		msg, ok, err := ch.Get(DeadLetterQueue(queue), false)
+		if err != nil {
+			return requeued, fmt.Errorf("failed to read dead letters: %w", err)
+		}
+		if !ok {
+			break
+		}
+		if len(wanted) > 0 && !wanted[msg.MessageId] {
+			continue
+		}
+
+		republish := deliveryPublishing(msg)
+		for _, header := range []string{attemptsHeader, lastErrorHeader, originalQueueHeader, deadLetterReasonHeader, deadLetteredAtHeader} {
+			delete(republish.Headers, header)
+		}
+		if err := r.publish(ctx, "", queue, republish); err != nil {
+			return requeued, err
+		}
+		if err := msg.Ack(false); err != nil {
+			return requeued, fmt.Errorf("failed to ack dead letter: %w", err)
+		}
+		requeued++
+	}
+	return requeued, nil
+}
+
+// PurgeDeadLetters drops every dead letter of queue and returns how many
+// there were.
+func (r *RabbitMQ) PurgeDeadLetters(queue string) (int, error) {
+	ch, err := r.adminChannel()
+	if err != nil {
+		return 0, err
+	}
+	defer ch.Close()
+
+	purged, err := ch.QueuePurge(DeadLetterQueue(queue), false)
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge dead letters: %w", err)
+	}
+	return purged, nil
+}
+
+// adminChannel is a channel of its own, so the messages it leaves unacked are
+// put back when it is closed.
+func (r *RabbitMQ) adminChannel() (*amqp.Channel, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	ch, err := r.conn.Channel()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open channel: %w", err)
+	}
+	return ch, nil
+}
@@ -0,0 +1,104 @@
+package rabbitmq
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/goodfoodcesi/auth-api/domain/messaging"
+	"github.com/google/uuid"
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// CloudEvents AMQP binding, binary content mode: the payload is the message
+// body and the attributes are application properties with this prefix.
+const (
+	cloudEventsPrefix      = "cloudEvents_"
+	cloudEventsSpecVersion = "1.0"
+)
+
+// publishing is envelope in the CloudEvents AMQP binding.
+func publishing(e *messaging.Envelope) amqp.Publishing {
+	headers := amqp.Table{
+		cloudEventsPrefix + "specversion":   cloudEventsSpecVersion,
+		cloudEventsPrefix + "id":            e.ID,
+		cloudEventsPrefix + "type":          e.Type,
+		cloudEventsPrefix + "source":        e.Producer,
+		cloudEventsPrefix + "time":          e.OccurredAt.Format(time.RFC3339Nano),
+		cloudEventsPrefix + "schemaversion": int32(e.Version),
+	}
+	if e.CorrelationID != "" {
+		headers[cloudEventsPrefix+"correlationid"] = e.CorrelationID
+	}
+	if e.CausationID != "" {
+		headers[cloudEventsPrefix+"causationid"] = e.CausationID
+	}
+
+	return amqp.Publishing{
+		Headers:       headers,
+		ContentType:   "application/json",
+		DeliveryMode:  amqp.Persistent,
+		CorrelationId: e.CorrelationID,
+		MessageId:     e.ID,
+		Timestamp:     e.OccurredAt,
+		Type:          e.Type,
+		AppId:         e.Producer,
+		Body:          e.Data,
+	}
+}
+
+// messageNamespace names the ids derived for messages published without one.
+var messageNamespace = uuid.MustParse("6f1c2a43-5b7e-4c1d-9a0e-3d2f8b6c4e15")
+
+// envelopeFromDelivery reads the envelope back from msg. A message without
+// the CloudEvents headers predates envelopes, or comes from a service that
+// does not use them: its routing key is its type and it is taken as version
+// 1. Without a message id either, its id is derived from its type and body,
+// so a redelivery still gets the same one.
+func envelopeFromDelivery(msg amqp.Delivery) (*messaging.Envelope, error) {
+	header := func(name string) string {
+		value, _ := msg.Headers[cloudEventsPrefix+name].(string)
+		return value
+	}
+
+	envelope := &messaging.Envelope{
+		ID:            header("id"),
+		Type:          header("type"),
+		Version:       1,
+		OccurredAt:    msg.Timestamp,
+		Producer:      header("source"),
+		CorrelationID: header("correlationid"),
+		CausationID:   header("causationid"),
+		Data:          msg.Body,
+	}
+	if envelope.Type == "" {
+		envelope.Type = msg.Type
+	}
+	if envelope.Type == "" {
+		envelope.Type = msg.RoutingKey
+	}
+	if envelope.ID == "" {
+		envelope.ID = msg.MessageId
+	}
+	if envelope.ID == "" {
+		envelope.ID = uuid.NewSHA1(messageNamespace, append([]byte(envelope.Type+"\n"), msg.Body...)).String()
+	}
+	if envelope.Producer == "" {
+		envelope.Producer = msg.AppId
+	}
+
+	switch version := msg.Headers[cloudEventsPrefix+"schemaversion"].(type) {
+	case int32:
+		envelope.Version = int(version)
+	case int64:
+		envelope.Version = int(version)
+	}
+	if value := header("time"); value != "" {
+		occurredAt, err := time.Parse(time.RFC3339Nano, value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %stime header: %w", cloudEventsPrefix, err)
+		}
+		envelope.OccurredAt = occurredAt
+	}
+
+	return envelope, nil
+}
@@ -0,0 +1,167 @@
+package rabbitmq
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/goodfoodcesi/auth-api/domain/messaging"
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+const (
+	// publisherPoolSize is how many idle channels are kept, more are opened
+	// when needed so concurrent requests never wait for one another.
+	publisherPoolSize = 8
+	// publishTimeout bounds the wait for a confirm when ctx has no deadline.
+	publishTimeout = 5 * time.Second
+)
+
+// publisher is a channel in confirm mode. It is used by one publish at a
+// time, so the returns it gets belong to that publish.
+type publisher struct {
+	channel *amqp.Channel
+	returns chan amqp.Return
+}
+
+// Publish wraps message in an envelope typed by routingKey and publishes it
+// like PublishEnvelope.
+func (r *RabbitMQ) Publish(ctx context.Context, exchange, routingKey string, message interface{}) error {
+	envelope, err := messaging.NewEnvelope(ctx, r.schemas, routingKey, message)
+	if err != nil {
+		return &messaging.PublishError{Exchange: exchange, RoutingKey: routingKey, Err: err}
+	}
+	return r.PublishEnvelope(ctx, exchange, routingKey, envelope)
+}
+
+// PublishEnvelope publishes envelope as mandatory and waits until the broker
+// confirms it took responsibility for it. The envelope id is the AMQP message
+// id so consumers can drop redeliveries.
+func (r *RabbitMQ) PublishEnvelope(ctx context.Context, exchange, routingKey string, envelope *messaging.Envelope) error {
+	if err := r.schemas.Validate(envelope.Type, envelope.Version, envelope.Data); err != nil {
+		return &messaging.PublishError{Exchange: exchange, RoutingKey: routingKey, MessageID: envelope.ID, Err: err}
+	}
+	return r.publish(ctx, exchange, routingKey, publishing(envelope))
+}
+
+// publish sends msg as is, see PublishEnvelope.
+func (r *RabbitMQ) publish(ctx context.Context, exchange, routingKey string, msg amqp.Publishing) error {
+	messageID := msg.MessageId
+	fail := func(err error) error {
+		return &messaging.PublishError{Exchange: exchange, RoutingKey: routingKey, MessageID: messageID, Err: err}
+	}
+
+	if !r.connected.Load() {
+		return fail(messaging.ErrNotConnected)
+	}
+
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, publishTimeout)
+		defer cancel()
+	}
+
+	p, err := r.acquirePublisher()
+	if err != nil {
+		return fail(err)
+	}
+
+	confirmation, err := p.channel.PublishWithDeferredConfirmWithContext(
+		ctx,
+		exchange,
+		routingKey,
+		true, // mandatory
+		false,
+		msg,
+	)
+	if err != nil {
+		p.channel.Close()
+		return fail(err)
+	}
+
+	acked, err := confirmation.WaitContext(ctx)
+	if err != nil {
+		// A late confirm would be mistaken for the next publish's.
+		p.channel.Close()
+		if errors.Is(err, context.DeadlineExceeded) {
+			return fail(messaging.ErrConfirmTimeout)
+		}
+		return fail(err)
+	}
+
+	// The broker returns an unroutable message before confirming it.
+	unroutable := false
+	for drained := false; !drained; {
+		select {
+		case ret, ok := <-p.returns:
+			drained = !ok
+			if ok && ret.MessageId == messageID {
+				unroutable = true
+			}
+		default:
+			drained = true
+		}
+	}
+	r.releasePublisher(p)
+
+	switch {
+	case unroutable:
+		return fail(messaging.ErrUnroutable)
+	case !acked:
+		return fail(messaging.ErrNacked)
+	}
+	return nil
+}
+
+func (r *RabbitMQ) acquirePublisher() (*publisher, error) {
+	for {
+		select {
+		case p := <-r.publishers:
+			if !p.channel.IsClosed() {
+				return p, nil
+			}
+		default:
+			return r.openPublisher()
+		}
+	}
+}
+
+func (r *RabbitMQ) openPublisher() (*publisher, error) {
+	r.mu.RLock()
+	ch, err := r.conn.Channel()
+	r.mu.RUnlock()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open channel: %w", err)
+	}
+
+	if err := ch.Confirm(false); err != nil {
+		ch.Close()
+		return nil, fmt.Errorf("failed to enable publisher confirms: %w", err)
+	}
+
+	return &publisher{
+		channel: ch,
+		returns: ch.NotifyReturn(make(chan amqp.Return, 1)),
+	}, nil
+}
+
+// releasePublisher keeps p for the next publish unless the pool is full.
+func (r *RabbitMQ) releasePublisher(p *publisher) {
+	select {
+	case r.publishers <- p:
+	default:
+		p.channel.Close()
+	}
+}
+
+func (r *RabbitMQ) drainPublishers() {
+	for {
+		select {
+		case p := <-r.publishers:
+			p.channel.Close()
+		default:
+			return
+		}
+	}
+}
@@ -2,61 +2,79 @@ package rabbitmq
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
+	"sync"
+	"sync/atomic"
+
+	"github.com/goodfoodcesi/auth-api/domain/messaging"
+	"github.com/goodfoodcesi/auth-api/infrastructure/messaging/schema"
 	amqp "github.com/rabbitmq/amqp091-go"
 	"go.uber.org/zap"
-	"sync"
+)
+
+var (
+	_ messaging.Publisher  = (*RabbitMQ)(nil)
+	_ messaging.Subscriber = (*RabbitMQ)(nil)
 )
 
 type RabbitMQ struct {
-	conn      *amqp.Connection
-	channel   *amqp.Channel
+	url     string
+	conn    *amqp.Connection
+	channel *amqp.Channel
+	// publishers holds idle confirm-mode channels, see publisher.go.
+	publishers chan *publisher
+	// schemas checks every payload published and consumed.
+	schemas *schema.Registry
+	// The topology and consumers are recorded so they can be restored after
+	// reconnecting.
 	exchanges map[string]ExchangeConfig
 	queues    map[string]QueueConfig
 	bindings  []BindingConfig
-	logger    *zap.Logger
-	mu        sync.RWMutex
+	consumers []*consumer
+	// workers counts the goroutines handling messages, see consumer.go.
+	workers  sync.WaitGroup
+	draining bool
+	// handlerCtx is cancelled when Shutdown gives up waiting for handlers.
+	handlerCtx     context.Context
+	cancelHandlers context.CancelFunc
+	connected      atomic.Bool
+	closing        chan struct{}
+	closeOnce      sync.Once
+	logger         *zap.Logger
+	mu             sync.RWMutex
 }
 
-func NewRabbitMQ(url string, logger *zap.Logger) (*RabbitMQ, error) {
-	conn, err := amqp.Dial(url)
-	if err != nil {
-		return nil, fmt.Errorf("failed to connect to RabbitMQ: %w", err)
+func NewRabbitMQ(url string, schemas *schema.Registry, logger *zap.Logger) (*RabbitMQ, error) {
+	r := &RabbitMQ{
+		url:        url,
+		schemas:    schemas,
+		exchanges:  make(map[string]ExchangeConfig),
+		queues:     make(map[string]QueueConfig),
+		publishers: make(chan *publisher, publisherPoolSize),
+		closing:    make(chan struct{}),
+		logger:     logger,
 	}
+	r.handlerCtx, r.cancelHandlers = context.WithCancel(context.Background())
 
-	ch, err := conn.Channel()
+	conn, ch, err := r.dial()
 	if err != nil {
-		conn.Close()
-		return nil, fmt.Errorf("failed to open channel: %w", err)
+		return nil, fmt.Errorf("failed to connect to RabbitMQ: %w", err)
 	}
+	r.conn, r.channel = conn, ch
+	r.connected.Store(true)
+	go r.watch(conn)
 
 	logger.Info("Connected to RabbitMQ")
 
-	return &RabbitMQ{
-		conn:      conn,
-		channel:   ch,
-		exchanges: make(map[string]ExchangeConfig),
-		queues:    make(map[string]QueueConfig),
-		logger:    logger,
-	}, nil
+	return r, nil
 }
 
 func (r *RabbitMQ) DeclareExchange(config ExchangeConfig) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
-	err := r.channel.ExchangeDeclare(
-		config.Name,
-		string(config.Type),
-		config.Durable,
-		config.AutoDelete,
-		config.Internal,
-		config.NoWait,
-		nil,
-	)
-	if err != nil {
-		return fmt.Errorf("failed to declare exchange: %w", err)
+	if err := declareExchange(r.channel, config); err != nil {
+		return err
 	}
 
 	r.exchanges[config.Name] = config
@@ -67,16 +85,8 @@ func (r *RabbitMQ) DeclareQueue(config QueueConfig) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
-	_, err := r.channel.QueueDeclare(
-		config.Name,
-		config.Durable,
-		config.AutoDelete,
-		config.Exclusive,
-		config.NoWait,
-		nil,
-	)
-	if err != nil {
-		return fmt.Errorf("failed to declare queue: %w", err)
+	if err := declareQueue(r.channel, config); err != nil {
+		return err
 	}
 
 	r.queues[config.Name] = config
@@ -87,115 +97,70 @@ func (r *RabbitMQ) BindQueue(config BindingConfig) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
-	err := r.channel.QueueBind(
-		config.Queue,
-		config.RoutingKey,
-		config.Exchange,
-		config.NoWait,
-		nil,
-	)
-	if err != nil {
-		return fmt.Errorf("failed to bind queue: %w", err)
+	if err := bindQueue(r.channel, config); err != nil {
+		return err
 	}
 
 	r.bindings = append(r.bindings, config)
 	return nil
 }
 
-func (r *RabbitMQ) Publish(ctx context.Context, exchange, routingKey string, message interface{}) error {
-	if err := r.ensureConnection(); err != nil {
-		return fmt.Errorf("failed to ensure RabbitMQ connection: %w", err)
-	}
+func (r *RabbitMQ) Close() error {
+	r.closeOnce.Do(func() { close(r.closing) })
+	r.connected.Store(false)
+	r.cancelHandlers()
 
-	body, err := json.Marshal(message)
-	if err != nil {
-		return fmt.Errorf("failed to marshal message: %w", err)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if err := r.channel.Close(); err != nil {
+		r.logger.Error("failed to close channel", zap.Error(err))
 	}
+	r.drainPublishers()
+	return r.conn.Close()
+}
 
-	err = r.channel.Publish(
-		exchange,
-		routingKey,
-		false,
-		false,
-		amqp.Publishing{
-			ContentType:  "application/json",
-			Body:         body,
-			DeliveryMode: amqp.Persistent,
-		},
+func declareExchange(ch *amqp.Channel, config ExchangeConfig) error {
+	err := ch.ExchangeDeclare(
+		config.Name,
+		string(config.Type),
+		config.Durable,
+		config.AutoDelete,
+		config.Internal,
+		config.NoWait,
+		nil,
 	)
-
 	if err != nil {
-		return fmt.Errorf("failed to publish message: %w", err)
+		return fmt.Errorf("failed to declare exchange: %w", err)
 	}
-
 	return nil
 }
 
-func (r *RabbitMQ) ensureConnection() error {
-	r.mu.Lock()
-	defer r.mu.Unlock()
-
-	if r.conn == nil || r.conn.IsClosed() {
-		var err error
-		r.conn, err = amqp.Dial(r.conn.LocalAddr().String())
-		if err != nil {
-			return fmt.Errorf("failed to reconnect to RabbitMQ: %w", err)
-		}
-
-		r.channel, err = r.conn.Channel()
-		if err != nil {
-			r.conn.Close()
-			return fmt.Errorf("failed to reopen channel: %w", err)
-		}
+func declareQueue(ch *amqp.Channel, config QueueConfig) error {
+	_, err := ch.QueueDeclare(
+		config.Name,
+		config.Durable,
+		config.AutoDelete,
+		config.Exclusive,
+		config.NoWait,
+		config.Args,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to declare queue: %w", err)
 	}
 	return nil
 }
 
-func (r *RabbitMQ) Consume(queueName string, handler func([]byte) error) error {
-	msgs, err := r.channel.Consume(
-		queueName,
-		"",    // consumer
-		false, // auto-ack
-		false, // exclusive
-		false, // no-local
-		false, // no-wait
-		nil,   // args
+func bindQueue(ch *amqp.Channel, config BindingConfig) error {
+	err := ch.QueueBind(
+		config.Queue,
+		config.RoutingKey,
+		config.Exchange,
+		config.NoWait,
+		nil,
 	)
 	if err != nil {
-		return fmt.Errorf("failed to register consumer: %w", err)
+		return fmt.Errorf("failed to bind queue: %w", err)
 	}
-
-	go func() {
-		for msg := range msgs {
-			if err := handler(msg.Body); err != nil {
-				r.logger.Error("failed to process message",
-					zap.Error(err),
-					zap.String("queue", queueName),
-				)
-				err := msg.Nack(false, true)
-				if err != nil {
-					r.logger.Error("failed to nack message", zap.Error(err))
-					return
-				} // requeue message
-			} else {
-				err := msg.Ack(false)
-				if err != nil {
-					r.logger.Error("failed to ack message", zap.Error(err))
-					return
-				}
-			}
-		}
-	}()
-
 	return nil
 }
-
-func (r *RabbitMQ) Close() error {
-	r.mu.Lock()
-	defer r.mu.Unlock()
-
-	if err := r.channel.Close(); err != nil {
-		r.logger.Error("failed to close channel", zap.Error(err))
-	}
-	return r.conn.Close()
-}
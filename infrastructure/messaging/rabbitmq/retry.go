@@ -0,0 +1,136 @@
+package rabbitmq
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/goodfoodcesi/auth-api/domain/messaging"
+	amqp "github.com/rabbitmq/amqp091-go"
+	"go.uber.org/zap"
+)
+
+// Headers set on retried and dead-lettered messages.
+const (
+	attemptsHeader         = "x-attempts"
+	lastErrorHeader        = "x-last-error"
+	originalQueueHeader    = "x-original-queue"
+	deadLetterReasonHeader = "x-dead-letter-reason"
+	deadLetteredAtHeader   = "x-dead-lettered-at"
+)
+
+// DeadLetterQueue is where the consumer of queue puts the messages it gave up
+// on.
+func DeadLetterQueue(queue string) string {
+	return queue + ".dlq"
+}
+
+func retryQueue(queue string, delay time.Duration) string {
+	return fmt.Sprintf("%s.retry.%s", queue, delay)
+}
+
+// retryQueues lists the retry and dead-letter queues p needs for queue. Each
+// retry waits in a queue whose TTL is the delay, then goes back to queue. The
+// retry queues are named after their delay, as the TTL of an existing queue
+// cannot be changed.
+func retryQueues(p messaging.RetryPolicy, queue string) []QueueConfig {
+	configs := []QueueConfig{{Name: DeadLetterQueue(queue), Durable: true}}
+	seen := make(map[time.Duration]bool)
+	for _, delay := range p.Delays {
+		if seen[delay] {
+			continue
+		}
+		seen[delay] = true
+		configs = append(configs, QueueConfig{
+			Name:    retryQueue(queue, delay),
+			Durable: true,
+			Args: amqp.Table{
+				"x-message-ttl":             delay.Milliseconds(),
+				"x-dead-letter-exchange":    "",
+				"x-dead-letter-routing-key": queue,
+			},
+		})
+	}
+	return configs
+}
+
+// fail moves msg, which could not be handled because of cause, to a retry
+// queue or to the dead-letter queue, then acks it.
+func (r *RabbitMQ) fail(c *consumer, msg amqp.Delivery, cause error) {
+	attempts := deliveryAttempts(msg) + 1
+	republish := deliveryPublishing(msg)
+	// The id and type of a message published without them come from its
+	// routing key, which is lost once it goes through another queue.
+	if envelope, err := envelopeFromDelivery(msg); err == nil {
+		if republish.MessageId == "" {
+			republish.MessageId = envelope.ID
+		}
+		if republish.Type == "" {
+			republish.Type = envelope.Type
+		}
+	}
+	republish.Headers[attemptsHeader] = int32(attempts)
+	republish.Headers[lastErrorHeader] = cause.Error()
+
+	target := DeadLetterQueue(c.queue)
+	if errors.Is(cause, messaging.ErrPermanent) || attempts >= c.options.Retry.MaxAttempts || len(c.options.Retry.Delays) == 0 {
+		republish.Headers[originalQueueHeader] = c.queue
+		republish.Headers[deadLetterReasonHeader] = cause.Error()
+		republish.Headers[deadLetteredAtHeader] = time.Now().UTC().Format(time.RFC3339)
+	} else {
+		target = retryQueue(c.queue, c.options.Retry.Delay(attempts))
+	}
+
+	logger := r.logger.With(
+		zap.String("queue", c.queue),
+		zap.String("message_id", republish.MessageId),
+		zap.Int("attempts", attempts),
+		zap.String("to", target),
+		zap.NamedError("cause", cause),
+	)
+
+	// The default exchange routes to the queue named by the routing key.
+	if err := r.publish(context.Background(), "", target, republish); err != nil {
+		logger.Error("failed to move message, requeueing it", zap.Error(err))
+		if err := msg.Nack(false, true); err != nil {
+			logger.Error("failed to nack message", zap.Error(err))
+		}
+		return
+	}
+	logger.Warn("failed to process message")
+
+	if err := msg.Ack(false); err != nil {
+		logger.Error("failed to ack message", zap.Error(err))
+	}
+}
+
+func deliveryAttempts(msg amqp.Delivery) int {
+	switch attempts := msg.Headers[attemptsHeader].(type) {
+	case int32:
+		return int(attempts)
+	case int64:
+		return int(attempts)
+	}
+	return 0
+}
+
+// deliveryPublishing copies msg so it can be published again unchanged.
+func deliveryPublishing(msg amqp.Delivery) amqp.Publishing {
+	headers := make(amqp.Table, len(msg.Headers)+5)
+	for k, v := range msg.Headers {
+		headers[k] = v
+	}
+
+	return amqp.Publishing{
+		Headers:       headers,
+		ContentType:   msg.ContentType,
+		DeliveryMode:  amqp.Persistent,
+		CorrelationId: msg.CorrelationId,
+		MessageId:     msg.MessageId,
+		Timestamp:     msg.Timestamp,
+		Type:          msg.Type,
+		AppId:         msg.AppId,
+		Body:          msg.Body,
+	}
+}
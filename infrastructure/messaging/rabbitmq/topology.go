@@ -0,0 +1,85 @@
+package rabbitmq
+
+// Topology is a set of exchanges and the queues bound to them.
+type Topology struct {
+	Exchanges []ExchangeConfig
+	Queues    []QueueConfig
+	Bindings  []BindingConfig
+}
+
+// AuthTopology is everything auth-api publishes to, with the queues of the
+// services consuming it. User events go to a topic exchange so each queue
+// binds only the keys it needs. Every key must be bound somewhere, as
+// publishing is mandatory.
+var AuthTopology = Topology{
+	Exchanges: []ExchangeConfig{
+		{Name: UserExchange, Type: TopicExchange, Durable: true},
+		{Name: NotificationExchange, Type: DirectExchange, Durable: true},
+		{Name: DriverExchange, Type: DirectExchange, Durable: true},
+	},
+	Queues: []QueueConfig{
+		{Name: ClientCreatedQueueNotificationAPI, Durable: true},
+		{Name: ClientCreatedQueueClientAPI, Durable: true},
+		{Name: SmsOtpQueueNotificationAPI, Durable: true},
+		{Name: UserStatusQueueOrderAPI, Durable: true},
+		{Name: UserProfileQueueClientAPI, Durable: true},
+		{Name: UserSecurityQueueNotificationAPI, Durable: true},
+		{Name: DriverQueueNotificationAPI, Durable: true},
+	},
+	Bindings: []BindingConfig{
+		{Queue: ClientCreatedQueueNotificationAPI, Exchange: UserExchange, RoutingKey: UserCreatedKey},
+		{Queue: ClientCreatedQueueClientAPI, Exchange: UserExchange, RoutingKey: UserCreatedKey},
+		{Queue: SmsOtpQueueNotificationAPI, Exchange: NotificationExchange, RoutingKey: SmsOtpKey},
+		{Queue: UserStatusQueueOrderAPI, Exchange: UserExchange, RoutingKey: UserSuspendedKey},
+		{Queue: UserStatusQueueOrderAPI, Exchange: UserExchange, RoutingKey: UserReactivatedKey},
+		{Queue: UserStatusQueueOrderAPI, Exchange: UserExchange, RoutingKey: UserDeletedKey},
+		{Queue: UserProfileQueueClientAPI, Exchange: UserExchange, RoutingKey: UserUpdatedKey},
+		{Queue: UserProfileQueueClientAPI, Exchange: UserExchange, RoutingKey: UserDeletedKey},
+		{Queue: UserSecurityQueueNotificationAPI, Exchange: UserExchange, RoutingKey: UserPasswordChangedKey},
+		{Queue: UserSecurityQueueNotificationAPI, Exchange: UserExchange, RoutingKey: UserRoleChangedKey},
+		{Queue: UserSecurityQueueNotificationAPI, Exchange: UserExchange, RoutingKey: UserLoggedInKey},
+		{Queue: DriverQueueNotificationAPI, Exchange: DriverExchange, RoutingKey: DriverApprovedKey},
+		{Queue: DriverQueueNotificationAPI, Exchange: DriverExchange, RoutingKey: DriverRejectedKey},
+	},
+}
+
+// ConsumerTopology is what auth-api consumes from the other services. Their
+// exchanges are declared too, with the settings of their owners, so the
+// queues can be bound whichever service starts first.
+var ConsumerTopology = Topology{
+	Exchanges: []ExchangeConfig{
+		{Name: RestaurantExchange, Type: TopicExchange, Durable: true},
+		{Name: PaymentExchange, Type: TopicExchange, Durable: true},
+		{Name: DeliveryExchange, Type: TopicExchange, Durable: true},
+	},
+	Queues: []QueueConfig{
+		{Name: RestaurantDeletedQueueAuthAPI, Durable: true},
+		{Name: CustomerFraudFlaggedQueueAuthAPI, Durable: true},
+		{Name: DriverContractEndedQueueAuthAPI, Durable: true},
+	},
+	Bindings: []BindingConfig{
+		{Queue: RestaurantDeletedQueueAuthAPI, Exchange: RestaurantExchange, RoutingKey: RestaurantDeletedKey},
+		{Queue: CustomerFraudFlaggedQueueAuthAPI, Exchange: PaymentExchange, RoutingKey: CustomerFraudFlaggedKey},
+		{Queue: DriverContractEndedQueueAuthAPI, Exchange: DeliveryExchange, RoutingKey: DriverContractEndedKey},
+	},
+}
+
+// DeclareTopology declares exchanges, then queues, then bindings.
+func (r *RabbitMQ) DeclareTopology(topology Topology) error {
+	for _, exchange := range topology.Exchanges {
+		if err := r.DeclareExchange(exchange); err != nil {
+			return err
+		}
+	}
+	for _, queue := range topology.Queues {
+		if err := r.DeclareQueue(queue); err != nil {
+			return err
+		}
+	}
+	for _, binding := range topology.Bindings {
+		if err := r.BindQueue(binding); err != nil {
+			return err
+		}
+	}
+	return nil
+}
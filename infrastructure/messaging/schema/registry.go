@@ -0,0 +1,83 @@
+// Package schema knows the payload of every event type and version, so that
+// a malformed event is refused before it is published and when it is
+// consumed.
+package schema
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/goodfoodcesi/auth-api/validator"
+)
+
+var (
+	ErrUnknownSchema  = errors.New("unknown event schema")
+	ErrInvalidPayload = errors.New("event payload does not match its schema")
+)
+
+type key struct {
+	eventType string
+	version   int
+}
+
+// Registry maps each event type and version to the struct its payload
+// decodes into. Schemas are registered at start-up, the registry is only
+// read afterwards.
+type Registry struct {
+	schemas   map[key]reflect.Type
+	latest    map[string]int
+	validator *validator.Validator
+}
+
+func NewRegistry() *Registry {
+	return &Registry{
+		schemas:   make(map[key]reflect.Type),
+		latest:    make(map[string]int),
+		validator: validator.NewValidator(),
+	}
+}
+
+// Register declares payload, a struct value, as the given version of
+// eventType. Its validate tags are checked on every payload of that version.
+func (r *Registry) Register(eventType string, version int, payload interface{}) {
+	t := reflect.TypeOf(payload)
+	if t == nil || t.Kind() != reflect.Struct {
+		panic(fmt.Sprintf("schema: payload of %s v%d must be a struct", eventType, version))
+	}
+	if _, ok := r.schemas[key{eventType, version}]; ok {
+		panic(fmt.Sprintf("schema: %s v%d registered twice", eventType, version))
+	}
+
+	r.schemas[key{eventType, version}] = t
+	if version > r.latest[eventType] {
+		r.latest[eventType] = version
+	}
+}
+
+// Latest returns the version new events of eventType are published with.
+func (r *Registry) Latest(eventType string) (int, error) {
+	version, ok := r.latest[eventType]
+	if !ok {
+		return 0, fmt.Errorf("%w: %s", ErrUnknownSchema, eventType)
+	}
+	return version, nil
+}
+
+// Validate decodes data as the given version of eventType and checks it.
+func (r *Registry) Validate(eventType string, version int, data []byte) error {
+	t, ok := r.schemas[key{eventType, version}]
+	if !ok {
+		return fmt.Errorf("%w: %s v%d", ErrUnknownSchema, eventType, version)
+	}
+
+	payload := reflect.New(t).Interface()
+	if err := json.Unmarshal(data, payload); err != nil {
+		return fmt.Errorf("%w: %s v%d: %w", ErrInvalidPayload, eventType, version, err)
+	}
+	if errs := r.validator.Validate(payload); errs != nil {
+		return fmt.Errorf("%w: %s v%d: %s failed on %s", ErrInvalidPayload, eventType, version, errs[0].Field, errs[0].Tag)
+	}
+	return nil
+}
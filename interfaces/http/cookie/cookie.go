@@ -0,0 +1,123 @@
+// Package cookie keeps browser sessions in HttpOnly cookies instead of
+// letting the web back office store tokens where scripts can read them.
+package cookie
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/goodfoodcesi/auth-api/infrastructure/jwt"
+)
+
+const (
+	AccessToken  = "gf_access_token"
+	RefreshToken = "gf_refresh_token"
+	// CSRF is readable by scripts on purpose: the app echoes it back in
+	// CSRFHeader, which a cross-site form cannot do.
+	CSRF       = "gf_csrf_token"
+	CSRFHeader = "X-CSRF-Token"
+
+	refreshPath = "/auth/refresh"
+)
+
+// Config is how session cookies are issued.
+type Config struct {
+	Domain     string
+	Secure     bool
+	SameSite   http.SameSite
+	AccessTTL  time.Duration
+	RefreshTTL time.Duration
+}
+
+// ParseSameSite reads COOKIE_SAMESITE-style values, defaulting to Strict.
+func ParseSameSite(value string) http.SameSite {
+	switch strings.ToLower(value) {
+	case "lax":
+		return http.SameSiteLaxMode
+	case "none":
+		return http.SameSiteNoneMode
+	default:
+		return http.SameSiteStrictMode
+	}
+}
+
+// Requested tells whether the client asked for a cookie session with
+// ?session=cookie rather than tokens in the response body.
+func Requested(r *http.Request) bool {
+	return r.URL.Query().Get("session") == "cookie"
+}
+
+// Start stores tokens in cookies and returns a new CSRF token, which is set
+// in its own cookie too.
+func (c Config) Start(w http.ResponseWriter, tokens *jwt.TokenPair) (string, error) {
+	csrf, err := c.SetCSRF(w)
+	if err != nil {
+		return "", err
+	}
+	http.SetCookie(w, c.cookie(AccessToken, tokens.AccessToken, "/", c.AccessTTL, true))
+	http.SetCookie(w, c.cookie(RefreshToken, tokens.RefreshToken, refreshPath, c.RefreshTTL, true))
+	return csrf, nil
+}
+
+// End removes the session cookies.
+func (c Config) End(w http.ResponseWriter) {
+	http.SetCookie(w, c.cookie(AccessToken, "", "/", -1, true))
+	http.SetCookie(w, c.cookie(RefreshToken, "", refreshPath, -1, true))
+	http.SetCookie(w, c.cookie(CSRF, "", "/", -1, false))
+}
+
+// SetCSRF issues a new double-submit CSRF token.
+func (c Config) SetCSRF(w http.ResponseWriter) (string, error) {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return "", err
+	}
+	token := base64.RawURLEncoding.EncodeToString(raw)
+	http.SetCookie(w, c.cookie(CSRF, token, "/", c.RefreshTTL, false))
+	return token, nil
+}
+
+func (c Config) cookie(name, value, path string, ttl time.Duration, httpOnly bool) *http.Cookie {
+	cookie := &http.Cookie{
+		Name:     name,
+		Value:    value,
+		Path:     path,
+		Domain:   c.Domain,
+		Secure:   c.Secure,
+		HttpOnly: httpOnly,
+		SameSite: c.SameSite,
+	}
+	if ttl < 0 {
+		cookie.MaxAge = -1
+	} else {
+		cookie.MaxAge = int(ttl.Seconds())
+	}
+	return cookie
+}
+
+// Value returns the value of cookie name, or an empty string.
+func Value(r *http.Request, name string) string {
+	cookie, err := r.Cookie(name)
+	if err != nil {
+		return ""
+	}
+	return cookie.Value
+}
+
+// ValidCSRF checks the double-submit token: the header must repeat the cookie.
+func ValidCSRF(r *http.Request) bool {
+	token := Value(r, CSRF)
+	if token == "" {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(token), []byte(r.Header.Get(CSRFHeader))) == 1
+}
+
+// Safe tells whether method cannot change state and so needs no CSRF token.
+func Safe(method string) bool {
+	return method == http.MethodGet || method == http.MethodHead || method == http.MethodOptions
+}
@@ -0,0 +1,60 @@
+package cookie_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/goodfoodcesi/auth-api/interfaces/http/cookie"
+)
+
+func TestValidCSRF(t *testing.T) {
+	tests := []struct {
+		name   string
+		cookie string
+		header string
+		want   bool
+	}{
+		{name: "header repeats the cookie", cookie: "token", header: "token", want: true},
+		{name: "header differs", cookie: "token", header: "other"},
+		{name: "no header", cookie: "token"},
+		{name: "no cookie", header: "token"},
+		{name: "neither"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, "/", nil)
+			if tt.cookie != "" {
+				r.AddCookie(&http.Cookie{Name: cookie.CSRF, Value: tt.cookie})
+			}
+			if tt.header != "" {
+				r.Header.Set(cookie.CSRFHeader, tt.header)
+			}
+
+			if got := cookie.ValidCSRF(r); got != tt.want {
+				t.Errorf("ValidCSRF = %t, want %t", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSetCSRFIssuesTheTokenItReturns(t *testing.T) {
+	w := httptest.NewRecorder()
+	token, err := cookie.Config{}.SetCSRF(w)
+	if err != nil {
+		t.Fatalf("SetCSRF: %v", err)
+	}
+
+	r := httptest.NewRequest(http.MethodPost, "/", nil)
+	for _, c := range w.Result().Cookies() {
+		if c.HttpOnly {
+			t.Errorf("cookie %s is HttpOnly, scripts must read it", c.Name)
+		}
+		r.AddCookie(c)
+	}
+	r.Header.Set(cookie.CSRFHeader, token)
+
+	if !cookie.ValidCSRF(r) {
+		t.Error("the returned token does not match the cookie")
+	}
+}
@@ -0,0 +1,151 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/goodfoodcesi/auth-api/domain/service"
+	"github.com/goodfoodcesi/auth-api/interfaces/http/response"
+	"github.com/goodfoodcesi/auth-api/validator"
+	"go.uber.org/zap"
+)
+
+type AddressHandler struct {
+	addressService *service.AddressService
+	validator      *validator.Validator
+	logger         *zap.Logger
+}
+
+func NewAddressHandler(addressService *service.AddressService, logger *zap.Logger) *AddressHandler {
+	return &AddressHandler{
+		addressService: addressService,
+		validator:      validator.NewValidator(),
+		logger:         logger,
+	}
+}
+
+func (h *AddressHandler) List(w http.ResponseWriter, r *http.Request) {
+	userID, err := currentUserID(r)
+	if err != nil {
+		h.logger.Error("invalid user ID format", zap.Error(err))
+		response.Error(w, http.StatusInternalServerError, "Internal Server Error", nil)
+		return
+	}
+
+	addresses, err := h.addressService.List(r.Context(), userID)
+	if err != nil {
+		h.logger.Error("failed to list addresses", zap.Error(err))
+		response.Error(w, http.StatusInternalServerError, "Failed to list addresses", nil)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, response.ToAddressesResponse(addresses))
+}
+
+func (h *AddressHandler) Create(w http.ResponseWriter, r *http.Request) {
+	input, ok := h.decodeInput(w, r)
+	if !ok {
+		return
+	}
+
+	userID, err := currentUserID(r)
+	if err != nil {
+		h.logger.Error("invalid user ID format", zap.Error(err))
+		response.Error(w, http.StatusInternalServerError, "Internal Server Error", nil)
+		return
+	}
+
+	address, err := h.addressService.Create(r.Context(), userID, input)
+	if err != nil {
+		h.logger.Error("failed to create address", zap.Error(err))
+		response.Error(w, http.StatusInternalServerError, "Failed to create address", nil)
+		return
+	}
+
+	response.JSON(w, http.StatusCreated, response.ToAddressResponse(address))
+}
+
+func (h *AddressHandler) Update(w http.ResponseWriter, r *http.Request) {
+	id, err := urlUUID(chi.URLParam(r, "addressID"))
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid address ID format", nil)
+		return
+	}
+
+	input, ok := h.decodeInput(w, r)
+	if !ok {
+		return
+	}
+
+	userID, err := currentUserID(r)
+	if err != nil {
+		h.logger.Error("invalid user ID format", zap.Error(err))
+		response.Error(w, http.StatusInternalServerError, "Internal Server Error", nil)
+		return
+	}
+
+	address, err := h.addressService.Update(r.Context(), userID, id, input)
+	if err != nil {
+		h.logger.Error("failed to update address", zap.Error(err))
+
+		if errors.Is(err, service.ErrAddressNotFound) {
+			response.Error(w, http.StatusNotFound, "Address not found", nil)
+			return
+		}
+
+		response.Error(w, http.StatusInternalServerError, "Failed to update address", nil)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, response.ToAddressResponse(address))
+}
+
+func (h *AddressHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	id, err := urlUUID(chi.URLParam(r, "addressID"))
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid address ID format", nil)
+		return
+	}
+
+	userID, err := currentUserID(r)
+	if err != nil {
+		h.logger.Error("invalid user ID format", zap.Error(err))
+		response.Error(w, http.StatusInternalServerError, "Internal Server Error", nil)
+		return
+	}
+
+	if err := h.addressService.Delete(r.Context(), userID, id); err != nil {
+		h.logger.Error("failed to delete address", zap.Error(err))
+
+		if errors.Is(err, service.ErrAddressNotFound) {
+			response.Error(w, http.StatusNotFound, "Address not found", nil)
+			return
+		}
+
+		response.Error(w, http.StatusInternalServerError, "Failed to delete address", nil)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *AddressHandler) decodeInput(w http.ResponseWriter, r *http.Request) (service.AddressInput, bool) {
+	var input service.AddressInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		h.logger.Error("failed to decode request body", zap.Error(err))
+		response.Error(w, http.StatusBadRequest, "Invalid request body", nil)
+		return input, false
+	}
+
+	if validationErrors := h.validator.Validate(input); validationErrors != nil {
+		h.logger.Error("validation failed", zap.Any("errors", validationErrors))
+		response.JSON(w, http.StatusBadRequest, map[string]interface{}{
+			"errors": validationErrors,
+		})
+		return input, false
+	}
+
+	return input, true
+}
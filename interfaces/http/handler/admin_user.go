@@ -0,0 +1,237 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/goodfoodcesi/auth-api/domain/service"
+	db "github.com/goodfoodcesi/auth-api/infrastructure/database/sqlc"
+	"github.com/goodfoodcesi/auth-api/interfaces/http/response"
+	"github.com/goodfoodcesi/auth-api/validator"
+	"go.uber.org/zap"
+)
+
+type AdminUserHandler struct {
+	userService *service.UserService
+	validator   *validator.Validator
+	logger      *zap.Logger
+}
+
+func NewAdminUserHandler(userService *service.UserService, logger *zap.Logger) *AdminUserHandler {
+	return &AdminUserHandler{
+		userService: userService,
+		validator:   validator.NewValidator(),
+		logger:      logger,
+	}
+}
+
+func (h *AdminUserHandler) List(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	input := service.ListUsersInput{
+		Role:   query.Get("role"),
+		Status: query.Get("status"),
+		Search: query.Get("q"),
+		Sort:   query.Get("sort"),
+		Cursor: query.Get("cursor"),
+	}
+
+	if limit := query.Get("limit"); limit != "" {
+		value, err := strconv.Atoi(limit)
+		if err != nil {
+			response.Error(w, http.StatusBadRequest, "Invalid limit", nil)
+			return
+		}
+		input.Limit = value
+	}
+	var err error
+	if input.CreatedAfter, err = timeQueryParam(r, "created_after"); err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid created_after, expected RFC 3339", nil)
+		return
+	}
+	if input.CreatedBefore, err = timeQueryParam(r, "created_before"); err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid created_before, expected RFC 3339", nil)
+		return
+	}
+
+	if validationErrors := h.validator.Validate(input); validationErrors != nil {
+		h.logger.Error("validation failed", zap.Any("errors", validationErrors))
+		response.JSON(w, http.StatusBadRequest, map[string]interface{}{
+			"errors": validationErrors,
+		})
+		return
+	}
+
+	page, err := h.userService.ListUsers(r.Context(), input)
+	if err != nil {
+		h.logger.Error("failed to list users", zap.Error(err))
+
+		if errors.Is(err, service.ErrInvalidCursor) {
+			response.Error(w, http.StatusBadRequest, "Invalid cursor", nil)
+			return
+		}
+
+		response.Error(w, http.StatusInternalServerError, "Failed to list users", nil)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, response.ToUserListResponse(page.Users, page.NextCursor))
+}
+
+func (h *AdminUserHandler) Get(w http.ResponseWriter, r *http.Request) {
+	id, err := urlUUID(chi.URLParam(r, "userID"))
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid user ID format", nil)
+		return
+	}
+
+	user, _ := h.userService.GetByID(r.Context(), id)
+	if user == nil {
+		response.Error(w, http.StatusNotFound, "User not found", nil)
+		return
+	}
+
+	setETag(w, user.Version)
+	response.JSON(w, http.StatusOK, response.ToUserResponse(user))
+}
+
+func (h *AdminUserHandler) UpdateRole(w http.ResponseWriter, r *http.Request) {
+	id, err := urlUUID(chi.URLParam(r, "userID"))
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid user ID format", nil)
+		return
+	}
+
+	version, present, ok := ifMatchVersion(r)
+	if !present {
+		response.Error(w, http.StatusPreconditionRequired, "If-Match header is required", nil)
+		return
+	}
+	if !ok {
+		response.Error(w, http.StatusPreconditionFailed, "User has been modified", nil)
+		return
+	}
+
+	var input service.ChangeRoleInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		h.logger.Error("failed to decode request body", zap.Error(err))
+		response.Error(w, http.StatusBadRequest, "Invalid request body", nil)
+		return
+	}
+
+	if validationErrors := h.validator.Validate(input); validationErrors != nil {
+		h.logger.Error("validation failed", zap.Any("errors", validationErrors))
+		response.JSON(w, http.StatusBadRequest, map[string]interface{}{
+			"errors": validationErrors,
+		})
+		return
+	}
+
+	adminID, err := currentUserID(r)
+	if err != nil {
+		h.logger.Error("invalid user ID format", zap.Error(err))
+		response.Error(w, http.StatusInternalServerError, "Internal Server Error", nil)
+		return
+	}
+
+	user, err := h.userService.ChangeRole(r.Context(), adminID, id, version, input)
+	if err != nil {
+		h.logger.Error("failed to change user role", zap.Error(err))
+		h.writeError(w, err)
+		return
+	}
+
+	setETag(w, user.Version)
+	response.JSON(w, http.StatusOK, response.ToUserResponse(user))
+}
+
+func (h *AdminUserHandler) Suspend(w http.ResponseWriter, r *http.Request) {
+	h.changeStatus(w, r, db.UserStatusSuspended)
+}
+
+func (h *AdminUserHandler) Ban(w http.ResponseWriter, r *http.Request) {
+	h.changeStatus(w, r, db.UserStatusBanned)
+}
+
+func (h *AdminUserHandler) Reactivate(w http.ResponseWriter, r *http.Request) {
+	h.changeStatus(w, r, db.UserStatusActive)
+}
+
+// Delete is a soft delete: the account is kept with the deleted status.
+func (h *AdminUserHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	h.changeStatus(w, r, db.UserStatusDeleted)
+}
+
+func (h *AdminUserHandler) changeStatus(w http.ResponseWriter, r *http.Request, status db.UserStatus) {
+	id, err := urlUUID(chi.URLParam(r, "userID"))
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid user ID format", nil)
+		return
+	}
+
+	// The reason is optional, so is the body.
+	var input service.ChangeStatusInput
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+			h.logger.Error("failed to decode request body", zap.Error(err))
+			response.Error(w, http.StatusBadRequest, "Invalid request body", nil)
+			return
+		}
+	}
+
+	if validationErrors := h.validator.Validate(input); validationErrors != nil {
+		h.logger.Error("validation failed", zap.Any("errors", validationErrors))
+		response.JSON(w, http.StatusBadRequest, map[string]interface{}{
+			"errors": validationErrors,
+		})
+		return
+	}
+
+	adminID, err := currentUserID(r)
+	if err != nil {
+		h.logger.Error("invalid user ID format", zap.Error(err))
+		response.Error(w, http.StatusInternalServerError, "Internal Server Error", nil)
+		return
+	}
+
+	user, err := h.userService.ChangeStatus(r.Context(), adminID, id, status, input)
+	if err != nil {
+		h.logger.Error("failed to change user status", zap.Error(err), zap.String("status", string(status)))
+		h.writeError(w, err)
+		return
+	}
+
+	setETag(w, user.Version)
+	response.JSON(w, http.StatusOK, response.ToUserResponse(user))
+}
+
+// timeQueryParam parses an optional RFC 3339 query parameter.
+func timeQueryParam(r *http.Request, name string) (*time.Time, error) {
+	value := r.URL.Query().Get(name)
+	if value == "" {
+		return nil, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+func (h *AdminUserHandler) writeError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, service.ErrUserNotFound):
+		response.Error(w, http.StatusNotFound, "User not found", nil)
+	case errors.Is(err, service.ErrStaleVersion):
+		response.Error(w, http.StatusPreconditionFailed, "User has been modified", nil)
+	case errors.Is(err, service.ErrSelfManagement):
+		response.Error(w, http.StatusForbidden, err.Error(), nil)
+	case errors.Is(err, service.ErrInvalidStatusTransition):
+		response.Error(w, http.StatusConflict, err.Error(), nil)
+	default:
+		response.Error(w, http.StatusInternalServerError, "Internal Server Error", nil)
+	}
+}
@@ -0,0 +1,125 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/goodfoodcesi/auth-api/domain/service"
+	"github.com/goodfoodcesi/auth-api/interfaces/http/response"
+	"github.com/goodfoodcesi/auth-api/validator"
+	"go.uber.org/zap"
+)
+
+type APIKeyHandler struct {
+	apiKeyService *service.APIKeyService
+	validator     *validator.Validator
+	logger        *zap.Logger
+}
+
+func NewAPIKeyHandler(apiKeyService *service.APIKeyService, logger *zap.Logger) *APIKeyHandler {
+	return &APIKeyHandler{
+		apiKeyService: apiKeyService,
+		validator:     validator.NewValidator(),
+		logger:        logger,
+	}
+}
+
+func (h *APIKeyHandler) List(w http.ResponseWriter, r *http.Request) {
+	restaurantID, err := urlUUID(chi.URLParam(r, "restaurantID"))
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid restaurant ID format", nil)
+		return
+	}
+
+	actor, ok := currentActor(w, r, h.logger)
+	if !ok {
+		return
+	}
+
+	keys, err := h.apiKeyService.List(r.Context(), actor, restaurantID)
+	if err != nil {
+		h.logger.Error("failed to list api keys", zap.Error(err))
+		h.writeError(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, response.ToAPIKeysResponse(keys))
+}
+
+func (h *APIKeyHandler) Create(w http.ResponseWriter, r *http.Request) {
+	restaurantID, err := urlUUID(chi.URLParam(r, "restaurantID"))
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid restaurant ID format", nil)
+		return
+	}
+
+	var input service.CreateAPIKeyInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		h.logger.Error("failed to decode request body", zap.Error(err))
+		response.Error(w, http.StatusBadRequest, "Invalid request body", nil)
+		return
+	}
+
+	if validationErrors := h.validator.Validate(input); validationErrors != nil {
+		h.logger.Error("validation failed", zap.Any("errors", validationErrors))
+		response.JSON(w, http.StatusBadRequest, map[string]interface{}{
+			"errors": validationErrors,
+		})
+		return
+	}
+
+	actor, ok := currentActor(w, r, h.logger)
+	if !ok {
+		return
+	}
+
+	key, value, err := h.apiKeyService.Create(r.Context(), actor, restaurantID, input)
+	if err != nil {
+		h.logger.Error("failed to create api key", zap.Error(err))
+		h.writeError(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusCreated, response.ToAPIKeyResponse(key, value))
+}
+
+func (h *APIKeyHandler) Revoke(w http.ResponseWriter, r *http.Request) {
+	restaurantID, err := urlUUID(chi.URLParam(r, "restaurantID"))
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid restaurant ID format", nil)
+		return
+	}
+	keyID, err := urlUUID(chi.URLParam(r, "keyID"))
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid API key ID format", nil)
+		return
+	}
+
+	actor, ok := currentActor(w, r, h.logger)
+	if !ok {
+		return
+	}
+
+	if err := h.apiKeyService.Revoke(r.Context(), actor, restaurantID, keyID); err != nil {
+		h.logger.Error("failed to revoke api key", zap.Error(err))
+		h.writeError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *APIKeyHandler) writeError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, service.ErrAPIKeyNotFound):
+		response.Error(w, http.StatusNotFound, "API key not found", nil)
+	case errors.Is(err, service.ErrAPIKeyForbidden), errors.Is(err, service.ErrAPIKeyScope):
+		response.Error(w, http.StatusForbidden, err.Error(), nil)
+	case errors.Is(err, service.ErrInvalidIPAllowlist):
+		response.Error(w, http.StatusUnprocessableEntity, err.Error(), nil)
+	default:
+		response.Error(w, http.StatusInternalServerError, "Internal Server Error", nil)
+	}
+}
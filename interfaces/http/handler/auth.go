@@ -2,7 +2,10 @@ package handler
 
 import (
 	"encoding/json"
+	"errors"
 	"github.com/goodfoodcesi/auth-api/domain/service"
+	"github.com/goodfoodcesi/auth-api/infrastructure/jwt"
+	"github.com/goodfoodcesi/auth-api/interfaces/http/cookie"
 	"github.com/goodfoodcesi/auth-api/interfaces/http/response"
 	"go.uber.org/zap"
 	"net/http"
@@ -10,15 +13,30 @@ import (
 
 type AuthHandler struct {
 	authService *service.AuthService
+	cookies     cookie.Config
 	logger      *zap.Logger
 }
 
+func NewAuthHandler(authService *service.AuthService, cookies cookie.Config, logger *zap.Logger) *AuthHandler {
+	return &AuthHandler{
+		authService: authService,
+		cookies:     cookies,
+		logger:      logger,
+	}
+}
+
 func (h *AuthHandler) RefreshToken(w http.ResponseWriter, r *http.Request) {
 	var input struct {
 		RefreshToken string `json:"refresh_token" validate:"required"`
 	}
 
-	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+	if cookie.Requested(r) {
+		if !cookie.ValidCSRF(r) {
+			response.Error(w, http.StatusForbidden, "Invalid CSRF token", nil)
+			return
+		}
+		input.RefreshToken = cookie.Value(r, cookie.RefreshToken)
+	} else if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
 		response.Error(w, http.StatusBadRequest, "Invalid request body", nil)
 		return
 	}
@@ -26,9 +44,96 @@ func (h *AuthHandler) RefreshToken(w http.ResponseWriter, r *http.Request) {
 	tokens, err := h.authService.RefreshToken(r.Context(), input.RefreshToken)
 	if err != nil {
 		h.logger.Error("failed to refresh token", zap.Error(err))
+
+		if errors.Is(err, service.ErrAccountInactive) {
+			response.Error(w, http.StatusForbidden, "Account is not active", nil)
+			return
+		}
+		if errors.Is(err, service.ErrNotRestaurantMember) {
+			response.Error(w, http.StatusForbidden, "Not a member of this restaurant", nil)
+			return
+		}
+
 		response.Error(w, http.StatusUnauthorized, "Invalid refresh token", nil)
 		return
 	}
 
-	response.JSON(w, http.StatusOK, tokens)
+	writeTokens(w, r, h.cookies, tokens, h.logger)
+}
+
+func (h *AuthHandler) SwitchRestaurant(w http.ResponseWriter, r *http.Request) {
+	var input struct {
+		RestaurantID string `json:"restaurant_id"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid request body", nil)
+		return
+	}
+
+	restaurantID, err := urlUUID(input.RestaurantID)
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid restaurant ID format", nil)
+		return
+	}
+
+	userID, err := currentUserID(r)
+	if err != nil {
+		h.logger.Error("invalid user ID format", zap.Error(err))
+		response.Error(w, http.StatusInternalServerError, "Internal Server Error", nil)
+		return
+	}
+
+	tokens, err := h.authService.SwitchRestaurant(r.Context(), userID, restaurantID)
+	if err != nil {
+		h.logger.Error("failed to switch restaurant", zap.Error(err))
+
+		switch {
+		case errors.Is(err, service.ErrNotRestaurantMember):
+			response.Error(w, http.StatusForbidden, "Not a member of this restaurant", nil)
+		case errors.Is(err, service.ErrAccountInactive):
+			response.Error(w, http.StatusForbidden, "Account is not active", nil)
+		default:
+			response.Error(w, http.StatusInternalServerError, "Internal Server Error", nil)
+		}
+		return
+	}
+
+	writeTokens(w, r, h.cookies, tokens, h.logger)
+}
+
+// CSRF issues a new double-submit token for cookie sessions.
+func (h *AuthHandler) CSRF(w http.ResponseWriter, r *http.Request) {
+	token, err := h.cookies.SetCSRF(w)
+	if err != nil {
+		h.logger.Error("failed to generate csrf token", zap.Error(err))
+		response.Error(w, http.StatusInternalServerError, "Internal Server Error", nil)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, map[string]string{"csrf_token": token})
+}
+
+// Logout ends a cookie session. Bearer tokens simply expire.
+func (h *AuthHandler) Logout(w http.ResponseWriter, r *http.Request) {
+	h.cookies.End(w)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// writeTokens answers with the token pair, or keeps it in cookies when the
+// client asked for a cookie session.
+func writeTokens(w http.ResponseWriter, r *http.Request, cookies cookie.Config, tokens *jwt.TokenPair, logger *zap.Logger) {
+	if !cookie.Requested(r) {
+		response.JSON(w, http.StatusOK, tokens)
+		return
+	}
+
+	csrf, err := cookies.Start(w, tokens)
+	if err != nil {
+		logger.Error("failed to start cookie session", zap.Error(err))
+		response.Error(w, http.StatusInternalServerError, "Internal Server Error", nil)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, map[string]string{"csrf_token": csrf})
 }
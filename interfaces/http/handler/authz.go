@@ -0,0 +1,108 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/goodfoodcesi/auth-api/authz"
+	"github.com/goodfoodcesi/auth-api/domain/auth"
+	"github.com/goodfoodcesi/auth-api/infrastructure/jwt"
+	"github.com/goodfoodcesi/auth-api/interfaces/http/response"
+	"github.com/goodfoodcesi/auth-api/validator"
+	"go.uber.org/zap"
+)
+
+// AccountChecker tells whether the account behind a valid token may still use
+// the API, as the auth middleware does for requests made to this one.
+type AccountChecker interface {
+	IsActive(ctx context.Context, userID string, issuedAt time.Time) (bool, error)
+}
+
+type AuthzHandler struct {
+	engine       *authz.Engine
+	tokenManager *jwt.TokenManager
+	accounts     AccountChecker
+	validator    *validator.Validator
+	logger       *zap.Logger
+}
+
+// CheckInput is a decision request from another service. The subject is the
+// user's access token, never attributes the caller could make up.
+type CheckInput struct {
+	Token    string `json:"token" validate:"required"`
+	Action   string `json:"action" validate:"required,max=100"`
+	Resource struct {
+		Type       string                 `json:"type" validate:"required,max=100"`
+		ID         string                 `json:"id" validate:"max=100"`
+		Attributes map[string]interface{} `json:"attributes"`
+	} `json:"resource"`
+	Environment struct {
+		IP string `json:"ip" validate:"omitempty,ip"`
+	} `json:"environment"`
+}
+
+func NewAuthzHandler(engine *authz.Engine, tokenManager *jwt.TokenManager, accounts AccountChecker, logger *zap.Logger) *AuthzHandler {
+	return &AuthzHandler{
+		engine:       engine,
+		tokenManager: tokenManager,
+		accounts:     accounts,
+		validator:    validator.NewValidator(),
+		logger:       logger,
+	}
+}
+
+func (h *AuthzHandler) Check(w http.ResponseWriter, r *http.Request) {
+	var input CheckInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		h.logger.Error("failed to decode request body", zap.Error(err))
+		response.Error(w, http.StatusBadRequest, "Invalid request body", nil)
+		return
+	}
+
+	if validationErrors := h.validator.Validate(input); validationErrors != nil {
+		h.logger.Error("validation failed", zap.Any("errors", validationErrors))
+		response.JSON(w, http.StatusBadRequest, map[string]interface{}{
+			"errors": validationErrors,
+		})
+		return
+	}
+
+	claims, err := h.tokenManager.ValidateToken(input.Token, false)
+	if err != nil {
+		h.logger.Error("invalid token", zap.Error(err))
+		response.Error(w, http.StatusUnauthorized, "Invalid token", nil)
+		return
+	}
+
+	principal := auth.FromClaims(claims)
+	active, err := h.accounts.IsActive(r.Context(), principal.Subject, principal.IssuedAt)
+	if err != nil {
+		h.logger.Error("failed to check account status", zap.Error(err))
+		response.Error(w, http.StatusUnauthorized, "Invalid token", nil)
+		return
+	}
+	if !active {
+		// A suspended user, or one whose sessions were revoked, is refused
+		// whatever the policy says.
+		response.JSON(w, http.StatusOK, authz.Decision{Allowed: false, Effect: authz.Deny, Reason: "account is not active"})
+		return
+	}
+
+	decision := h.engine.Decide(authz.Request{
+		Subject: authz.SubjectFromPrincipal(principal),
+		Action:  input.Action,
+		Resource: authz.Resource{
+			Type:       input.Resource.Type,
+			ID:         input.Resource.ID,
+			Attributes: input.Resource.Attributes,
+		},
+		Environment: authz.Environment{
+			IP:   input.Environment.IP,
+			Time: time.Now(),
+		},
+	})
+
+	response.JSON(w, http.StatusOK, decision)
+}
@@ -0,0 +1,92 @@
+package handler_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/goodfoodcesi/auth-api/authz"
+	"github.com/goodfoodcesi/auth-api/infrastructure/database/sqlc"
+	"github.com/goodfoodcesi/auth-api/infrastructure/jwt"
+	"github.com/goodfoodcesi/auth-api/interfaces/http/handler"
+	"go.uber.org/zap"
+)
+
+// accounts reports every account as active unless listed in inactive.
+type accounts struct {
+	inactive map[string]bool
+}
+
+func (a accounts) IsActive(_ context.Context, userID string, _ time.Time) (bool, error) {
+	return !a.inactive[userID], nil
+}
+
+func TestAuthzCheck(t *testing.T) {
+	tokens := jwt.NewTokenManager("access-secret", "refresh-secret", time.Hour, 24*time.Hour)
+	manager := jwt.Subject{UserID: "u1", Role: db.UserRoleManager, RestaurantID: "r1", RestaurantRole: db.MembershipRoleOwner}
+	pair, err := tokens.GenerateTokenPair(manager)
+	if err != nil {
+		t.Fatalf("GenerateTokenPair: %v", err)
+	}
+	impersonation, _, err := tokens.GenerateImpersonationToken(manager, "admin", time.Minute)
+	if err != nil {
+		t.Fatalf("GenerateImpersonationToken: %v", err)
+	}
+	suspendedManager := manager
+	suspendedManager.UserID = "u2"
+	suspended, err := tokens.GenerateTokenPair(suspendedManager)
+	if err != nil {
+		t.Fatalf("GenerateTokenPair: %v", err)
+	}
+
+	policy, err := authz.LoadPolicy("")
+	if err != nil {
+		t.Fatalf("LoadPolicy: %v", err)
+	}
+	h := handler.NewAuthzHandler(authz.NewEngine(policy, nil), tokens, accounts{inactive: map[string]bool{"u2": true}}, zap.NewNop())
+
+	tests := []struct {
+		name     string
+		token    string
+		action   string
+		want     int
+		allowed  bool
+		wantRule string
+	}{
+		{name: "manager manages their restaurant", token: pair.AccessToken, action: "members:manage", want: http.StatusOK, allowed: true, wantRule: "manager-own-restaurant"},
+		{name: "impersonated manager lists members", token: impersonation, action: "members:list", want: http.StatusOK, allowed: true, wantRule: "manager-own-restaurant"},
+		{name: "impersonated manager manages members", token: impersonation, action: "members:manage", want: http.StatusOK, wantRule: authz.ImpersonationRuleID},
+		{name: "impersonated manager updates the restaurant", token: impersonation, action: "restaurant:update", want: http.StatusOK, wantRule: authz.ImpersonationRuleID},
+		{name: "suspended manager", token: suspended.AccessToken, action: "members:list", want: http.StatusOK},
+		{name: "refresh token", token: pair.RefreshToken, action: "members:list", want: http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body, _ := json.Marshal(map[string]interface{}{
+				"token":    tt.token,
+				"action":   tt.action,
+				"resource": map[string]interface{}{"type": "restaurant", "attributes": map[string]interface{}{"restaurant_id": "r1"}},
+			})
+			w := httptest.NewRecorder()
+			h.Check(w, httptest.NewRequest(http.MethodPost, "/authz/check", bytes.NewReader(body)))
+
+			if w.Code != tt.want {
+				t.Fatalf("status = %d, want %d", w.Code, tt.want)
+			}
+			if w.Code != http.StatusOK {
+				return
+			}
+			var decision authz.Decision
+			if err := json.NewDecoder(w.Body).Decode(&decision); err != nil {
+				t.Fatalf("decode decision: %v", err)
+			}
+			if decision.Allowed != tt.allowed || decision.RuleID != tt.wantRule {
+				t.Errorf("decision = %+v, want allowed %t by %q", decision, tt.allowed, tt.wantRule)
+			}
+		})
+	}
+}
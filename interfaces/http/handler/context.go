@@ -0,0 +1,73 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/goodfoodcesi/auth-api/domain/auth"
+	"github.com/goodfoodcesi/auth-api/domain/service"
+	db "github.com/goodfoodcesi/auth-api/infrastructure/database/sqlc"
+	"github.com/goodfoodcesi/auth-api/interfaces/http/response"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/zap"
+)
+
+var errNoPrincipal = errors.New("request is not authenticated")
+
+// currentUserID returns the authenticated user set by AuthMiddleware.
+func currentUserID(r *http.Request) (pgtype.UUID, error) {
+	principal, ok := auth.FromContext(r.Context())
+	if !ok {
+		return pgtype.UUID{}, errNoPrincipal
+	}
+	id, err := uuid.Parse(principal.Subject)
+	if err != nil {
+		return pgtype.UUID{}, err
+	}
+	return pgtype.UUID{Bytes: id, Valid: true}, nil
+}
+
+// urlUUID parses a uuid path parameter.
+func urlUUID(value string) (pgtype.UUID, error) {
+	id, err := uuid.Parse(value)
+	if err != nil {
+		return pgtype.UUID{}, err
+	}
+	return pgtype.UUID{Bytes: id, Valid: true}, nil
+}
+
+// currentUserRole returns the role of the authenticated user set by AuthMiddleware.
+func currentUserRole(r *http.Request) db.UserRole {
+	principal, ok := auth.FromContext(r.Context())
+	if !ok {
+		return ""
+	}
+	return principal.Role
+}
+
+// currentImpersonator returns the admin behind an impersonation token, or an
+// empty string for regular tokens.
+func currentImpersonator(r *http.Request) string {
+	principal, ok := auth.FromContext(r.Context())
+	if !ok || !principal.Impersonated() {
+		return ""
+	}
+	return principal.Actor
+}
+
+// currentActor returns the authenticated user as a service.Actor, writing the
+// error response itself when the context does not hold a valid user.
+func currentActor(w http.ResponseWriter, r *http.Request, logger *zap.Logger) (service.Actor, bool) {
+	id, err := currentUserID(r)
+	if err != nil {
+		logger.Error("invalid user ID format", zap.Error(err))
+		response.Error(w, http.StatusInternalServerError, "Internal Server Error", nil)
+		return service.Actor{}, false
+	}
+	actor := service.Actor{ID: id, Role: currentUserRole(r)}
+	if principal, _ := auth.FromContext(r.Context()); principal.Kind == auth.KindAPIKey {
+		actor.Scopes = append([]string{}, principal.Permissions...)
+	}
+	return actor, true
+}
@@ -0,0 +1,188 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/goodfoodcesi/auth-api/domain/service"
+	db "github.com/goodfoodcesi/auth-api/infrastructure/database/sqlc"
+	"github.com/goodfoodcesi/auth-api/interfaces/http/response"
+	"github.com/goodfoodcesi/auth-api/validator"
+	"go.uber.org/zap"
+)
+
+type DriverHandler struct {
+	driverService *service.DriverService
+	validator     *validator.Validator
+	logger        *zap.Logger
+}
+
+func NewDriverHandler(driverService *service.DriverService, logger *zap.Logger) *DriverHandler {
+	return &DriverHandler{
+		driverService: driverService,
+		validator:     validator.NewValidator(),
+		logger:        logger,
+	}
+}
+
+func (h *DriverHandler) GetMine(w http.ResponseWriter, r *http.Request) {
+	userID, err := currentUserID(r)
+	if err != nil {
+		h.logger.Error("invalid user ID format", zap.Error(err))
+		response.Error(w, http.StatusInternalServerError, "Internal Server Error", nil)
+		return
+	}
+
+	application, err := h.driverService.GetForUser(r.Context(), userID)
+	if err != nil {
+		h.logger.Error("failed to get driver application", zap.Error(err))
+		h.writeError(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, response.ToDriverApplicationResponse(application))
+}
+
+// Submit files or resubmits the application of the authenticated driver.
+func (h *DriverHandler) Submit(w http.ResponseWriter, r *http.Request) {
+	var input service.DriverApplicationInput
+	if !h.decode(w, r, &input) {
+		return
+	}
+
+	userID, err := currentUserID(r)
+	if err != nil {
+		h.logger.Error("invalid user ID format", zap.Error(err))
+		response.Error(w, http.StatusInternalServerError, "Internal Server Error", nil)
+		return
+	}
+
+	application, err := h.driverService.Submit(r.Context(), userID, currentUserRole(r), input)
+	if err != nil {
+		h.logger.Error("failed to submit driver application", zap.Error(err))
+		h.writeError(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, response.ToDriverApplicationResponse(application))
+}
+
+// List is the review queue, or every application in the status query parameter.
+func (h *DriverHandler) List(w http.ResponseWriter, r *http.Request) {
+	var status *db.DriverApplicationStatus
+	if value := r.URL.Query().Get("status"); value != "" {
+		s := db.DriverApplicationStatus(value)
+		switch s {
+		case db.DriverApplicationStatusSubmitted, db.DriverApplicationStatusUnderReview,
+			db.DriverApplicationStatusApproved, db.DriverApplicationStatusRejected:
+			status = &s
+		default:
+			response.Error(w, http.StatusBadRequest, "Invalid status", nil)
+			return
+		}
+	}
+
+	applications, err := h.driverService.List(r.Context(), status)
+	if err != nil {
+		h.logger.Error("failed to list driver applications", zap.Error(err))
+		response.Error(w, http.StatusInternalServerError, "Failed to list driver applications", nil)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, response.ToDriverApplicationsResponse(applications))
+}
+
+func (h *DriverHandler) Get(w http.ResponseWriter, r *http.Request) {
+	id, err := urlUUID(chi.URLParam(r, "applicationID"))
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid application ID format", nil)
+		return
+	}
+
+	application, err := h.driverService.Get(r.Context(), id)
+	if err != nil {
+		h.logger.Error("failed to get driver application", zap.Error(err))
+		h.writeError(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, response.ToDriverApplicationResponse(application))
+}
+
+func (h *DriverHandler) StartReview(w http.ResponseWriter, r *http.Request) {
+	h.review(w, r, db.DriverApplicationStatusUnderReview)
+}
+
+func (h *DriverHandler) Approve(w http.ResponseWriter, r *http.Request) {
+	h.review(w, r, db.DriverApplicationStatusApproved)
+}
+
+func (h *DriverHandler) Reject(w http.ResponseWriter, r *http.Request) {
+	h.review(w, r, db.DriverApplicationStatusRejected)
+}
+
+func (h *DriverHandler) review(w http.ResponseWriter, r *http.Request, status db.DriverApplicationStatus) {
+	id, err := urlUUID(chi.URLParam(r, "applicationID"))
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid application ID format", nil)
+		return
+	}
+
+	// Notes are only required to reject, so the body is optional.
+	var input service.ReviewDriverInput
+	if r.ContentLength != 0 {
+		if !h.decode(w, r, &input) {
+			return
+		}
+	}
+
+	reviewerID, err := currentUserID(r)
+	if err != nil {
+		h.logger.Error("invalid user ID format", zap.Error(err))
+		response.Error(w, http.StatusInternalServerError, "Internal Server Error", nil)
+		return
+	}
+
+	application, err := h.driverService.Review(r.Context(), reviewerID, id, status, input)
+	if err != nil {
+		h.logger.Error("failed to review driver application", zap.Error(err), zap.String("status", string(status)))
+		h.writeError(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, response.ToDriverApplicationResponse(application))
+}
+
+func (h *DriverHandler) decode(w http.ResponseWriter, r *http.Request, input interface{}) bool {
+	if err := json.NewDecoder(r.Body).Decode(input); err != nil {
+		h.logger.Error("failed to decode request body", zap.Error(err))
+		response.Error(w, http.StatusBadRequest, "Invalid request body", nil)
+		return false
+	}
+
+	if validationErrors := h.validator.Validate(input); validationErrors != nil {
+		h.logger.Error("validation failed", zap.Any("errors", validationErrors))
+		response.JSON(w, http.StatusBadRequest, map[string]interface{}{
+			"errors": validationErrors,
+		})
+		return false
+	}
+	return true
+}
+
+func (h *DriverHandler) writeError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, service.ErrDriverApplicationNotFound):
+		response.Error(w, http.StatusNotFound, "Driver application not found", nil)
+	case errors.Is(err, service.ErrNotDriver):
+		response.Error(w, http.StatusForbidden, err.Error(), nil)
+	case errors.Is(err, service.ErrInvalidReviewTransition):
+		response.Error(w, http.StatusConflict, err.Error(), nil)
+	case errors.Is(err, service.ErrRejectionNotesRequired):
+		response.Error(w, http.StatusBadRequest, err.Error(), nil)
+	default:
+		response.Error(w, http.StatusInternalServerError, "Internal Server Error", nil)
+	}
+}
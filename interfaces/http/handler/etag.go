@@ -0,0 +1,34 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// setETag exposes the user version so clients can send it back in If-Match.
+func setETag(w http.ResponseWriter, version int32) {
+	w.Header().Set("ETag", strconv.Quote(strconv.FormatInt(int64(version), 10)))
+}
+
+// ifMatchVersion reads the version from the If-Match header. present is false
+// when the header is missing; ok is false when it does not carry a version.
+func ifMatchVersion(r *http.Request) (version int32, present bool, ok bool) {
+	header := strings.TrimSpace(r.Header.Get("If-Match"))
+	if header == "" {
+		return 0, false, false
+	}
+
+	tag := strings.TrimPrefix(header, "W/")
+	unquoted, err := strconv.Unquote(tag)
+	if err != nil {
+		return 0, true, false
+	}
+
+	v, err := strconv.ParseInt(unquoted, 10, 32)
+	if err != nil {
+		return 0, true, false
+	}
+
+	return int32(v), true, true
+}
@@ -0,0 +1,46 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/goodfoodcesi/auth-api/interfaces/http/response"
+	"go.uber.org/zap"
+)
+
+// ReadinessCheck fails while a dependency cannot serve requests.
+type ReadinessCheck func(ctx context.Context) error
+
+type HealthHandler struct {
+	checks map[string]ReadinessCheck
+	logger *zap.Logger
+}
+
+func NewHealthHandler(checks map[string]ReadinessCheck, logger *zap.Logger) *HealthHandler {
+	return &HealthHandler{
+		checks: checks,
+		logger: logger,
+	}
+}
+
+// Ready reports the state of every dependency, and 503 when one is down so
+// the instance is taken out of the load balancer.
+func (h *HealthHandler) Ready(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 2*time.Second)
+	defer cancel()
+
+	status := http.StatusOK
+	states := make(map[string]string, len(h.checks))
+	for name, check := range h.checks {
+		if err := check(ctx); err != nil {
+			h.logger.Warn("readiness check failed", zap.String("dependency", name), zap.Error(err))
+			states[name] = "down"
+			status = http.StatusServiceUnavailable
+			continue
+		}
+		states[name] = "up"
+	}
+
+	response.JSON(w, status, states)
+}
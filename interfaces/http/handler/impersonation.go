@@ -0,0 +1,80 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/goodfoodcesi/auth-api/domain/service"
+	"github.com/goodfoodcesi/auth-api/interfaces/http/response"
+	"github.com/goodfoodcesi/auth-api/validator"
+	"go.uber.org/zap"
+)
+
+type ImpersonationHandler struct {
+	impersonationService *service.ImpersonationService
+	validator            *validator.Validator
+	logger               *zap.Logger
+}
+
+func NewImpersonationHandler(impersonationService *service.ImpersonationService, logger *zap.Logger) *ImpersonationHandler {
+	return &ImpersonationHandler{
+		impersonationService: impersonationService,
+		validator:            validator.NewValidator(),
+		logger:               logger,
+	}
+}
+
+func (h *ImpersonationHandler) Impersonate(w http.ResponseWriter, r *http.Request) {
+	id, err := urlUUID(chi.URLParam(r, "userID"))
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid user ID format", nil)
+		return
+	}
+
+	var input service.ImpersonateInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		h.logger.Error("failed to decode request body", zap.Error(err))
+		response.Error(w, http.StatusBadRequest, "Invalid request body", nil)
+		return
+	}
+
+	if validationErrors := h.validator.Validate(input); validationErrors != nil {
+		h.logger.Error("validation failed", zap.Any("errors", validationErrors))
+		response.JSON(w, http.StatusBadRequest, map[string]interface{}{
+			"errors": validationErrors,
+		})
+		return
+	}
+
+	adminID, err := currentUserID(r)
+	if err != nil {
+		h.logger.Error("invalid user ID format", zap.Error(err))
+		response.Error(w, http.StatusInternalServerError, "Internal Server Error", nil)
+		return
+	}
+
+	ip, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		ip = r.RemoteAddr
+	}
+
+	impersonation, err := h.impersonationService.Impersonate(r.Context(), adminID, id, ip, input)
+	if err != nil {
+		h.logger.Error("failed to impersonate user", zap.Error(err))
+
+		switch {
+		case errors.Is(err, service.ErrUserNotFound):
+			response.Error(w, http.StatusNotFound, "User not found", nil)
+		case errors.Is(err, service.ErrSelfManagement), errors.Is(err, service.ErrImpersonationForbidden):
+			response.Error(w, http.StatusForbidden, err.Error(), nil)
+		default:
+			response.Error(w, http.StatusInternalServerError, "Internal Server Error", nil)
+		}
+		return
+	}
+
+	response.JSON(w, http.StatusOK, impersonation)
+}
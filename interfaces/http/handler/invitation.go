@@ -0,0 +1,137 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/goodfoodcesi/auth-api/domain/service"
+	"github.com/goodfoodcesi/auth-api/interfaces/http/response"
+	"github.com/goodfoodcesi/auth-api/validator"
+	"go.uber.org/zap"
+)
+
+type InvitationHandler struct {
+	invitationService *service.InvitationService
+	validator         *validator.Validator
+	logger            *zap.Logger
+}
+
+func NewInvitationHandler(invitationService *service.InvitationService, logger *zap.Logger) *InvitationHandler {
+	return &InvitationHandler{
+		invitationService: invitationService,
+		validator:         validator.NewValidator(),
+		logger:            logger,
+	}
+}
+
+func (h *InvitationHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var input service.CreateInvitationInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		h.logger.Error("failed to decode request body", zap.Error(err))
+		response.Error(w, http.StatusBadRequest, "Invalid request body", nil)
+		return
+	}
+
+	if validationErrors := h.validator.Validate(input); validationErrors != nil {
+		h.logger.Error("validation failed", zap.Any("errors", validationErrors))
+		response.JSON(w, http.StatusBadRequest, map[string]interface{}{
+			"errors": validationErrors,
+		})
+		return
+	}
+
+	inviter, ok := currentActor(w, r, h.logger)
+	if !ok {
+		return
+	}
+
+	invitation, token, err := h.invitationService.Create(r.Context(), inviter, input)
+	if err != nil {
+		h.logger.Error("failed to create invitation", zap.Error(err))
+		h.writeError(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusCreated, response.ToInvitationResponse(invitation, token))
+}
+
+func (h *InvitationHandler) List(w http.ResponseWriter, r *http.Request) {
+	inviter, ok := currentActor(w, r, h.logger)
+	if !ok {
+		return
+	}
+
+	invitations, err := h.invitationService.List(r.Context(), inviter)
+	if err != nil {
+		h.logger.Error("failed to list invitations", zap.Error(err))
+		response.Error(w, http.StatusInternalServerError, "Failed to list invitations", nil)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, response.ToInvitationsResponse(invitations))
+}
+
+func (h *InvitationHandler) Revoke(w http.ResponseWriter, r *http.Request) {
+	id, err := urlUUID(chi.URLParam(r, "invitationID"))
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid invitation ID format", nil)
+		return
+	}
+
+	inviter, ok := currentActor(w, r, h.logger)
+	if !ok {
+		return
+	}
+
+	if err := h.invitationService.Revoke(r.Context(), inviter, id); err != nil {
+		h.logger.Error("failed to revoke invitation", zap.Error(err))
+		h.writeError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Redeem is the public registration path for privileged roles.
+func (h *InvitationHandler) Redeem(w http.ResponseWriter, r *http.Request) {
+	var input service.RedeemInvitationInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		h.logger.Error("failed to decode request body", zap.Error(err))
+		response.Error(w, http.StatusBadRequest, "Invalid request body", nil)
+		return
+	}
+
+	if validationErrors := h.validator.Validate(input); validationErrors != nil {
+		h.logger.Error("validation failed", zap.Any("errors", validationErrors))
+		response.JSON(w, http.StatusBadRequest, map[string]interface{}{
+			"errors": validationErrors,
+		})
+		return
+	}
+
+	user, err := h.invitationService.Redeem(r.Context(), input)
+	if err != nil {
+		h.logger.Error("failed to redeem invitation", zap.Error(err))
+		h.writeError(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusCreated, response.ToUserResponse(user))
+}
+
+func (h *InvitationHandler) writeError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, service.ErrInvitationNotFound):
+		response.Error(w, http.StatusNotFound, "Invitation not found", nil)
+	case errors.Is(err, service.ErrInvitationForbidden):
+		response.Error(w, http.StatusForbidden, "Forbidden", nil)
+	case errors.Is(err, service.ErrInvitationInvalid):
+		response.Error(w, http.StatusGone, "Invitation is invalid or expired", nil)
+	case errors.Is(err, service.ErrEmailExists):
+		response.Error(w, http.StatusConflict, "Email already exists", nil)
+	default:
+		response.Error(w, http.StatusInternalServerError, "Internal Server Error", nil)
+	}
+}
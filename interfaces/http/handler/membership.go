@@ -0,0 +1,150 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/goodfoodcesi/auth-api/domain/service"
+	"github.com/goodfoodcesi/auth-api/interfaces/http/response"
+	"github.com/goodfoodcesi/auth-api/validator"
+	"go.uber.org/zap"
+)
+
+type MembershipHandler struct {
+	membershipService *service.MembershipService
+	validator         *validator.Validator
+	logger            *zap.Logger
+}
+
+func NewMembershipHandler(membershipService *service.MembershipService, logger *zap.Logger) *MembershipHandler {
+	return &MembershipHandler{
+		membershipService: membershipService,
+		validator:         validator.NewValidator(),
+		logger:            logger,
+	}
+}
+
+func (h *MembershipHandler) ListMine(w http.ResponseWriter, r *http.Request) {
+	userID, err := currentUserID(r)
+	if err != nil {
+		h.logger.Error("invalid user ID format", zap.Error(err))
+		response.Error(w, http.StatusInternalServerError, "Internal Server Error", nil)
+		return
+	}
+
+	memberships, err := h.membershipService.ListMine(r.Context(), userID)
+	if err != nil {
+		h.logger.Error("failed to list memberships", zap.Error(err))
+		response.Error(w, http.StatusInternalServerError, "Failed to list memberships", nil)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, response.ToMembershipsResponse(memberships))
+}
+
+func (h *MembershipHandler) List(w http.ResponseWriter, r *http.Request) {
+	restaurantID, err := urlUUID(chi.URLParam(r, "restaurantID"))
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid restaurant ID format", nil)
+		return
+	}
+
+	actor, ok := currentActor(w, r, h.logger)
+	if !ok {
+		return
+	}
+
+	memberships, err := h.membershipService.ListForRestaurant(r.Context(), actor, restaurantID)
+	if err != nil {
+		h.logger.Error("failed to list restaurant members", zap.Error(err))
+		h.writeError(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, response.ToMembershipsResponse(memberships))
+}
+
+func (h *MembershipHandler) Save(w http.ResponseWriter, r *http.Request) {
+	restaurantID, err := urlUUID(chi.URLParam(r, "restaurantID"))
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid restaurant ID format", nil)
+		return
+	}
+	userID, err := urlUUID(chi.URLParam(r, "userID"))
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid user ID format", nil)
+		return
+	}
+
+	var input service.SaveMembershipInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		h.logger.Error("failed to decode request body", zap.Error(err))
+		response.Error(w, http.StatusBadRequest, "Invalid request body", nil)
+		return
+	}
+
+	if validationErrors := h.validator.Validate(input); validationErrors != nil {
+		h.logger.Error("validation failed", zap.Any("errors", validationErrors))
+		response.JSON(w, http.StatusBadRequest, map[string]interface{}{
+			"errors": validationErrors,
+		})
+		return
+	}
+
+	actor, ok := currentActor(w, r, h.logger)
+	if !ok {
+		return
+	}
+
+	membership, err := h.membershipService.Save(r.Context(), actor, restaurantID, userID, input)
+	if err != nil {
+		h.logger.Error("failed to save membership", zap.Error(err))
+		h.writeError(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, response.ToMembershipResponse(membership))
+}
+
+func (h *MembershipHandler) Remove(w http.ResponseWriter, r *http.Request) {
+	restaurantID, err := urlUUID(chi.URLParam(r, "restaurantID"))
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid restaurant ID format", nil)
+		return
+	}
+	userID, err := urlUUID(chi.URLParam(r, "userID"))
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid user ID format", nil)
+		return
+	}
+
+	actor, ok := currentActor(w, r, h.logger)
+	if !ok {
+		return
+	}
+
+	if err := h.membershipService.Remove(r.Context(), actor, restaurantID, userID); err != nil {
+		h.logger.Error("failed to remove membership", zap.Error(err))
+		h.writeError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *MembershipHandler) writeError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, service.ErrMembershipNotFound):
+		response.Error(w, http.StatusNotFound, "Membership not found", nil)
+	case errors.Is(err, service.ErrUserNotFound):
+		response.Error(w, http.StatusNotFound, "User not found", nil)
+	case errors.Is(err, service.ErrMembershipForbidden), errors.Is(err, service.ErrSelfManagement):
+		response.Error(w, http.StatusForbidden, err.Error(), nil)
+	case errors.Is(err, service.ErrMembershipNotManager):
+		response.Error(w, http.StatusUnprocessableEntity, err.Error(), nil)
+	default:
+		response.Error(w, http.StatusInternalServerError, "Internal Server Error", nil)
+	}
+}
@@ -0,0 +1,79 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/goodfoodcesi/auth-api/domain/service"
+	"github.com/goodfoodcesi/auth-api/interfaces/http/response"
+	"go.uber.org/zap"
+)
+
+type OAuthHandler struct {
+	tokenExchangeService *service.TokenExchangeService
+	logger               *zap.Logger
+}
+
+func NewOAuthHandler(tokenExchangeService *service.TokenExchangeService, logger *zap.Logger) *OAuthHandler {
+	return &OAuthHandler{
+		tokenExchangeService: tokenExchangeService,
+		logger:               logger,
+	}
+}
+
+// Token is the OAuth token endpoint. It takes a form encoded body and answers
+// with RFC 6749 error objects rather than the usual API errors.
+func (h *OAuthHandler) Token(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		oauthError(w, http.StatusBadRequest, service.ErrInvalidRequest, "body must be form encoded")
+		return
+	}
+
+	clientID, clientSecret, ok := r.BasicAuth()
+	if !ok {
+		clientID, clientSecret = r.PostForm.Get("client_id"), r.PostForm.Get("client_secret")
+	}
+
+	token, err := h.tokenExchangeService.Exchange(r.Context(), service.TokenExchangeInput{
+		GrantType:        r.PostForm.Get("grant_type"),
+		ClientID:         clientID,
+		ClientSecret:     clientSecret,
+		SubjectToken:     r.PostForm.Get("subject_token"),
+		SubjectTokenType: r.PostForm.Get("subject_token_type"),
+		Audience:         r.PostForm.Get("audience"),
+		Scope:            r.PostForm.Get("scope"),
+	})
+	if err != nil {
+		h.logger.Error("failed to exchange token", zap.Error(err), zap.String("client_id", clientID))
+
+		switch {
+		case errors.Is(err, service.ErrInvalidClient):
+			w.Header().Set("WWW-Authenticate", `Basic realm="auth-api"`)
+			oauthError(w, http.StatusUnauthorized, err, "unknown client or wrong secret")
+		case errors.Is(err, service.ErrInvalidRequest):
+			oauthError(w, http.StatusBadRequest, err, "subject_token, subject_token_type and audience are required")
+		case errors.Is(err, service.ErrUnsupportedGrantType):
+			oauthError(w, http.StatusBadRequest, err, "only token exchange is supported")
+		case errors.Is(err, service.ErrInvalidGrant):
+			oauthError(w, http.StatusBadRequest, err, "subject token is invalid, expired or revoked")
+		case errors.Is(err, service.ErrInvalidTarget):
+			oauthError(w, http.StatusBadRequest, err, "client may not request tokens for this audience")
+		case errors.Is(err, service.ErrInvalidScope):
+			oauthError(w, http.StatusBadRequest, err, "scope exceeds the subject token permissions")
+		default:
+			response.Error(w, http.StatusInternalServerError, "Internal Server Error", nil)
+		}
+		return
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	response.JSON(w, http.StatusOK, token)
+}
+
+func oauthError(w http.ResponseWriter, status int, err error, description string) {
+	w.Header().Set("Cache-Control", "no-store")
+	response.JSON(w, status, map[string]string{
+		"error":             err.Error(),
+		"error_description": description,
+	})
+}
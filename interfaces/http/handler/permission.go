@@ -0,0 +1,120 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/goodfoodcesi/auth-api/domain/service"
+	db "github.com/goodfoodcesi/auth-api/infrastructure/database/sqlc"
+	"github.com/goodfoodcesi/auth-api/interfaces/http/response"
+	"github.com/goodfoodcesi/auth-api/validator"
+	"go.uber.org/zap"
+)
+
+type PermissionHandler struct {
+	permissionService *service.PermissionService
+	validator         *validator.Validator
+	logger            *zap.Logger
+}
+
+func NewPermissionHandler(permissionService *service.PermissionService, logger *zap.Logger) *PermissionHandler {
+	return &PermissionHandler{
+		permissionService: permissionService,
+		validator:         validator.NewValidator(),
+		logger:            logger,
+	}
+}
+
+func (h *PermissionHandler) List(w http.ResponseWriter, r *http.Request) {
+	permissions, err := h.permissionService.List(r.Context())
+	if err != nil {
+		h.logger.Error("failed to list permissions", zap.Error(err))
+		response.Error(w, http.StatusInternalServerError, "Failed to list permissions", nil)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, response.ToPermissionsResponse(permissions))
+}
+
+func (h *PermissionHandler) ListRoles(w http.ResponseWriter, r *http.Request) {
+	grants, err := h.permissionService.Grants(r.Context())
+	if err != nil {
+		h.logger.Error("failed to list role permissions", zap.Error(err))
+		response.Error(w, http.StatusInternalServerError, "Failed to list role permissions", nil)
+		return
+	}
+
+	resp := make([]response.RolePermissionsResponse, 0, len(grants))
+	for _, role := range []db.UserRole{db.UserRoleClient, db.UserRoleManager, db.UserRoleDriver, db.UserRoleAdmin} {
+		resp = append(resp, response.RolePermissionsResponse{Role: string(role), Permissions: grants[role]})
+	}
+	response.JSON(w, http.StatusOK, resp)
+}
+
+func (h *PermissionHandler) GetRole(w http.ResponseWriter, r *http.Request) {
+	role, ok := roleParam(r)
+	if !ok {
+		response.Error(w, http.StatusNotFound, "Role not found", nil)
+		return
+	}
+
+	permissions, err := h.permissionService.ForRole(r.Context(), role)
+	if err != nil {
+		h.logger.Error("failed to get role permissions", zap.Error(err))
+		response.Error(w, http.StatusInternalServerError, "Failed to get role permissions", nil)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, response.RolePermissionsResponse{Role: string(role), Permissions: permissions})
+}
+
+func (h *PermissionHandler) SetRole(w http.ResponseWriter, r *http.Request) {
+	role, ok := roleParam(r)
+	if !ok {
+		response.Error(w, http.StatusNotFound, "Role not found", nil)
+		return
+	}
+
+	var input service.SetRolePermissionsInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		h.logger.Error("failed to decode request body", zap.Error(err))
+		response.Error(w, http.StatusBadRequest, "Invalid request body", nil)
+		return
+	}
+
+	if validationErrors := h.validator.Validate(input); validationErrors != nil {
+		h.logger.Error("validation failed", zap.Any("errors", validationErrors))
+		response.JSON(w, http.StatusBadRequest, map[string]interface{}{
+			"errors": validationErrors,
+		})
+		return
+	}
+
+	permissions, err := h.permissionService.SetForRole(r.Context(), role, input)
+	if err != nil {
+		h.logger.Error("failed to set role permissions", zap.Error(err))
+
+		switch {
+		case errors.Is(err, service.ErrUnknownPermission):
+			response.Error(w, http.StatusBadRequest, err.Error(), nil)
+		case errors.Is(err, service.ErrPermissionLockout):
+			response.Error(w, http.StatusConflict, err.Error(), nil)
+		default:
+			response.Error(w, http.StatusInternalServerError, "Internal Server Error", nil)
+		}
+		return
+	}
+
+	response.JSON(w, http.StatusOK, response.RolePermissionsResponse{Role: string(role), Permissions: permissions})
+}
+
+func roleParam(r *http.Request) (db.UserRole, bool) {
+	role := db.UserRole(chi.URLParam(r, "role"))
+	switch role {
+	case db.UserRoleClient, db.UserRoleManager, db.UserRoleDriver, db.UserRoleAdmin:
+		return role, true
+	}
+	return "", false
+}
@@ -0,0 +1,97 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/goodfoodcesi/auth-api/domain/service"
+	"github.com/goodfoodcesi/auth-api/interfaces/http/response"
+	"github.com/goodfoodcesi/auth-api/validator"
+	"go.uber.org/zap"
+)
+
+type PhoneLoginHandler struct {
+	phoneLoginService *service.PhoneLoginService
+	validator         *validator.Validator
+	logger            *zap.Logger
+}
+
+func NewPhoneLoginHandler(phoneLoginService *service.PhoneLoginService, logger *zap.Logger) *PhoneLoginHandler {
+	return &PhoneLoginHandler{
+		phoneLoginService: phoneLoginService,
+		validator:         validator.NewValidator(),
+		logger:            logger,
+	}
+}
+
+func (h *PhoneLoginHandler) RequestCode(w http.ResponseWriter, r *http.Request) {
+	var input service.PhoneLoginInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		h.logger.Error("failed to decode request body", zap.Error(err))
+		response.Error(w, http.StatusBadRequest, "Invalid request body", nil)
+		return
+	}
+
+	if validationErrors := h.validator.Validate(input); validationErrors != nil {
+		h.logger.Error("validation failed", zap.Any("errors", validationErrors))
+		response.JSON(w, http.StatusBadRequest, map[string]interface{}{
+			"errors": validationErrors,
+		})
+		return
+	}
+
+	if err := h.phoneLoginService.RequestCode(r.Context(), input); err != nil {
+		h.logger.Error("failed to request phone login code", zap.Error(err))
+
+		if errors.Is(err, service.ErrOtpThrottled) {
+			response.Error(w, http.StatusTooManyRequests, "Too many codes requested, try again later", nil)
+			return
+		}
+		if errors.Is(err, service.ErrMessagingUnavailable) {
+			response.Error(w, http.StatusServiceUnavailable, "Cannot send codes right now, try again later", nil)
+			return
+		}
+
+		response.Error(w, http.StatusInternalServerError, "Failed to send code", nil)
+		return
+	}
+
+	response.JSON(w, http.StatusAccepted, map[string]string{"status": "code sent"})
+}
+
+func (h *PhoneLoginHandler) VerifyCode(w http.ResponseWriter, r *http.Request) {
+	var input service.PhoneVerifyInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		h.logger.Error("failed to decode request body", zap.Error(err))
+		response.Error(w, http.StatusBadRequest, "Invalid request body", nil)
+		return
+	}
+
+	if validationErrors := h.validator.Validate(input); validationErrors != nil {
+		h.logger.Error("validation failed", zap.Any("errors", validationErrors))
+		response.JSON(w, http.StatusBadRequest, map[string]interface{}{
+			"errors": validationErrors,
+		})
+		return
+	}
+
+	tokens, err := h.phoneLoginService.VerifyCode(r.Context(), input)
+	if err != nil {
+		h.logger.Error("failed to verify phone login code", zap.Error(err))
+
+		switch {
+		case errors.Is(err, service.ErrOtpAttemptsExceeded):
+			response.Error(w, http.StatusTooManyRequests, "Too many attempts, request a new code", nil)
+		case errors.Is(err, service.ErrOtpInvalid):
+			response.Error(w, http.StatusUnauthorized, "Invalid or expired code", nil)
+		case errors.Is(err, service.ErrAccountInactive):
+			response.Error(w, http.StatusForbidden, "Account is not active", nil)
+		default:
+			response.Error(w, http.StatusInternalServerError, "Failed to verify code", nil)
+		}
+		return
+	}
+
+	response.JSON(w, http.StatusOK, tokens)
+}
@@ -2,31 +2,43 @@ package handler
 
 import (
 	"encoding/json"
+	"errors"
+	"github.com/goodfoodcesi/auth-api/interfaces/http/cookie"
 	"github.com/goodfoodcesi/auth-api/interfaces/http/response"
 	"net/http"
 
 	"github.com/goodfoodcesi/auth-api/domain/service"
 	"github.com/goodfoodcesi/auth-api/validator"
-	"github.com/google/uuid"
-	"github.com/jackc/pgx/v5/pgtype"
 	"go.uber.org/zap"
 )
 
 type UserHandler struct {
 	userService *service.UserService
+	cookies     cookie.Config
 	validator   *validator.Validator
 	logger      *zap.Logger
 }
 
-func NewUserHandler(userService *service.UserService, logger *zap.Logger) *UserHandler {
+func NewUserHandler(userService *service.UserService, cookies cookie.Config, logger *zap.Logger) *UserHandler {
 	return &UserHandler{
 		userService: userService,
+		cookies:     cookies,
 		validator:   validator.NewValidator(),
 		logger:      logger,
 	}
 }
 
 func (h *UserHandler) Update(w http.ResponseWriter, r *http.Request) {
+	version, present, ok := ifMatchVersion(r)
+	if !present {
+		response.Error(w, http.StatusPreconditionRequired, "If-Match header is required", nil)
+		return
+	}
+	if !ok {
+		response.Error(w, http.StatusPreconditionFailed, "User has been modified", nil)
+		return
+	}
+
 	var input service.UpdateUserInput
 	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
 		h.logger.Error("failed to decode request body", zap.Error(err))
@@ -48,20 +60,29 @@ func (h *UserHandler) Update(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	id, err := uuid.Parse(r.Context().Value("userID").(string))
+	id, err := currentUserID(r)
 	if err != nil {
 		h.logger.Error("invalid user ID format", zap.Error(err))
 		response.Error(w, http.StatusInternalServerError, "Internal Server Error", nil)
 		return
 	}
 
-	user, err := h.userService.Update(r.Context(), pgtype.UUID{Bytes: id, Valid: true}, input)
+	user, err := h.userService.Update(r.Context(), id, version, input)
 	if err != nil {
 		h.logger.Error("failed to update user", zap.Error(err))
-		response.Error(w, http.StatusInternalServerError, "Internal Server Error", nil)
+
+		switch {
+		case errors.Is(err, service.ErrStaleVersion):
+			response.Error(w, http.StatusPreconditionFailed, "User has been modified", nil)
+		case errors.Is(err, service.ErrUserNotFound):
+			response.Error(w, http.StatusNotFound, "User not found", nil)
+		default:
+			response.Error(w, http.StatusInternalServerError, "Internal Server Error", nil)
+		}
 		return
 	}
 
+	setETag(w, user.Version)
 	w.Header().Set("Content-Type", "application/json")
 	userResponse := response.ToUserResponse(user)
 	if err := json.NewEncoder(w).Encode(userResponse); err != nil {
@@ -71,6 +92,44 @@ func (h *UserHandler) Update(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+func (h *UserHandler) ChangePassword(w http.ResponseWriter, r *http.Request) {
+	var input service.ChangePasswordInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		h.logger.Error("failed to decode request body", zap.Error(err))
+		response.Error(w, http.StatusBadRequest, "Invalid request body", nil)
+		return
+	}
+
+	if validationErrors := h.validator.Validate(input); validationErrors != nil {
+		h.logger.Error("validation failed", zap.Any("errors", validationErrors))
+		response.JSON(w, http.StatusBadRequest, map[string]interface{}{"errors": validationErrors})
+		return
+	}
+
+	id, err := currentUserID(r)
+	if err != nil {
+		h.logger.Error("invalid user ID format", zap.Error(err))
+		response.Error(w, http.StatusInternalServerError, "Internal Server Error", nil)
+		return
+	}
+
+	if err := h.userService.ChangePassword(r.Context(), id, input); err != nil {
+		h.logger.Error("failed to change password", zap.Error(err))
+
+		switch {
+		case errors.Is(err, service.ErrInvalidPassword):
+			response.Error(w, http.StatusForbidden, "Current password is invalid", nil)
+		case errors.Is(err, service.ErrUserNotFound):
+			response.Error(w, http.StatusNotFound, "User not found", nil)
+		default:
+			response.Error(w, http.StatusInternalServerError, "Internal Server Error", nil)
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
 func (h *UserHandler) Register(w http.ResponseWriter, r *http.Request) {
 	var input service.RegisterUserInput
 	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
@@ -97,7 +156,7 @@ func (h *UserHandler) Register(w http.ResponseWriter, r *http.Request) {
 	if err != nil {
 		h.logger.Error("failed to register user", zap.Error(err))
 
-		if err.Error() == "email already exists" {
+		if errors.Is(err, service.ErrEmailExists) {
 			response.Error(w, http.StatusConflict, "Email already exists", nil)
 			return
 		}
@@ -127,36 +186,43 @@ func (h *UserHandler) Login(w http.ResponseWriter, r *http.Request) {
 	tokens, err := h.userService.Login(r.Context(), input)
 	if err != nil {
 		h.logger.Error("failed to login", zap.Error(err))
+
+		if errors.Is(err, service.ErrAccountInactive) {
+			response.Error(w, http.StatusForbidden, "Account is not active", nil)
+			return
+		}
+		if errors.Is(err, service.ErrNotRestaurantMember) {
+			response.Error(w, http.StatusForbidden, "Not a member of this restaurant", nil)
+			return
+		}
+
 		response.Error(w, http.StatusUnauthorized, "Invalid credentials", nil)
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	err = json.NewEncoder(w).Encode(tokens)
-	if err != nil {
-		h.logger.Error("failed to encode response", zap.Error(err))
-		response.Error(w, http.StatusInternalServerError, "Failed to encode response", nil)
-		return
-	}
+	writeTokens(w, r, h.cookies, tokens, h.logger)
 }
 
 func (h *UserHandler) GetProfile(w http.ResponseWriter, r *http.Request) {
-	userID := r.Context().Value("userID").(string)
-
-	id, err := uuid.Parse(userID)
+	id, err := currentUserID(r)
 	if err != nil {
 		h.logger.Error("invalid user ID format", zap.Error(err))
 		response.Error(w, http.StatusBadRequest, "Invalid user ID format", nil)
 		return
 	}
 
-	user, err := h.userService.GetByID(r.Context(), pgtype.UUID{Bytes: id, Valid: true})
+	user, err := h.userService.GetByID(r.Context(), id)
 	if err != nil {
 		h.logger.Error("failed to get user profile", zap.Error(err))
 		response.Error(w, http.StatusInternalServerError, "Failed to get user profile", nil)
 		return
 	}
 
+	setETag(w, user.Version)
+	// Lets the app show that support staff is looking at the account.
+	if actorID := currentImpersonator(r); actorID != "" {
+		w.Header().Set("X-Impersonated-By", actorID)
+	}
 	w.Header().Set("Content-Type", "application/json")
 	userResponse := response.ToUserResponse(user)
 	if err := json.NewEncoder(w).Encode(userResponse); err != nil {
@@ -2,61 +2,182 @@ package middleware
 
 import (
 	"context"
+	"github.com/goodfoodcesi/auth-api/domain/auth"
 	db "github.com/goodfoodcesi/auth-api/infrastructure/database/sqlc"
 	"net/http"
 	"strings"
+	"time"
 
+	"github.com/goodfoodcesi/auth-api/interfaces/http/cookie"
 	"github.com/goodfoodcesi/auth-api/interfaces/http/response"
 
 	"github.com/goodfoodcesi/auth-api/infrastructure/jwt"
 	"go.uber.org/zap"
 )
 
-func AuthMiddleware(logger *zap.Logger, tm *jwt.TokenManager) func(http.Handler) http.Handler {
+// AccountChecker tells whether the account behind a valid token may still use
+// the API, so a suspended user, or one whose sessions were revoked, is locked
+// out before their token expires.
+type AccountChecker interface {
+	IsActive(ctx context.Context, userID string, issuedAt time.Time) (bool, error)
+}
+
+// ImpersonationAuditor writes every request made with an impersonation token
+// to the audit log.
+type ImpersonationAuditor interface {
+	RecordImpersonatedRequest(ctx context.Context, actorID, subjectID, method, path, ip string) error
+}
+
+// APIKeyAuthenticator resolves an API key sent as "Authorization: ApiKey <key>"
+// into the principal it acts as.
+type APIKeyAuthenticator interface {
+	AuthenticateAPIKey(ctx context.Context, key, ip string) (*auth.Principal, error)
+}
+
+func AuthMiddleware(logger *zap.Logger, tm *jwt.TokenManager, keys APIKeyAuthenticator, accounts AccountChecker, auditor ImpersonationAuditor) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			authHeader := r.Header.Get("Authorization")
 			if authHeader == "" {
-				response.Error(w, http.StatusUnauthorized, "Authorization header is missing", nil)
-				return
+				// Browser sessions keep the access token in a cookie, which
+				// the browser also sends on cross-site requests.
+				token := cookie.Value(r, cookie.AccessToken)
+				if token == "" {
+					response.Error(w, http.StatusUnauthorized, "Authorization header is missing", nil)
+					return
+				}
+				if !cookie.Safe(r.Method) && !cookie.ValidCSRF(r) {
+					response.Error(w, http.StatusForbidden, "Invalid CSRF token", nil)
+					return
+				}
+				authHeader = "Bearer " + token
 			}
 
-			bearerToken := strings.Split(authHeader, " ")
-			if len(bearerToken) != 2 || bearerToken[0] != "Bearer" {
+			credentials := strings.Split(authHeader, " ")
+			if len(credentials) != 2 || (credentials[0] != "Bearer" && credentials[0] != "ApiKey") {
 				response.Error(w, http.StatusUnauthorized, "Invalid authorization header format", nil)
 				return
 			}
 
-			claims, err := tm.ValidateToken(bearerToken[1], false)
+			var principal *auth.Principal
+			if credentials[0] == "ApiKey" {
+				var err error
+				principal, err = keys.AuthenticateAPIKey(r.Context(), credentials[1], remoteIP(r))
+				if err != nil {
+					logger.Error("invalid api key", zap.Error(err))
+					response.Error(w, http.StatusUnauthorized, "Invalid API key", nil)
+					return
+				}
+			} else {
+				claims, err := tm.ValidateToken(credentials[1], false)
+				if err != nil {
+					logger.Error("invalid token", zap.Error(err))
+					response.Error(w, http.StatusBadRequest, "Invalid token", nil)
+					return
+				}
+				principal = auth.FromClaims(claims)
+			}
+
+			active, err := accounts.IsActive(r.Context(), principal.Subject, principal.IssuedAt)
 			if err != nil {
-				logger.Error("invalid token", zap.Error(err))
-				response.Error(w, http.StatusBadRequest, "Invalid token", nil)
+				logger.Error("failed to check account status", zap.Error(err))
+				response.Error(w, http.StatusUnauthorized, "Invalid token", nil)
+				return
+			}
+			if !active {
+				response.Error(w, http.StatusForbidden, "Account is not active", nil)
+				return
+			}
+
+			if principal.Impersonated() {
+				// Impersonation tokens only let support staff look around.
+				if !cookie.Safe(r.Method) {
+					response.Error(w, http.StatusForbidden, "Impersonation tokens are read-only", nil)
+					return
+				}
+
+				err := auditor.RecordImpersonatedRequest(r.Context(), principal.Actor, principal.Subject, r.Method, r.URL.Path, remoteIP(r))
+				if err != nil {
+					logger.Error("failed to audit impersonated request", zap.Error(err))
+					response.Error(w, http.StatusInternalServerError, "Internal Server Error", nil)
+					return
+				}
+			}
+
+			next.ServeHTTP(w, r.WithContext(auth.NewContext(r.Context(), principal)))
+		})
+	}
+}
+
+// RequireRole lets the request through when the user has one of roles. Admins
+// are always let through.
+func RequireRole(roles ...db.UserRole) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			principal, ok := auth.FromContext(r.Context())
+			if !ok {
+				response.Error(w, http.StatusUnauthorized, "Unauthorized", nil)
+				return
+			}
+
+			if principal.Role == db.UserRoleAdmin {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			for _, requiredRole := range roles {
+				if principal.Role == requiredRole {
+					next.ServeHTTP(w, r)
+					return
+				}
+			}
+
+			response.Error(w, http.StatusForbidden, "Forbidden", nil)
+		})
+	}
+}
+
+// RequireKind lets the request through when the caller authenticated in one
+// of kinds. Routes acting on the caller's own account, or minting tokens for
+// it, take users only so an API key cannot step out of its scopes.
+func RequireKind(kinds ...auth.Kind) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			principal, ok := auth.FromContext(r.Context())
+			if !ok {
+				response.Error(w, http.StatusUnauthorized, "Unauthorized", nil)
 				return
 			}
 
-			ctx := context.WithValue(r.Context(), "userID", claims.UserID)
-			ctx = context.WithValue(ctx, "userRole", claims.Role)
+			for _, kind := range kinds {
+				if principal.Kind == kind {
+					next.ServeHTTP(w, r)
+					return
+				}
+			}
 
-			next.ServeHTTP(w, r.WithContext(ctx))
+			response.Error(w, http.StatusForbidden, "Forbidden", nil)
 		})
 	}
 }
 
-func RequireRole(requiredRole db.UserRole) func(http.Handler) http.Handler {
+// RequirePermission lets the request through when the token was issued with
+// permission, e.g. RequirePermission("orders:refund").
+func RequirePermission(permission string) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			role := r.Context().Value("userRole")
-			if role == nil {
+			principal, ok := auth.FromContext(r.Context())
+			if !ok {
 				response.Error(w, http.StatusUnauthorized, "Unauthorized", nil)
 				return
 			}
 
-			if role.(db.UserRole) != requiredRole {
-				response.Error(w, http.StatusForbidden, "Forbidden", nil)
+			if principal.HasPermission(permission) {
+				next.ServeHTTP(w, r)
 				return
 			}
 
-			next.ServeHTTP(w, r)
+			response.Error(w, http.StatusForbidden, "Forbidden", nil)
 		})
 	}
 }
@@ -0,0 +1,174 @@
+package middleware_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/goodfoodcesi/auth-api/domain/auth"
+	"github.com/goodfoodcesi/auth-api/infrastructure/database/sqlc"
+	"github.com/goodfoodcesi/auth-api/infrastructure/jwt"
+	"github.com/goodfoodcesi/auth-api/interfaces/http/cookie"
+	"github.com/goodfoodcesi/auth-api/interfaces/http/middleware"
+	"go.uber.org/zap"
+)
+
+// accounts reports every account as active unless listed in inactive.
+type accounts struct {
+	inactive map[string]bool
+}
+
+func (a accounts) IsActive(_ context.Context, userID string, _ time.Time) (bool, error) {
+	return !a.inactive[userID], nil
+}
+
+// apiKeys knows a single key.
+type apiKeys map[string]*auth.Principal
+
+func (k apiKeys) AuthenticateAPIKey(_ context.Context, key, _ string) (*auth.Principal, error) {
+	principal, ok := k[key]
+	if !ok {
+		return nil, errors.New("unknown api key")
+	}
+	return principal, nil
+}
+
+type auditor struct {
+	requests int
+}
+
+func (a *auditor) RecordImpersonatedRequest(context.Context, string, string, string, string, string) error {
+	a.requests++
+	return nil
+}
+
+// ok answers 204 with nothing, to tell it from the middleware's answers.
+var ok = http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+	w.WriteHeader(http.StatusNoContent)
+})
+
+func serve(handler http.Handler, r *http.Request) int {
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+	return w.Code
+}
+
+func TestAuthMiddleware(t *testing.T) {
+	tokens := jwt.NewTokenManager("access-secret", "refresh-secret", time.Hour, 24*time.Hour)
+	pair, err := tokens.GenerateTokenPair(jwt.Subject{UserID: "u1", Role: db.UserRoleClient})
+	if err != nil {
+		t.Fatalf("GenerateTokenPair: %v", err)
+	}
+	suspended, err := tokens.GenerateTokenPair(jwt.Subject{UserID: "u2", Role: db.UserRoleClient})
+	if err != nil {
+		t.Fatalf("GenerateTokenPair: %v", err)
+	}
+	impersonation, _, err := tokens.GenerateImpersonationToken(jwt.Subject{UserID: "u1", Role: db.UserRoleClient}, "admin", time.Minute)
+	if err != nil {
+		t.Fatalf("GenerateImpersonationToken: %v", err)
+	}
+	keys := apiKeys{"gf_key": {Subject: "u3", Kind: auth.KindAPIKey, Role: db.UserRoleManager}}
+
+	withCookies := func(r *http.Request, access, csrf, header string) *http.Request {
+		r.AddCookie(&http.Cookie{Name: cookie.AccessToken, Value: access})
+		if csrf != "" {
+			r.AddCookie(&http.Cookie{Name: cookie.CSRF, Value: csrf})
+		}
+		if header != "" {
+			r.Header.Set(cookie.CSRFHeader, header)
+		}
+		return r
+	}
+	withHeader := func(r *http.Request, value string) *http.Request {
+		r.Header.Set("Authorization", value)
+		return r
+	}
+
+	tests := []struct {
+		name        string
+		request     *http.Request
+		want        int
+		wantAudited int
+	}{
+		{name: "bearer token", request: withHeader(httptest.NewRequest(http.MethodPost, "/", nil), "Bearer "+pair.AccessToken), want: http.StatusNoContent},
+		{name: "api key", request: withHeader(httptest.NewRequest(http.MethodPost, "/", nil), "ApiKey gf_key"), want: http.StatusNoContent},
+		{name: "unknown api key", request: withHeader(httptest.NewRequest(http.MethodPost, "/", nil), "ApiKey gf_other"), want: http.StatusUnauthorized},
+		{name: "no credentials", request: httptest.NewRequest(http.MethodGet, "/", nil), want: http.StatusUnauthorized},
+		{name: "unknown scheme", request: withHeader(httptest.NewRequest(http.MethodGet, "/", nil), "Basic dXNlcjpwYXNz"), want: http.StatusUnauthorized},
+		{name: "refresh token", request: withHeader(httptest.NewRequest(http.MethodGet, "/", nil), "Bearer "+pair.RefreshToken), want: http.StatusBadRequest},
+		{name: "inactive account", request: withHeader(httptest.NewRequest(http.MethodGet, "/", nil), "Bearer "+suspended.AccessToken), want: http.StatusForbidden},
+		{name: "cookie on a safe method", request: withCookies(httptest.NewRequest(http.MethodGet, "/", nil), pair.AccessToken, "", ""), want: http.StatusNoContent},
+		{name: "cookie with matching csrf token", request: withCookies(httptest.NewRequest(http.MethodPost, "/", nil), pair.AccessToken, "csrf", "csrf"), want: http.StatusNoContent},
+		{name: "cookie with mismatched csrf token", request: withCookies(httptest.NewRequest(http.MethodPost, "/", nil), pair.AccessToken, "csrf", "other"), want: http.StatusForbidden},
+		{name: "cookie without csrf header", request: withCookies(httptest.NewRequest(http.MethodDelete, "/", nil), pair.AccessToken, "csrf", ""), want: http.StatusForbidden},
+		{name: "cookie without csrf cookie", request: withCookies(httptest.NewRequest(http.MethodPut, "/", nil), pair.AccessToken, "", "csrf"), want: http.StatusForbidden},
+		{name: "impersonation read", request: withHeader(httptest.NewRequest(http.MethodGet, "/", nil), "Bearer "+impersonation), want: http.StatusNoContent, wantAudited: 1},
+		{name: "impersonation write", request: withHeader(httptest.NewRequest(http.MethodPatch, "/", nil), "Bearer "+impersonation), want: http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			audit := &auditor{}
+			handler := middleware.AuthMiddleware(zap.NewNop(), tokens, keys, accounts{inactive: map[string]bool{"u2": true}}, audit)(ok)
+
+			if status := serve(handler, tt.request); status != tt.want {
+				t.Errorf("status = %d, want %d", status, tt.want)
+			}
+			if audit.requests != tt.wantAudited {
+				t.Errorf("audited %d requests, want %d", audit.requests, tt.wantAudited)
+			}
+		})
+	}
+}
+
+func TestRequireKind(t *testing.T) {
+	tests := []struct {
+		name      string
+		principal *auth.Principal
+		want      int
+	}{
+		{name: "user", principal: &auth.Principal{Subject: "u1", Kind: auth.KindUser}, want: http.StatusNoContent},
+		{name: "api key", principal: &auth.Principal{Subject: "u1", Kind: auth.KindAPIKey}, want: http.StatusForbidden},
+		{name: "service", principal: &auth.Principal{Subject: "u1", Kind: auth.KindService}, want: http.StatusForbidden},
+		{name: "anonymous", want: http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.principal != nil {
+				r = r.WithContext(auth.NewContext(r.Context(), tt.principal))
+			}
+
+			if status := serve(middleware.RequireKind(auth.KindUser)(ok), r); status != tt.want {
+				t.Errorf("status = %d, want %d", status, tt.want)
+			}
+		})
+	}
+}
+
+func TestRequirePermission(t *testing.T) {
+	tests := []struct {
+		name      string
+		principal *auth.Principal
+		want      int
+	}{
+		{name: "granted", principal: &auth.Principal{Permissions: []string{"users:read", "orders:refund"}}, want: http.StatusNoContent},
+		{name: "not granted", principal: &auth.Principal{Permissions: []string{"orders:read"}}, want: http.StatusForbidden},
+		{name: "admin without the permission", principal: &auth.Principal{Role: db.UserRoleAdmin}, want: http.StatusForbidden},
+		{name: "anonymous", want: http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, "/", nil)
+			if tt.principal != nil {
+				r = r.WithContext(auth.NewContext(r.Context(), tt.principal))
+			}
+
+			if status := serve(middleware.RequirePermission("orders:refund")(ok), r); status != tt.want {
+				t.Errorf("status = %d, want %d", status, tt.want)
+			}
+		})
+	}
+}
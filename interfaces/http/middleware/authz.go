@@ -0,0 +1,54 @@
+package middleware
+
+import (
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/goodfoodcesi/auth-api/authz"
+	"github.com/goodfoodcesi/auth-api/domain/auth"
+	"github.com/goodfoodcesi/auth-api/interfaces/http/response"
+)
+
+// ResourceFunc describes the resource a request acts on, usually from its
+// URL parameters.
+type ResourceFunc func(r *http.Request) authz.Resource
+
+// Authorize asks engine whether the authenticated user may perform action on
+// the resource of the request. It must run after AuthMiddleware.
+func Authorize(engine *authz.Engine, action string, resource ResourceFunc) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			principal, ok := auth.FromContext(r.Context())
+			if !ok {
+				response.Error(w, http.StatusUnauthorized, "Unauthorized", nil)
+				return
+			}
+
+			decision := engine.Decide(authz.Request{
+				Subject:  authz.SubjectFromPrincipal(principal),
+				Action:   action,
+				Resource: resource(r),
+				Environment: authz.Environment{
+					IP:   remoteIP(r),
+					Time: time.Now(),
+				},
+			})
+			if !decision.Allowed {
+				response.Error(w, http.StatusForbidden, "Forbidden", nil)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// remoteIP strips the port RemoteAddr has unless RealIP already rewrote it.
+func remoteIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
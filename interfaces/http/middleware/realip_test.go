@@ -0,0 +1,79 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/goodfoodcesi/auth-api/interfaces/http/middleware"
+)
+
+func TestRealIP(t *testing.T) {
+	proxies, err := middleware.ParseTrustedProxies("10.0.0.0/8, 192.0.2.1")
+	if err != nil {
+		t.Fatalf("ParseTrustedProxies: %v", err)
+	}
+
+	tests := []struct {
+		name         string
+		remoteAddr   string
+		forwardedFor string
+		realIP       string
+		wantRemoteIP string
+	}{
+		{name: "direct client", remoteAddr: "198.51.100.7:4321", wantRemoteIP: "198.51.100.7:4321"},
+		{name: "spoofed header from an untrusted peer", remoteAddr: "198.51.100.7:4321", forwardedFor: "203.0.113.9", wantRemoteIP: "198.51.100.7:4321"},
+		{name: "spoofed real ip from an untrusted peer", remoteAddr: "198.51.100.7:4321", realIP: "203.0.113.9", wantRemoteIP: "198.51.100.7:4321"},
+		{name: "client behind a trusted proxy", remoteAddr: "10.1.2.3:80", forwardedFor: "203.0.113.9", wantRemoteIP: "203.0.113.9"},
+		{name: "client behind a chain of trusted proxies", remoteAddr: "10.1.2.3:80", forwardedFor: "203.0.113.9, 192.0.2.1, 10.4.5.6", wantRemoteIP: "203.0.113.9"},
+		{name: "client prepending a spoofed hop", remoteAddr: "10.1.2.3:80", forwardedFor: "1.2.3.4, 203.0.113.9", wantRemoteIP: "203.0.113.9"},
+		{name: "real ip from a trusted proxy", remoteAddr: "192.0.2.1:80", realIP: "203.0.113.9", wantRemoteIP: "203.0.113.9"},
+		{name: "malformed hop", remoteAddr: "10.1.2.3:80", forwardedFor: "203.0.113.9, not-an-ip", wantRemoteIP: "10.1.2.3:80"},
+		{name: "only trusted hops", remoteAddr: "10.1.2.3:80", forwardedFor: "10.9.9.9", wantRemoteIP: "10.1.2.3:80"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			r.RemoteAddr = tt.remoteAddr
+			if tt.forwardedFor != "" {
+				r.Header.Set("X-Forwarded-For", tt.forwardedFor)
+			}
+			if tt.realIP != "" {
+				r.Header.Set("X-Real-IP", tt.realIP)
+			}
+
+			var got string
+			middleware.RealIP(proxies)(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+				got = r.RemoteAddr
+			})).ServeHTTP(httptest.NewRecorder(), r)
+
+			if got != tt.wantRemoteIP {
+				t.Errorf("remote address = %s, want %s", got, tt.wantRemoteIP)
+			}
+		})
+	}
+}
+
+func TestParseTrustedProxies(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    int
+		wantErr bool
+	}{
+		{value: "", want: 0},
+		{value: "10.0.0.0/8", want: 1},
+		{value: "192.0.2.1, ::1, 2001:db8::/32", want: 3},
+		{value: "10.0.0.0/33", wantErr: true},
+		{value: "proxy.internal", wantErr: true},
+	}
+	for _, tt := range tests {
+		proxies, err := middleware.ParseTrustedProxies(tt.value)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseTrustedProxies(%q) error = %v, want error %t", tt.value, err, tt.wantErr)
+			continue
+		}
+		if len(proxies) != tt.want {
+			t.Errorf("ParseTrustedProxies(%q) = %v, want %d networks", tt.value, proxies, tt.want)
+		}
+	}
+}
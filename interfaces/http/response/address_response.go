@@ -0,0 +1,49 @@
+package response
+
+import (
+	"github.com/goodfoodcesi/auth-api/infrastructure/database/sqlc"
+	"time"
+)
+
+type AddressResponse struct {
+	ID          string    `json:"id"`
+	Label       string    `json:"label"`
+	Line1       string    `json:"line1"`
+	Line2       string    `json:"line2,omitempty"`
+	Postcode    string    `json:"postcode"`
+	City        string    `json:"city"`
+	CountryCode string    `json:"country_code"`
+	Latitude    *float64  `json:"latitude,omitempty"`
+	Longitude   *float64  `json:"longitude,omitempty"`
+	IsDefault   bool      `json:"is_default"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+func ToAddressResponse(address *db.UserAddress) AddressResponse {
+	resp := AddressResponse{
+		ID:          address.ID.String(),
+		Label:       address.Label,
+		Line1:       address.Line1,
+		Line2:       address.Line2.String,
+		Postcode:    address.Postcode,
+		City:        address.City,
+		CountryCode: address.CountryCode,
+		IsDefault:   address.IsDefault,
+		CreatedAt:   address.CreatedAt.Time,
+		UpdatedAt:   address.UpdatedAt.Time,
+	}
+	if address.Latitude.Valid && address.Longitude.Valid {
+		resp.Latitude = &address.Latitude.Float64
+		resp.Longitude = &address.Longitude.Float64
+	}
+	return resp
+}
+
+func ToAddressesResponse(addresses []db.UserAddress) []AddressResponse {
+	resp := make([]AddressResponse, 0, len(addresses))
+	for i := range addresses {
+		resp = append(resp, ToAddressResponse(&addresses[i]))
+	}
+	return resp
+}
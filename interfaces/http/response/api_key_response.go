@@ -0,0 +1,56 @@
+package response
+
+import (
+	"github.com/goodfoodcesi/auth-api/infrastructure/database/sqlc"
+	"time"
+)
+
+type APIKeyResponse struct {
+	ID           string     `json:"id"`
+	Name         string     `json:"name"`
+	Prefix       string     `json:"prefix"`
+	OwnerID      string     `json:"owner_id"`
+	RestaurantID string     `json:"restaurant_id"`
+	Scopes       []string   `json:"scopes"`
+	IPAllowlist  []string   `json:"ip_allowlist"`
+	ExpiresAt    *time.Time `json:"expires_at,omitempty"`
+	LastUsedAt   *time.Time `json:"last_used_at,omitempty"`
+	LastUsedIP   string     `json:"last_used_ip,omitempty"`
+	RevokedAt    *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+	// Key is only returned once, when the key is created.
+	Key string `json:"key,omitempty"`
+}
+
+func ToAPIKeyResponse(key *db.ApiKey, value string) APIKeyResponse {
+	resp := APIKeyResponse{
+		ID:           key.ID.String(),
+		Name:         key.Name,
+		Prefix:       key.Prefix,
+		OwnerID:      key.OwnerID.String(),
+		RestaurantID: key.RestaurantID.String(),
+		Scopes:       key.Scopes,
+		IPAllowlist:  key.IpAllowlist,
+		LastUsedIP:   key.LastUsedIp.String,
+		CreatedAt:    key.CreatedAt.Time,
+		Key:          value,
+	}
+	if key.ExpiresAt.Valid {
+		resp.ExpiresAt = &key.ExpiresAt.Time
+	}
+	if key.LastUsedAt.Valid {
+		resp.LastUsedAt = &key.LastUsedAt.Time
+	}
+	if key.RevokedAt.Valid {
+		resp.RevokedAt = &key.RevokedAt.Time
+	}
+	return resp
+}
+
+func ToAPIKeysResponse(keys []db.ApiKey) []APIKeyResponse {
+	resp := make([]APIKeyResponse, 0, len(keys))
+	for i := range keys {
+		resp = append(resp, ToAPIKeyResponse(&keys[i], ""))
+	}
+	return resp
+}
@@ -0,0 +1,49 @@
+package response
+
+import (
+	"github.com/goodfoodcesi/auth-api/infrastructure/database/sqlc"
+	"time"
+)
+
+type DriverApplicationResponse struct {
+	ID            string     `json:"id"`
+	UserID        string     `json:"user_id"`
+	Status        string     `json:"status"`
+	VehicleType   string     `json:"vehicle_type"`
+	LicenseNumber string     `json:"license_number"`
+	DocumentURL   string     `json:"document_url"`
+	ReviewerNotes string     `json:"reviewer_notes,omitempty"`
+	ReviewedBy    string     `json:"reviewed_by,omitempty"`
+	ReviewedAt    *time.Time `json:"reviewed_at,omitempty"`
+	SubmittedAt   time.Time  `json:"submitted_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
+
+func ToDriverApplicationResponse(application *db.DriverApplication) DriverApplicationResponse {
+	resp := DriverApplicationResponse{
+		ID:            application.ID.String(),
+		UserID:        application.UserID.String(),
+		Status:        string(application.Status),
+		VehicleType:   application.VehicleType,
+		LicenseNumber: application.LicenseNumber,
+		DocumentURL:   application.DocumentUrl,
+		ReviewerNotes: application.ReviewerNotes.String,
+		SubmittedAt:   application.SubmittedAt.Time,
+		UpdatedAt:     application.UpdatedAt.Time,
+	}
+	if application.ReviewedBy.Valid {
+		resp.ReviewedBy = application.ReviewedBy.String()
+	}
+	if application.ReviewedAt.Valid {
+		resp.ReviewedAt = &application.ReviewedAt.Time
+	}
+	return resp
+}
+
+func ToDriverApplicationsResponse(applications []db.DriverApplication) []DriverApplicationResponse {
+	resp := make([]DriverApplicationResponse, 0, len(applications))
+	for i := range applications {
+		resp = append(resp, ToDriverApplicationResponse(&applications[i]))
+	}
+	return resp
+}
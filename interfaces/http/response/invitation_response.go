@@ -0,0 +1,50 @@
+package response
+
+import (
+	"github.com/goodfoodcesi/auth-api/infrastructure/database/sqlc"
+	"time"
+)
+
+type InvitationResponse struct {
+	ID           string     `json:"id"`
+	Email        string     `json:"email"`
+	Role         string     `json:"role"`
+	RestaurantID string     `json:"restaurant_id,omitempty"`
+	InvitedBy    string     `json:"invited_by"`
+	ExpiresAt    time.Time  `json:"expires_at"`
+	AcceptedAt   *time.Time `json:"accepted_at,omitempty"`
+	RevokedAt    *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+	// Token is only returned once, when the invitation is created.
+	Token string `json:"token,omitempty"`
+}
+
+func ToInvitationResponse(invitation *db.Invitation, token string) InvitationResponse {
+	resp := InvitationResponse{
+		ID:        invitation.ID.String(),
+		Email:     invitation.Email,
+		Role:      string(invitation.Role),
+		InvitedBy: invitation.InvitedBy.String(),
+		ExpiresAt: invitation.ExpiresAt.Time,
+		CreatedAt: invitation.CreatedAt.Time,
+		Token:     token,
+	}
+	if invitation.RestaurantID.Valid {
+		resp.RestaurantID = invitation.RestaurantID.String()
+	}
+	if invitation.AcceptedAt.Valid {
+		resp.AcceptedAt = &invitation.AcceptedAt.Time
+	}
+	if invitation.RevokedAt.Valid {
+		resp.RevokedAt = &invitation.RevokedAt.Time
+	}
+	return resp
+}
+
+func ToInvitationsResponse(invitations []db.Invitation) []InvitationResponse {
+	resp := make([]InvitationResponse, 0, len(invitations))
+	for i := range invitations {
+		resp = append(resp, ToInvitationResponse(&invitations[i], ""))
+	}
+	return resp
+}
@@ -0,0 +1,37 @@
+package response
+
+import (
+	"github.com/goodfoodcesi/auth-api/infrastructure/database/sqlc"
+	"time"
+)
+
+type MembershipResponse struct {
+	ID           string    `json:"id"`
+	UserID       string    `json:"user_id"`
+	RestaurantID string    `json:"restaurant_id"`
+	Role         string    `json:"role"`
+	CreatedBy    string    `json:"created_by,omitempty"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+func ToMembershipResponse(membership *db.Membership) MembershipResponse {
+	resp := MembershipResponse{
+		ID:           membership.ID.String(),
+		UserID:       membership.UserID.String(),
+		RestaurantID: membership.RestaurantID.String(),
+		Role:         string(membership.Role),
+		CreatedAt:    membership.CreatedAt.Time,
+	}
+	if membership.CreatedBy.Valid {
+		resp.CreatedBy = membership.CreatedBy.String()
+	}
+	return resp
+}
+
+func ToMembershipsResponse(memberships []db.Membership) []MembershipResponse {
+	resp := make([]MembershipResponse, 0, len(memberships))
+	for i := range memberships {
+		resp = append(resp, ToMembershipResponse(&memberships[i]))
+	}
+	return resp
+}
@@ -0,0 +1,24 @@
+package response
+
+import "github.com/goodfoodcesi/auth-api/infrastructure/database/sqlc"
+
+type PermissionResponse struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+type RolePermissionsResponse struct {
+	Role        string   `json:"role"`
+	Permissions []string `json:"permissions"`
+}
+
+func ToPermissionsResponse(permissions []db.Permission) []PermissionResponse {
+	resp := make([]PermissionResponse, 0, len(permissions))
+	for _, permission := range permissions {
+		resp = append(resp, PermissionResponse{
+			Name:        permission.Name,
+			Description: permission.Description,
+		})
+	}
+	return resp
+}
@@ -6,23 +6,53 @@ import (
 )
 
 type UserResponse struct {
-	ID        string    `json:"id"`
-	Email     string    `json:"email"`
-	FirstName string    `json:"first_name"`
-	LastName  string    `json:"last_name"`
-	Role      string    `json:"role"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID            string    `json:"id"`
+	Email         string    `json:"email"`
+	FirstName     string    `json:"first_name"`
+	LastName      string    `json:"last_name"`
+	Role          string    `json:"role"`
+	PhoneNumber   string    `json:"phone_number,omitempty"`
+	PhoneVerified bool      `json:"phone_verified"`
+	Locale        string    `json:"locale"`
+	Timezone      string    `json:"timezone"`
+	AvatarURL     string    `json:"avatar_url,omitempty"`
+	Status        string    `json:"status"`
+	StatusReason  string    `json:"status_reason,omitempty"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+type UserListResponse struct {
+	Data       []UserResponse `json:"data"`
+	NextCursor string         `json:"next_cursor,omitempty"`
 }
 
 func ToUserResponse(user *db.User) UserResponse {
 	return UserResponse{
-		ID:        user.ID.String(),
-		Email:     user.Email,
-		FirstName: user.Firstname,
-		LastName:  user.Lastname,
-		Role:      string(user.Role),
-		CreatedAt: user.CreatedAt.Time,
-		UpdatedAt: user.UpdatedAt.Time,
+		ID:            user.ID.String(),
+		Email:         user.Email,
+		FirstName:     user.Firstname,
+		LastName:      user.Lastname,
+		Role:          string(user.Role),
+		PhoneNumber:   user.PhoneNumber.String,
+		PhoneVerified: user.PhoneVerifiedAt.Valid,
+		Locale:        user.Locale,
+		Timezone:      user.Timezone,
+		AvatarURL:     user.AvatarUrl.String,
+		Status:        string(user.Status),
+		StatusReason:  user.StatusReason.String,
+		CreatedAt:     user.CreatedAt.Time,
+		UpdatedAt:     user.UpdatedAt.Time,
+	}
+}
+
+func ToUserListResponse(users []db.User, nextCursor string) UserListResponse {
+	data := make([]UserResponse, 0, len(users))
+	for i := range users {
+		data = append(data, ToUserResponse(&users[i]))
+	}
+	return UserListResponse{
+		Data:       data,
+		NextCursor: nextCursor,
 	}
 }
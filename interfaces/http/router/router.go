@@ -1,7 +1,10 @@
 package router
 
 import (
+	"github.com/goodfoodcesi/auth-api/authz"
+	"github.com/goodfoodcesi/auth-api/domain/auth"
 	"github.com/goodfoodcesi/auth-api/interfaces/http/response"
+	"net"
 	"net/http"
 	"time"
 
@@ -9,6 +12,7 @@ import (
 	"github.com/go-chi/chi/v5/middleware"
 	"github.com/go-chi/cors"
 	"github.com/goodfoodcesi/auth-api/infrastructure/jwt"
+	"github.com/goodfoodcesi/auth-api/interfaces/http/cookie"
 	"github.com/goodfoodcesi/auth-api/interfaces/http/handler"
 	customMiddleware "github.com/goodfoodcesi/auth-api/interfaces/http/middleware"
 	"go.uber.org/zap"
@@ -16,13 +20,33 @@ import (
 
 func NewRouter(
 	userHandler *handler.UserHandler,
+	authHandler *handler.AuthHandler,
+	addressHandler *handler.AddressHandler,
+	phoneLoginHandler *handler.PhoneLoginHandler,
+	adminUserHandler *handler.AdminUserHandler,
+	impersonationHandler *handler.ImpersonationHandler,
+	invitationHandler *handler.InvitationHandler,
+	driverHandler *handler.DriverHandler,
+	membershipHandler *handler.MembershipHandler,
+	permissionHandler *handler.PermissionHandler,
+	authzHandler *handler.AuthzHandler,
+	oauthHandler *handler.OAuthHandler,
+	apiKeyHandler *handler.APIKeyHandler,
+	healthHandler *handler.HealthHandler,
+	engine *authz.Engine,
 	logger *zap.Logger,
 	tokenManager *jwt.TokenManager,
+	apiKeys customMiddleware.APIKeyAuthenticator,
+	accounts customMiddleware.AccountChecker,
+	auditor customMiddleware.ImpersonationAuditor,
+	allowedOrigins []string,
+	trustedProxies []*net.IPNet,
 ) *chi.Mux {
 	r := chi.NewRouter()
 
 	r.Use(middleware.RequestID)
-	r.Use(middleware.RealIP)
+	r.Use(customMiddleware.RequestID)
+	r.Use(customMiddleware.RealIP(trustedProxies))
 	r.Use(middleware.Recoverer)
 	r.Use(customMiddleware.LoggerMiddleware(logger))
 	r.Use(middleware.Timeout(60 * time.Second))
@@ -30,12 +54,18 @@ func NewRouter(
 	//rateLimiter := customMiddleware.NewRateLimiter(100, 100)
 	//r.Use(rateLimiter.Middleware)
 
+	// Cookies are only sent cross-origin to the origins listed explicitly,
+	// never to any origin.
+	credentials := len(allowedOrigins) > 0
+	if !credentials {
+		allowedOrigins = []string{"*"}
+	}
 	r.Use(cors.Handler(cors.Options{
-		AllowedOrigins:   []string{"*"},
+		AllowedOrigins:   allowedOrigins,
 		AllowedMethods:   []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type"},
-		ExposedHeaders:   []string{"Link"},
-		AllowCredentials: true,
+		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "If-Match", cookie.CSRFHeader},
+		ExposedHeaders:   []string{"Link", "ETag", "X-Impersonated-By"},
+		AllowCredentials: credentials,
 		MaxAge:           300,
 	}))
 
@@ -50,21 +80,131 @@ func NewRouter(
 		// Routes publiques
 		r.Group(func(r chi.Router) {
 			r.Post("/register", userHandler.Register)
+			r.Post("/register/invite", invitationHandler.Redeem)
 			r.Post("/login", userHandler.Login)
+			r.Post("/refresh", authHandler.RefreshToken)
+			r.Post("/logout", authHandler.Logout)
+			r.Get("/csrf", authHandler.CSRF)
+			r.Post("/login/phone", phoneLoginHandler.RequestCode)
+			r.Post("/login/phone/verify", phoneLoginHandler.VerifyCode)
+			r.Post("/authz/check", authzHandler.Check)
+			r.Post("/oauth/token", oauthHandler.Token)
 			r.Get("/health", func(w http.ResponseWriter, r *http.Request) {
 				response.JSON(w, http.StatusOK, map[string]string{"status": "ok"})
 			})
+			r.Get("/ready", healthHandler.Ready)
 		})
 
 		// Routes protégées
 		r.Group(func(r chi.Router) {
-			r.Use(customMiddleware.AuthMiddleware(logger, tokenManager))
+			r.Use(customMiddleware.AuthMiddleware(logger, tokenManager, apiKeys, accounts, auditor))
 
 			// Routes utilisateur
-			r.Get("/me", userHandler.GetProfile)
-			r.Put("/me", userHandler.Update)
+			r.Group(func(r chi.Router) {
+				r.Use(customMiddleware.RequireKind(auth.KindUser))
+
+				r.Get("/me", userHandler.GetProfile)
+				r.Put("/me", userHandler.Update)
+				r.Put("/me/password", userHandler.ChangePassword)
+
+				r.Route("/me/addresses", func(r chi.Router) {
+					r.Get("/", addressHandler.List)
+					r.Post("/", addressHandler.Create)
+					r.Put("/{addressID}", addressHandler.Update)
+					r.Delete("/{addressID}", addressHandler.Delete)
+				})
+
+				r.Get("/me/driver-application", driverHandler.GetMine)
+				r.Put("/me/driver-application", driverHandler.Submit)
+
+				r.Get("/me/memberships", membershipHandler.ListMine)
+				r.Post("/token/restaurant", authHandler.SwitchRestaurant)
+			})
+
+			r.Route("/restaurants/{restaurantID}/members", func(r chi.Router) {
+				r.Use(customMiddleware.RequirePermission("restaurant:members"))
+
+				r.With(customMiddleware.Authorize(engine, "members:list", restaurantResource)).Get("/", membershipHandler.List)
+				r.With(customMiddleware.Authorize(engine, "members:manage", restaurantResource)).Put("/{userID}", membershipHandler.Save)
+				r.With(customMiddleware.Authorize(engine, "members:manage", restaurantResource)).Delete("/{userID}", membershipHandler.Remove)
+			})
+
+			r.Route("/restaurants/{restaurantID}/api-keys", func(r chi.Router) {
+				r.Use(customMiddleware.RequirePermission("api-keys:manage"))
+				r.Use(customMiddleware.Authorize(engine, "api-keys:manage", restaurantResource))
+
+				r.Get("/", apiKeyHandler.List)
+				r.Post("/", apiKeyHandler.Create)
+				r.Delete("/{keyID}", apiKeyHandler.Revoke)
+			})
+		})
+
+		// Routes d'administration
+		r.Route("/admin", func(r chi.Router) {
+			r.Use(customMiddleware.AuthMiddleware(logger, tokenManager, apiKeys, accounts, auditor))
+			// Administration is for staff signed in themselves, never for an
+			// API key of an admin, which could otherwise mint impersonation
+			// tokens.
+			r.Use(customMiddleware.RequireKind(auth.KindUser))
+
+			r.Route("/users", func(r chi.Router) {
+				r.Group(func(r chi.Router) {
+					r.Use(customMiddleware.RequirePermission("users:read"))
+
+					r.Get("/", adminUserHandler.List)
+					r.Get("/{userID}", adminUserHandler.Get)
+				})
+
+				r.Group(func(r chi.Router) {
+					r.Use(customMiddleware.RequirePermission("users:manage"))
+
+					r.Put("/{userID}/role", adminUserHandler.UpdateRole)
+					r.Post("/{userID}/suspend", adminUserHandler.Suspend)
+					r.Post("/{userID}/reactivate", adminUserHandler.Reactivate)
+					r.Post("/{userID}/ban", adminUserHandler.Ban)
+					r.Delete("/{userID}", adminUserHandler.Delete)
+				})
+
+				r.With(customMiddleware.RequirePermission("users:impersonate")).Post("/{userID}/impersonate", impersonationHandler.Impersonate)
+			})
+
+			r.Route("/invitations", func(r chi.Router) {
+				r.Use(customMiddleware.RequirePermission("invitations:manage"))
+
+				r.Get("/", invitationHandler.List)
+				r.Post("/", invitationHandler.Create)
+				r.Delete("/{invitationID}", invitationHandler.Revoke)
+			})
+
+			r.Route("/driver-applications", func(r chi.Router) {
+				r.Use(customMiddleware.RequirePermission("drivers:review"))
+
+				r.Get("/", driverHandler.List)
+				r.Get("/{applicationID}", driverHandler.Get)
+				r.Post("/{applicationID}/review", driverHandler.StartReview)
+				r.Post("/{applicationID}/approve", driverHandler.Approve)
+				r.Post("/{applicationID}/reject", driverHandler.Reject)
+			})
+
+			r.Group(func(r chi.Router) {
+				r.Use(customMiddleware.RequirePermission("permissions:manage"))
+
+				r.Get("/permissions", permissionHandler.List)
+				r.Get("/roles", permissionHandler.ListRoles)
+				r.Get("/roles/{role}/permissions", permissionHandler.GetRole)
+				r.Put("/roles/{role}/permissions", permissionHandler.SetRole)
+			})
 		})
 	})
 
 	return r
 }
+
+func restaurantResource(r *http.Request) authz.Resource {
+	restaurantID := chi.URLParam(r, "restaurantID")
+	return authz.Resource{
+		Type:       "restaurant",
+		ID:         restaurantID,
+		Attributes: map[string]interface{}{"restaurant_id": restaurantID},
+	}
+}
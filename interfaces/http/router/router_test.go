@@ -0,0 +1,84 @@
+package router_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/goodfoodcesi/auth-api/authz"
+	"github.com/goodfoodcesi/auth-api/domain/auth"
+	"github.com/goodfoodcesi/auth-api/infrastructure/database/sqlc"
+	"github.com/goodfoodcesi/auth-api/infrastructure/jwt"
+	"github.com/goodfoodcesi/auth-api/interfaces/http/router"
+	"go.uber.org/zap"
+)
+
+// adminKey is an API key created by an admin and scoped to every admin
+// permission.
+type adminKey struct{}
+
+func (adminKey) AuthenticateAPIKey(context.Context, string, string) (*auth.Principal, error) {
+	return &auth.Principal{
+		Subject:     "admin",
+		Kind:        auth.KindAPIKey,
+		Role:        db.UserRoleAdmin,
+		Permissions: []string{"users:read", "users:manage", "users:impersonate", "invitations:manage", "drivers:review", "permissions:manage"},
+	}, nil
+}
+
+type activeAccounts struct{}
+
+func (activeAccounts) IsActive(context.Context, string, time.Time) (bool, error) {
+	return true, nil
+}
+
+type auditor struct{}
+
+func (auditor) RecordImpersonatedRequest(context.Context, string, string, string, string, string) error {
+	return nil
+}
+
+func TestAdminRoutesRefuseAPIKeys(t *testing.T) {
+	policy, err := authz.LoadPolicy("")
+	if err != nil {
+		t.Fatalf("LoadPolicy: %v", err)
+	}
+	tokens := jwt.NewTokenManager("access-secret", "refresh-secret", time.Hour, 24*time.Hour)
+	// The handlers are never reached.
+	r := router.NewRouter(
+		nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil,
+		authz.NewEngine(policy, nil),
+		zap.NewNop(),
+		tokens,
+		adminKey{},
+		activeAccounts{},
+		auditor{},
+		nil,
+		nil,
+	)
+
+	tests := []struct {
+		method string
+		path   string
+	}{
+		{http.MethodPost, "/auth/admin/users/00000000-0000-0000-0000-000000000001/impersonate"},
+		{http.MethodGet, "/auth/admin/users"},
+		{http.MethodPut, "/auth/admin/users/00000000-0000-0000-0000-000000000001/role"},
+		{http.MethodPost, "/auth/admin/invitations"},
+		{http.MethodPut, "/auth/admin/roles/manager/permissions"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.path, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			req.Header.Set("Authorization", "ApiKey gf_admin")
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			if w.Code != http.StatusForbidden {
+				t.Errorf("status = %d, want %d", w.Code, http.StatusForbidden)
+			}
+		})
+	}
+}
@@ -2,21 +2,28 @@ package main
 
 import (
 	"context"
+	"errors"
+	"expvar"
+	"github.com/goodfoodcesi/auth-api/authz"
 	"github.com/goodfoodcesi/auth-api/infrastructure/database/repository"
 	"github.com/goodfoodcesi/auth-api/infrastructure/messaging/rabbitmq"
 	"log"
 	"net/http"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 	"time"
 
 	"github.com/goodfoodcesi/auth-api/crypto"
+	"github.com/goodfoodcesi/auth-api/domain/events"
 	"github.com/goodfoodcesi/auth-api/domain/service"
 	"github.com/goodfoodcesi/auth-api/infrastructure/database"
 	"github.com/goodfoodcesi/auth-api/infrastructure/jwt"
 	"github.com/goodfoodcesi/auth-api/infrastructure/logger"
+	"github.com/goodfoodcesi/auth-api/interfaces/http/cookie"
 	"github.com/goodfoodcesi/auth-api/interfaces/http/handler"
+	customMiddleware "github.com/goodfoodcesi/auth-api/interfaces/http/middleware"
 	"github.com/goodfoodcesi/auth-api/interfaces/http/router"
 	"go.uber.org/zap"
 )
@@ -43,35 +50,150 @@ func main() {
 	}
 	defer db.Close()
 
-	rabbit, err := rabbitmq.NewRabbitMQ(os.Getenv("RABBITMQ_URL"), logger)
+	rabbit, err := rabbitmq.NewRabbitMQ(os.Getenv("RABBITMQ_URL"), events.Schemas, logger)
 	if err != nil {
 		log.Fatal(err)
 	}
 	defer rabbit.Close()
 
-	messagingService, err := service.NewMessagingService(rabbit, logger)
-	if err != nil {
+	if err := rabbit.DeclareTopology(rabbitmq.AuthTopology); err != nil {
 		log.Fatal(err)
 	}
+	messagingService := service.NewMessagingService(rabbit, logger)
 
+	accessTokenTTL := time.Hour * 24
+	refreshTokenTTL := time.Hour * 24 * 30
 	tokenManager := jwt.NewTokenManager(
 		os.Getenv("JWT_ACCESS_SECRET"),
 		os.Getenv("JWT_REFRESH_SECRET"),
-		time.Hour*24,    // Access token TTL
-		time.Hour*24*30, // Refresh token TTL
+		accessTokenTTL,
+		refreshTokenTTL,
 	)
 
+	cookies := cookie.Config{
+		Domain:     os.Getenv("COOKIE_DOMAIN"),
+		Secure:     os.Getenv("COOKIE_INSECURE") != "true",
+		SameSite:   cookie.ParseSameSite(os.Getenv("COOKIE_SAMESITE")),
+		AccessTTL:  accessTokenTTL,
+		RefreshTTL: refreshTokenTTL,
+	}
+
 	userRepo := repository.NewUserRepository(db)
 	passwordManager := crypto.NewPasswordManager(os.Getenv("PASSWORD_SECRET"))
-	userService := service.NewUserService(userRepo, tokenManager, passwordManager, messagingService, logger)
-	userHandler := handler.NewUserHandler(userService, logger)
-	r := router.NewRouter(userHandler, logger, tokenManager)
+	driverApplicationRepo := repository.NewDriverApplicationRepository(db)
+	membershipRepo := repository.NewMembershipRepository(db)
+	permissionRepo := repository.NewPermissionRepository(db)
+	outboxRepo := repository.NewOutboxRepository(db)
+	transactor := repository.NewTransactor(db)
+	userService := service.NewUserService(userRepo, driverApplicationRepo, membershipRepo, permissionRepo, outboxRepo, transactor, tokenManager, passwordManager, logger)
+	userHandler := handler.NewUserHandler(userService, cookies, logger)
+	authService := service.NewAuthService(userService, tokenManager)
+	authHandler := handler.NewAuthHandler(authService, cookies, logger)
+
+	addressRepo := repository.NewAddressRepository(db)
+	addressService := service.NewAddressService(addressRepo, logger)
+	addressHandler := handler.NewAddressHandler(addressService, logger)
+
+	phoneOtpRepo := repository.NewPhoneOtpRepository(db)
+	otpManager := crypto.NewOTPManager(os.Getenv("OTP_SECRET"))
+	phoneLoginService := service.NewPhoneLoginService(userRepo, phoneOtpRepo, userService, otpManager, messagingService, logger)
+	phoneLoginHandler := handler.NewPhoneLoginHandler(phoneLoginService, logger)
+
+	adminUserHandler := handler.NewAdminUserHandler(userService, logger)
+
+	auditRepo := repository.NewAuditRepository(db)
+	auditService := service.NewAuditService(auditRepo, logger)
+	impersonationService := service.NewImpersonationService(userService, auditService, logger)
+	impersonationHandler := handler.NewImpersonationHandler(impersonationService, logger)
+
+	invitationRepo := repository.NewInvitationRepository(db)
+	invitationSigner := crypto.NewTokenSigner(os.Getenv("INVITATION_SECRET"))
+	invitationService := service.NewInvitationService(invitationRepo, membershipRepo, userService, invitationSigner, logger)
+	invitationHandler := handler.NewInvitationHandler(invitationService, logger)
+
+	driverService := service.NewDriverService(driverApplicationRepo, userService, logger)
+	driverHandler := handler.NewDriverHandler(driverService, logger)
+
+	membershipService := service.NewMembershipService(membershipRepo, userRepo, logger)
+	membershipHandler := handler.NewMembershipHandler(membershipService, logger)
+
+	permissionService := service.NewPermissionService(permissionRepo, logger)
+	permissionHandler := handler.NewPermissionHandler(permissionService, logger)
+
+	policy, err := authz.LoadPolicy(os.Getenv("AUTHZ_POLICY_FILE"))
+	if err != nil {
+		logger.Fatal("failed to load authorization policy", zap.Error(err))
+	}
+	authzEngine := authz.NewEngine(policy, authz.NewZapDecisionLog(logger))
+	authzHandler := handler.NewAuthzHandler(authzEngine, tokenManager, userService, logger)
+
+	trustedProxies, err := customMiddleware.ParseTrustedProxies(os.Getenv("TRUSTED_PROXIES"))
+	if err != nil {
+		logger.Fatal("failed to parse TRUSTED_PROXIES", zap.Error(err))
+	}
+
+	serviceClients, err := service.ParseServiceClients(os.Getenv("SERVICE_CLIENTS"))
+	if err != nil {
+		logger.Fatal("failed to parse SERVICE_CLIENTS", zap.Error(err))
+	}
+	tokenExchangeService := service.NewTokenExchangeService(tokenManager, userService, serviceClients, logger)
+	oauthHandler := handler.NewOAuthHandler(tokenExchangeService, logger)
+
+	apiKeyRepo := repository.NewAPIKeyRepository(db)
+	apiKeyService := service.NewAPIKeyService(apiKeyRepo, membershipRepo, userRepo, permissionService, logger)
+	apiKeyHandler := handler.NewAPIKeyHandler(apiKeyService, logger)
+
+	healthHandler := handler.NewHealthHandler(map[string]handler.ReadinessCheck{
+		"postgres": db.Ping,
+		"rabbitmq": rabbit.Ready,
+	}, logger)
+
+	r := router.NewRouter(
+		userHandler,
+		authHandler,
+		addressHandler,
+		phoneLoginHandler,
+		adminUserHandler,
+		impersonationHandler,
+		invitationHandler,
+		driverHandler,
+		membershipHandler,
+		permissionHandler,
+		authzHandler,
+		oauthHandler,
+		apiKeyHandler,
+		healthHandler,
+		authzEngine,
+		logger,
+		tokenManager,
+		apiKeyService,
+		userService,
+		auditService,
+		allowedOrigins(os.Getenv("CORS_ALLOWED_ORIGINS")),
+		trustedProxies,
+	)
 
 	server := &http.Server{
 		Addr:    ":8080",
 		Handler: r,
 	}
 
+	// Outbox and runtime metrics are served on their own listener, which the
+	// Service does not expose.
+	metricsServer := &http.Server{
+		Addr:    metricsAddr(os.Getenv("METRICS_ADDR")),
+		Handler: expvar.Handler(),
+	}
+	go func() {
+		if err := metricsServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logger.Error("metrics server failed", zap.Error(err))
+		}
+	}()
+
+	relayCtx, stopRelay := context.WithCancel(context.Background())
+	defer stopRelay()
+	go service.NewOutboxRelay(outboxRepo, rabbit, logger).Run(relayCtx)
+
 	// Channel pour recevoir les signaux d'interruption
 	stop := make(chan os.Signal, 1)
 	signal.Notify(stop, os.Interrupt, syscall.SIGTERM)
@@ -92,10 +214,35 @@ func main() {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
+	stopRelay()
+
 	// Arrêter le serveur HTTP
 	if err := server.Shutdown(ctx); err != nil {
 		logger.Error("Server forced to shutdown:", zap.Error(err))
 	}
+	if err := metricsServer.Shutdown(ctx); err != nil {
+		logger.Error("metrics server forced to shutdown", zap.Error(err))
+	}
 
 	logger.Info("Server gracefully stopped")
 }
+
+// metricsAddr defaults to the loopback interface, so /debug/vars is only
+// reachable from inside the pod unless METRICS_ADDR says otherwise.
+func metricsAddr(value string) string {
+	if value == "" {
+		return "localhost:9090"
+	}
+	return value
+}
+
+// allowedOrigins splits a comma-separated CORS_ALLOWED_ORIGINS.
+func allowedOrigins(value string) []string {
+	var origins []string
+	for _, origin := range strings.Split(value, ",") {
+		if origin = strings.TrimSpace(origin); origin != "" {
+			origins = append(origins, origin)
+		}
+	}
+	return origins
+}